@@ -5,6 +5,8 @@ package main
 import (
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -31,6 +33,13 @@ func main() {
 	}
 	defer database.Close()
 
+	// Entri ledger cuti otomatis ganda harus dibersihkan sebelum unique index periode dibuat
+	if removed, err := repository.NewLeaveBalanceRepository(database.DB).DedupeLedgerPeriods(); err != nil {
+		log.Printf("⚠️ Failed to dedupe leave ledger periods: %v", err)
+	} else if removed > 0 {
+		log.Printf("✅ Removed %d duplicate leave ledger entries", removed)
+	}
+
 	// Run Database Migrations
 	log.Println("🔄 Running database migrations...")
 	if err := database.DB.AutoMigrate(
@@ -41,9 +50,35 @@ func main() {
 		&models.PasswordReset{},
 		&models.Transaction{},
 		&models.BotMessage{},
-		&models.Holiday{},     // Holiday model
-		&models.Leave{},       // Leave model
-		&models.ChatMessage{}, // ChatMessage model
+		&models.UserDevice{},             // Push notification devices
+		&models.NotificationOutbox{},     // Durable notification delivery
+		&models.NotificationSettings{},   // Notification time zone & quiet hours
+		&models.NotificationPreference{}, // Per-type notification preferences
+		&models.WebPushSubscription{},    // Browser push subscriptions
+		&models.NotificationWebhook{},    // Outgoing notification webhooks
+		&models.TaskReminderLog{},        // Sent task reminders (exactly-once)
+		&models.TaskSnooze{},             // Snoozed task reminders
+		&models.SchedulerLease{},         // Scheduler leader election
+		&models.JobRun{},                 // Scheduler job-run history
+		&models.ScheduledJobState{},      // Scheduler job pause state
+		&models.DailyBriefingLog{},       // Sent daily agenda / digest
+		&models.OverduePolicy{},          // Overdue task policies
+		&models.OverdueActionLog{},       // Actions taken by the overdue job
+		&models.Holiday{},                // Holiday model
+		&models.Leave{},                  // Leave model
+		&models.ChatMessage{},            // ChatMessage model
+		// Leave approval workflow
+		&models.LeaveApprover{},
+		&models.LeaveTransition{},
+		&models.LeavePolicy{},
+		&models.LeaveLedgerEntry{},
+		// Workspaces
+		&models.Workspace{},
+		&models.WorkspaceMember{},
+		&models.WorkspaceInvitation{},
+		&models.TaskAssignee{},
+		&models.TaskComment{},
+		&models.TaskActivity{},
 		// Security models (Keamanan Basis Data)
 		&models.AuditLog{},
 		&models.SecurityEvent{},
@@ -52,6 +87,9 @@ func main() {
 		&models.PasswordHistory{},
 		// Email Verification model
 		&models.EmailVerification{},
+		// Productivity reports
+		&models.ProductivityReport{},
+		&models.ReportSetting{},
 	); err != nil {
 		log.Fatal("Failed to run migrations:", err)
 	}
@@ -68,8 +106,44 @@ func main() {
 	botMessageRepo := repository.NewBotMessageRepository(database.DB)
 	holidayRepo := repository.NewHolidayRepository(database.DB)
 	leaveRepo := repository.NewLeaveRepository(database.DB)
+	leaveBalanceRepo := repository.NewLeaveBalanceRepository(database.DB)
 	chatRepo := repository.NewChatRepository(database.DB)
 	auditRepo := repository.NewAuditRepository(database.DB) // Security: Audit Repository
+	reportRepo := repository.NewReportRepository(database.DB)
+	workspaceRepo := repository.NewWorkspaceRepository(database.DB)
+	taskCommentRepo := repository.NewTaskCommentRepository(database.DB)
+	taskActivityRepo := repository.NewTaskActivityRepository(database.DB)
+	deviceRepo := repository.NewDeviceRepository(database.DB)
+	notificationOutboxRepo := repository.NewNotificationOutboxRepository(database.DB)
+	taskReminderRepo := repository.NewTaskReminderRepository(database.DB)
+	taskSnoozeRepo := repository.NewTaskSnoozeRepository(database.DB)
+	dailyBriefingRepo := repository.NewDailyBriefingRepository(database.DB)
+	overdueRepo := repository.NewOverdueRepository(database.DB)
+	schedulerRepo := repository.NewSchedulerRepository(database.DB)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(database.DB)
+	notificationEndpointRepo := repository.NewNotificationEndpointRepository(database.DB)
+
+	// Pindahkan FCM token lama (users.fcm_token) ke registry perangkat
+	if migrated, err := deviceRepo.MigrateLegacyTokens(); err != nil {
+		log.Printf("⚠️ Failed to migrate legacy FCM tokens: %v", err)
+	} else if migrated > 0 {
+		log.Printf("✅ Migrated %d legacy FCM token(s) to user_devices", migrated)
+	}
+
+	// Admin bootstrap: users.role tidak bisa diubah lewat API
+	if config.AppConfig.AdminEmails != "" {
+		var adminEmails []string
+		for _, email := range strings.Split(config.AppConfig.AdminEmails, ",") {
+			if email = strings.TrimSpace(email); email != "" {
+				adminEmails = append(adminEmails, email)
+			}
+		}
+		if promoted, err := userRepo.PromoteToAdmin(adminEmails); err != nil {
+			log.Printf("⚠️ Failed to promote admin users: %v", err)
+		} else if promoted > 0 {
+			log.Printf("✅ Promoted %d user(s) to admin", promoted)
+		}
+	}
 
 	// Initialize security services first (needed for middleware)
 	auditService := services.NewAuditService(auditRepo)
@@ -102,17 +176,66 @@ func main() {
 	}))
 
 	// Initialize services
+	// Real-time event broker (in-process; bisa diganti implementasi lain, mis. Redis)
+	realtimeBroker := services.NewInMemoryEventBroker()
+
 	authService := services.NewAuthService(userRepo, categoryRepo, passwordResetRepo, emailVerificationRepo)
-	taskService := services.NewTaskService(taskRepo, categoryRepo)
-	categoryService := services.NewCategoryService(categoryRepo, taskRepo)
+	emailService := services.NewEmailService()
+	workspaceService := services.NewWorkspaceService(workspaceRepo, userRepo, emailService)
+	categoryService := services.NewCategoryService(categoryRepo, taskRepo, workspaceService)
 	profileService := services.NewProfileService(userRepo, taskRepo, categoryRepo)
 	calendarService := services.NewCalendarService(taskRepo)
 	subscriptionService := services.NewSubscriptionService(userRepo, subscriptionRepo, database.DB)
 	workloadService := services.NewWorkloadService(taskRepo)
-	botMessageService := services.NewBotMessageService(botMessageRepo)
-	paymentService := services.NewPaymentService(transactionRepo, userRepo, subscriptionService, botMessageService)
-	holidayService := services.NewHolidayService(holidayRepo)
-	leaveService := services.NewLeaveService(leaveRepo)
+	botMessageService := services.NewBotMessageService(botMessageRepo, realtimeBroker)
+	paymentService := services.NewPaymentService(transactionRepo, userRepo, subscriptionService, botMessageService, realtimeBroker)
+	holidayService := services.NewHolidayService(holidayRepo, userRepo)
+
+	// Initialize notification channels (FCM, email, in-app, Web Push, webhook)
+	notificationPreferenceService := services.NewNotificationPreferenceService(notificationPreferenceRepo)
+	analyticsService := services.NewAnalyticsService(taskRepo, notificationPreferenceService)
+	fcmNotifier, err := services.NewFCMNotifier(deviceRepo, config.AppConfig.FirebaseProjectID, config.AppConfig.FirebaseCredentialsFile)
+	if err != nil {
+		log.Fatalf("Failed to initialize FCM notifier: %v", err)
+	}
+	webPushNotifier, err := services.NewWebPushNotifier(notificationEndpointRepo, config.AppConfig.VAPIDPrivateKey, config.AppConfig.VAPIDSubject)
+	if err != nil {
+		log.Fatalf("Failed to initialize Web Push notifier: %v", err)
+	}
+	notificationDispatcher := services.NewNotificationDispatcher(
+		notificationPreferenceService,
+		fcmNotifier,
+		services.NewEmailNotifier(userRepo, emailService),
+		services.NewInAppNotifier(botMessageService),
+		webPushNotifier,
+		services.NewWebhookNotifier(notificationEndpointRepo),
+	)
+	notificationDispatcher.LogAvailableChannels()
+	notificationService := services.NewNotificationService(
+		deviceRepo,
+		notificationEndpointRepo,
+		notificationOutboxRepo,
+		notificationPreferenceService,
+		notificationDispatcher,
+	)
+
+	leaveBalanceService := services.NewLeaveBalanceService(leaveBalanceRepo, leaveRepo, userRepo, workspaceRepo, holidayService, services.GetAccessControlService())
+	leaveService := services.NewLeaveService(leaveRepo, userRepo, workspaceRepo, holidayService, leaveBalanceService, notificationService, notificationPreferenceService, botMessageService, auditService, realtimeBroker)
+	workCalendarService := services.NewWorkCalendarService(holidayService, leaveService, userRepo)
+	taskActivityService := services.NewTaskActivityService(taskActivityRepo, userRepo, auditService)
+	taskService := services.NewTaskService(taskRepo, categoryRepo, userRepo, workspaceService, workCalendarService, taskActivityService, notificationService, botMessageService, realtimeBroker)
+	taskSnoozeService := services.NewTaskSnoozeService(taskSnoozeRepo, taskRepo, taskService, notificationService, notificationPreferenceService)
+	taskCommentService := services.NewTaskCommentService(taskCommentRepo, userRepo, taskService, workspaceService, notificationService)
+	teamWorkloadService := services.NewTeamWorkloadService(
+		workspaceRepo,
+		workspaceService,
+		workloadService,
+		taskRepo,
+		leaveRepo,
+		holidayService,
+		services.GetAccessControlService(),
+	)
+	leaveImpactService := services.NewLeaveImpactService(leaveRepo, taskRepo, workCalendarService, taskActivityService, botMessageService, realtimeBroker)
 	aiService := services.NewAIService(chatRepo, taskRepo, userRepo, config.AppConfig.GeminiAPIKey)
 	oauthService := services.NewOAuthService(
 		config.AppConfig.GoogleClientID,
@@ -120,49 +243,95 @@ func main() {
 		config.AppConfig.GoogleRedirectURL,
 	)
 	weatherService := services.NewWeatherService(config.AppConfig.WeatherAPIKey)
-
-	// Initialize notification service (Firebase FCM)
-	notificationService, err := services.NewNotificationService(
+	reportService := services.NewReportService(
+		reportRepo,
+		taskRepo,
 		userRepo,
-		config.AppConfig.FirebaseProjectID,
-		config.AppConfig.FirebaseCredentialsFile,
+		holidayService,
+		workloadService,
+		botMessageService,
+		emailService,
 	)
-	if err != nil {
-		log.Fatalf("Failed to initialize NotificationService: %v", err)
+
+	// Start notification outbox worker (durable delivery with retries)
+	notificationService.StartOutboxWorker(config.AppConfig.NotificationWorkers)
+	defer notificationService.StopOutboxWorker()
+
+	// Leader election: dengan beberapa replica hanya leader yang menjalankan job terjadwal
+	schedulerLeader := services.NewLeaderElector(
+		schedulerRepo,
+		services.SchedulerLeaseName,
+		config.AppConfig.SchedulerInstanceID,
+		time.Duration(config.AppConfig.SchedulerLeaseTTLSeconds)*time.Second,
+	)
+	schedulerLeader.Start()
+	defer schedulerLeader.Stop()
+	jobRunService := services.NewJobRunService(schedulerRepo, schedulerLeader.InstanceID())
+
+	// Job registry: semua job terjadwal (cron, jitter, timeout, concurrency, pause)
+	schedulerLocation := time.Local
+	if config.AppConfig.SchedulerTimeZone != "" {
+		if schedulerLocation, err = time.LoadLocation(config.AppConfig.SchedulerTimeZone); err != nil {
+			log.Fatalf("Invalid SCHEDULER_TIMEZONE: %v", err)
+		}
 	}
+	jobRegistry := services.NewJobRegistry(schedulerRepo, schedulerLeader, jobRunService, schedulerLocation)
 
 	// Initialize scheduler service for background notifications
+	dailyBriefingService := services.NewDailyBriefingService(taskRepo, holidayService, leaveService)
+	overdueService := services.NewOverdueService(overdueRepo, taskRepo, categoryRepo, notificationService, notificationPreferenceService, taskActivityService, realtimeBroker)
 	schedulerService := services.NewSchedulerService(
 		database.DB,
 		userRepo,
 		taskRepo,
+		taskReminderRepo,
+		dailyBriefingRepo,
 		notificationService,
+		notificationPreferenceService,
+		dailyBriefingService,
+		overdueService,
+		taskSnoozeService,
 		weatherService,
+		reportService,
+		holidayService,
+		leaveService,
+		jobRegistry,
 	)
 	schedulerService.Start()
-	defer schedulerService.Stop()
 
 	// Initialize security scheduler service (Keamanan Basis Data - Phase 4: Monitoring)
-	securitySchedulerService := services.NewSecuritySchedulerService(database.DB)
-	securitySchedulerService.Start()
-	defer securitySchedulerService.Stop()
+	securitySchedulerService := services.GetSecuritySchedulerService()
+	securitySchedulerService.SetJobRegistry(jobRegistry)
+	if err := securitySchedulerService.Start(); err != nil {
+		log.Printf("⚠️ Failed to start security scheduler: %v", err)
+	}
+
+	jobRegistry.Start()
+	defer jobRegistry.Stop()
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	taskHandler := handlers.NewTaskHandler(taskService)
+	taskCommentHandler := handlers.NewTaskCommentHandler(taskCommentService)
+	overdueHandler := handlers.NewOverdueHandler(overdueService)
+	taskSnoozeHandler := handlers.NewTaskSnoozeHandler(taskSnoozeService)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	profileHandler := handlers.NewProfileHandler(profileService)
 	calendarHandler := handlers.NewCalendarHandler(calendarService)
 	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService)
 	workloadHandler := handlers.NewWorkloadHandler(workloadService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
 	botMessageHandler := handlers.NewBotMessageHandler(botMessageService)
+	realtimeHandler := handlers.NewRealtimeHandler(realtimeBroker)
 	holidayHandler := handlers.NewHolidayHandler(holidayService)
-	leaveHandler := handlers.NewLeaveHandler(leaveService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService, taskService, categoryService, teamWorkloadService)
+	leaveHandler := handlers.NewLeaveHandler(leaveService, leaveBalanceService, leaveImpactService)
 	chatHandler := handlers.NewChatHandler(aiService)
 	oauthHandler := handlers.NewOAuthHandler(oauthService, authService)
 	weatherHandler := handlers.NewWeatherHandler(weatherService)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	reportHandler := handlers.NewReportHandler(reportService)
 	securityHandler := handlers.NewSecurityHandler(auditService) // Security: Handler
 
 	// MFA Service & Handler (Minggu 3: Multi-Factor Authentication)
@@ -170,7 +339,7 @@ func main() {
 	mfaHandler := handlers.NewMFAHandler(mfaService)
 
 	// Monitoring Handler (Keamanan Basis Data - Phase 4: Monitoring & Maintenance)
-	monitoringHandler := handlers.NewMonitoringHandler()
+	monitoringHandler := handlers.NewMonitoringHandler(jobRunService, jobRegistry)
 
 	// API Routes
 	api := app.Group("/api")
@@ -227,15 +396,34 @@ func main() {
 	profile.Post("/change-password", authHandler.ChangePassword)
 	profile.Get("/work-hours", profileHandler.GetWorkHours)
 	profile.Put("/work-hours", profileHandler.UpdateWorkHours)
+	profile.Get("/deadline-policy", profileHandler.GetDeadlinePolicy)
+	profile.Put("/deadline-policy", profileHandler.UpdateDeadlinePolicy)
 
 	// Protected routes - Tasks
 	tasks := api.Group("/tasks", middleware.AuthMiddleware())
 	tasks.Post("/", taskHandler.CreateTask)
 	tasks.Get("/", taskHandler.GetTasks)
+	tasks.Get("/assigned-to-me", taskHandler.GetAssignedToMe)
+	tasks.Get("/assigned-by-me", taskHandler.GetAssignedByMe)
+	tasks.Patch("/bulk", taskHandler.BulkUpdateTasks)
+	tasks.Get("/overdue", overdueHandler.GetOverdueTasks)
+	tasks.Get("/overdue/policies", overdueHandler.GetPolicies)
+	tasks.Put("/overdue/policies", overdueHandler.UpdatePolicy)
+	tasks.Delete("/overdue/policies", overdueHandler.DeletePolicy)
+	tasks.Get("/overdue/log", overdueHandler.GetActionLog)
 	tasks.Get("/:id", taskHandler.GetTaskByID)
 	tasks.Put("/:id", taskHandler.UpdateTask)
 	tasks.Delete("/:id", taskHandler.DeleteTask)
 	tasks.Patch("/:id/toggle", taskHandler.ToggleComplete)
+	tasks.Put("/:id/assignees", taskHandler.SetAssignees)
+	tasks.Get("/:id/activity", taskHandler.GetActivity)
+	tasks.Get("/:id/snooze", taskSnoozeHandler.GetSnooze)
+	tasks.Post("/:id/snooze", taskSnoozeHandler.Snooze)
+	tasks.Delete("/:id/snooze", taskSnoozeHandler.Unsnooze)
+	tasks.Get("/:id/comments", taskCommentHandler.GetComments)
+	tasks.Post("/:id/comments", taskCommentHandler.CreateComment)
+	tasks.Put("/:id/comments/:commentId", taskCommentHandler.UpdateComment)
+	tasks.Delete("/:id/comments/:commentId", taskCommentHandler.DeleteComment)
 
 	// Protected routes - Categories
 	categories := api.Group("/categories", middleware.AuthMiddleware())
@@ -244,6 +432,26 @@ func main() {
 	categories.Put("/:id", categoryHandler.UpdateCategory)
 	categories.Delete("/:id", categoryHandler.DeleteCategory)
 
+	// Protected routes - Workspaces (shared tasks & categories)
+	workspaces := api.Group("/workspaces", middleware.AuthMiddleware())
+	workspaces.Get("/", workspaceHandler.GetWorkspaces)
+	workspaces.Post("/", workspaceHandler.CreateWorkspace)
+	workspaces.Get("/invitations", workspaceHandler.GetMyInvitations)
+	workspaces.Post("/invitations/:invitationId/accept", workspaceHandler.AcceptInvitation)
+	workspaces.Post("/invitations/:invitationId/decline", workspaceHandler.DeclineInvitation)
+	workspaces.Get("/:id", workspaceHandler.GetWorkspace)
+	workspaces.Put("/:id", workspaceHandler.UpdateWorkspace)
+	workspaces.Delete("/:id", workspaceHandler.DeleteWorkspace)
+	workspaces.Get("/:id/members", workspaceHandler.GetMembers)
+	workspaces.Put("/:id/members/:userId", workspaceHandler.UpdateMemberRole)
+	workspaces.Delete("/:id/members/:userId", workspaceHandler.RemoveMember)
+	workspaces.Get("/:id/invitations", workspaceHandler.GetInvitations)
+	workspaces.Post("/:id/invitations", workspaceHandler.InviteMember)
+	workspaces.Delete("/:id/invitations/:invitationId", workspaceHandler.RevokeInvitation)
+	workspaces.Get("/:id/tasks", workspaceHandler.GetTasks)
+	workspaces.Get("/:id/categories", workspaceHandler.GetCategories)
+	workspaces.Get("/:id/workload", workspaceHandler.GetTeamWorkload)
+
 	// Protected routes - Calendar
 	calendar := api.Group("/calendar", middleware.AuthMiddleware())
 	calendar.Get("/today", calendarHandler.GetTodayTasks)
@@ -271,6 +479,22 @@ func main() {
 	workload := api.Group("/workload", middleware.AuthMiddleware())
 	workload.Get("/", workloadHandler.GetWorkload)
 
+	// Protected routes - Productivity Analytics
+	analytics := api.Group("/analytics", middleware.AuthMiddleware())
+	analytics.Get("/heatmap", analyticsHandler.GetHeatmap)
+	analytics.Get("/distribution", analyticsHandler.GetDistribution)
+	analytics.Get("/completion-time", analyticsHandler.GetCompletionTime)
+	analytics.Get("/lateness", analyticsHandler.GetLateness)
+
+	// Protected routes - Productivity Reports
+	reports := api.Group("/reports", middleware.AuthMiddleware())
+	reports.Get("/", reportHandler.GetReports)
+	reports.Post("/generate", reportHandler.GenerateReport)
+	reports.Get("/settings", reportHandler.GetSettings)
+	reports.Put("/settings", reportHandler.UpdateSettings)
+	reports.Get("/:id", reportHandler.GetReport)
+	reports.Get("/:id/download", reportHandler.DownloadReport)
+
 	// Protected routes - Bot Messages
 	messages := api.Group("/messages", middleware.AuthMiddleware())
 	messages.Get("/", botMessageHandler.GetMessages)
@@ -280,19 +504,42 @@ func main() {
 	messages.Patch("/read-all", botMessageHandler.MarkAllAsRead)
 	messages.Delete("/:id", botMessageHandler.DeleteMessage)
 
+	// Protected routes - Real-time events (SSE)
+	events := api.Group("/events", middleware.AuthMiddleware())
+	events.Get("/stream", realtimeHandler.Stream)
+
 	// Protected routes - Holidays
 	holidays := api.Group("/holidays", middleware.AuthMiddleware())
 	holidays.Get("/", holidayHandler.GetHolidays)
 	holidays.Post("/personal", holidayHandler.CreatePersonalHoliday)
+	holidays.Put("/personal/:id", holidayHandler.UpdatePersonalHoliday)
 	holidays.Delete("/personal/:id", holidayHandler.DeletePersonalHoliday)
+	holidays.Get("/regions", holidayHandler.GetRegions)
+	holidays.Put("/region", holidayHandler.UpdateRegion)
+	// Import data holiday: role admin / superadmin (users.role, lihat ADMIN_EMAILS)
+	holidays.Post("/import", middleware.AccessControlMiddleware(services.PermissionHolidayManage), holidayHandler.ImportHolidays)
 
 	// Protected routes - Leaves
 	leaves := api.Group("/leaves", middleware.AuthMiddleware())
 	leaves.Get("/", leaveHandler.GetLeaves)
 	leaves.Get("/upcoming/count", leaveHandler.GetUpcomingCount)
+	leaves.Get("/approvals", leaveHandler.GetApprovalInbox)
+	leaves.Get("/balance", leaveHandler.GetBalance)
+	leaves.Get("/balance/ledger", leaveHandler.GetLedger)
+	leaves.Put("/balance/policy", leaveHandler.SetBalancePolicy)
+	leaves.Post("/balance/adjustments", leaveHandler.AddBalanceAdjustment)
+	leaves.Get("/approvers", leaveHandler.GetApprovers)
+	leaves.Post("/approvers", leaveHandler.AddApprover)
+	leaves.Delete("/approvers/:id", leaveHandler.RemoveApprover)
 	leaves.Post("/", leaveHandler.CreateLeave)
 	leaves.Put("/:id", leaveHandler.UpdateLeave)
 	leaves.Delete("/:id", leaveHandler.DeleteLeave)
+	leaves.Post("/:id/approve", leaveHandler.ApproveLeave)
+	leaves.Post("/:id/reject", leaveHandler.RejectLeave)
+	leaves.Post("/:id/cancel", leaveHandler.CancelLeave)
+	leaves.Get("/:id/history", leaveHandler.GetLeaveHistory)
+	leaves.Get("/:id/impact", leaveHandler.GetLeaveImpact)
+	leaves.Post("/:id/impact/apply", leaveHandler.ApplyLeaveImpact)
 
 	// Protected routes - AI Chatbot
 	aiChat := api.Group("/ai", middleware.AuthMiddleware())
@@ -310,6 +557,16 @@ func main() {
 	notifications := api.Group("/notifications", middleware.AuthMiddleware())
 	notifications.Post("/register-device", notificationHandler.RegisterDevice)
 	notifications.Delete("/register-device", notificationHandler.UnregisterDevice)
+	notifications.Get("/devices", notificationHandler.GetDevices)
+	notifications.Get("/history", notificationHandler.GetHistory)
+	notifications.Get("/preferences", notificationHandler.GetPreferences)
+	notifications.Put("/preferences", notificationHandler.UpdatePreferences)
+	notifications.Get("/web-push/public-key", notificationHandler.GetWebPushPublicKey)
+	notifications.Post("/web-push", notificationHandler.RegisterWebPush)
+	notifications.Delete("/web-push", notificationHandler.UnregisterWebPush)
+	notifications.Get("/webhook", notificationHandler.GetWebhook)
+	notifications.Put("/webhook", notificationHandler.SetWebhook)
+	notifications.Delete("/webhook", notificationHandler.DeleteWebhook)
 	notifications.Post("/test", notificationHandler.SendTestNotification) // For testing
 
 	// Protected routes - Security (Keamanan Basis Data - Minggu 2 & 3)
@@ -323,7 +580,8 @@ func main() {
 	security.Get("/dashboard", securityHandler.GetSecurityDashboard)
 
 	// Protected routes - Monitoring (Keamanan Basis Data - Phase 4: Monitoring & Maintenance)
-	monitoring := api.Group("/monitoring", middleware.AuthMiddleware())
+	// Hanya admin: job terjadwal dan laporan keamanan berdampak ke semua user
+	monitoring := api.Group("/monitoring", middleware.AuthMiddleware(), middleware.SecurityManageMiddleware())
 	monitoring.Post("/audit/run", monitoringHandler.RunSecurityAudit)
 	monitoring.Get("/audit/report", monitoringHandler.GetLastAuditReport)
 	monitoring.Get("/audit/history", monitoringHandler.GetAuditHistory)
@@ -335,6 +593,9 @@ func main() {
 	monitoring.Post("/scheduler/task/:type/run", monitoringHandler.RunScheduledTask)
 	monitoring.Post("/scheduler/task/:type/enable", monitoringHandler.EnableScheduledTask)
 	monitoring.Post("/scheduler/task/:type/disable", monitoringHandler.DisableScheduledTask)
+	monitoring.Post("/scheduler/task/:type/pause", monitoringHandler.DisableScheduledTask)
+	monitoring.Post("/scheduler/task/:type/resume", monitoringHandler.EnableScheduledTask)
+	monitoring.Get("/scheduler/runs", monitoringHandler.GetJobRuns)
 
 	// Start server with TLS support (Keamanan Basis Data - Minggu 4)
 	port := config.AppConfig.Port
@@ -40,6 +40,22 @@ type Config struct {
 	FirebaseProjectID       string
 	FirebaseCredentialsFile string
 
+	// Notification outbox - jumlah pengiriman paralel
+	NotificationWorkers int
+
+	// Optional - Web Push (VAPID). Private key P-256 base64url (32 byte)
+	VAPIDPrivateKey string
+	VAPIDSubject    string
+
+	// Scheduler cluster - lease leader (detik), ID instance (default hostname-pid)
+	SchedulerLeaseTTLSeconds int
+	SchedulerInstanceID      string
+	// Zona waktu jadwal cron job (default zona waktu server)
+	SchedulerTimeZone string
+
+	// Email user yang dijadikan admin saat startup (comma-separated)
+	AdminEmails string
+
 	// Optional - Midtrans
 	MidtransServerKey    string
 	MidtransClientKey    string
@@ -51,6 +67,9 @@ type Config struct {
 	// Optional - Resend Email
 	ResendAPIKey string
 
+	// Optional - Holiday ICS feeds (comma-separated, "REGION=url" untuk libur daerah)
+	HolidayICSURLs string
+
 	// Optional - SMTP Email (Legacy - kept for backward compatibility)
 	SMTPHost      string
 	SMTPPort      string
@@ -118,6 +137,17 @@ func Load() error {
 		FirebaseProjectID:       getEnv("FIREBASE_PROJECT_ID", ""),
 		FirebaseCredentialsFile: getEnv("FIREBASE_CREDENTIALS_FILE", ""),
 
+		NotificationWorkers: getEnvAsInt("NOTIFICATION_WORKERS", 4),
+
+		VAPIDPrivateKey: getEnv("VAPID_PRIVATE_KEY", ""),
+		VAPIDSubject:    getEnv("VAPID_SUBJECT", "mailto:noreply@workradar.app"),
+
+		SchedulerLeaseTTLSeconds: getEnvAsInt("SCHEDULER_LEASE_TTL_SECONDS", 30),
+		SchedulerInstanceID:      getEnv("SCHEDULER_INSTANCE_ID", ""),
+		SchedulerTimeZone:        getEnv("SCHEDULER_TIMEZONE", ""),
+
+		AdminEmails: getEnv("ADMIN_EMAILS", ""),
+
 		MidtransServerKey:    getEnv("MIDTRANS_SERVER_KEY", ""),
 		MidtransClientKey:    getEnv("MIDTRANS_CLIENT_KEY", ""),
 		MidtransIsProduction: getEnvAsBool("MIDTRANS_IS_PRODUCTION", false),
@@ -127,6 +157,8 @@ func Load() error {
 		// Resend Email Configuration
 		ResendAPIKey: getEnv("RESEND_API_KEY", ""),
 
+		HolidayICSURLs: getEnv("HOLIDAY_ICS_URLS", ""),
+
 		// SMTP Email Configuration (Legacy - kept for backward compatibility)
 		SMTPHost:      getEnv("SMTP_HOST", "smtp.gmail.com"),
 		SMTPPort:      getEnv("SMTP_PORT", "587"),
@@ -175,6 +207,14 @@ func getFirstEnv(keys []string, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvAsInt(key string, defaultValue int) int {
+	valStr := getEnv(key, "")
+	if val, err := strconv.Atoi(valStr); err == nil {
+		return val
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	valStr := getEnv(key, "")
 	if val, err := strconv.ParseBool(valStr); err == nil {
@@ -0,0 +1,11 @@
+-- Add administrative role to users (user, moderator, admin, superadmin)
+-- Migration: 008_add_role_to_users.sql
+
+ALTER TABLE users
+ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user' AFTER vip_expires_at;
+
+-- Read user butuh role untuk pengecekan akses; app user tidak boleh mengubahnya
+GRANT SELECT (role) ON workradar.users TO 'workradar_read'@'localhost';
+GRANT SELECT (role) ON workradar.users TO 'workradar_read'@'%';
+REVOKE UPDATE (role) ON workradar.users FROM 'workradar_app'@'localhost';
+REVOKE UPDATE (role) ON workradar.users FROM 'workradar_app'@'%';
@@ -0,0 +1,13 @@
+-- Entri ledger cuti otomatis (allotment/accrual/carry-over) unik per periode
+-- Migration: 009_unique_leave_ledger_periods.sql
+
+-- Penyesuaian manual tidak punya periode
+UPDATE leave_ledger_entries SET period = NULL WHERE period = '';
+
+-- Hapus entri otomatis ganda (sisakan yang paling awal)
+DELETE e FROM leave_ledger_entries e
+JOIN leave_ledger_entries k
+  ON k.user_id = e.user_id AND k.type = e.type AND k.year = e.year AND k.kind = e.kind AND k.period = e.period
+ AND (k.created_at < e.created_at OR (k.created_at = e.created_at AND k.id < e.id));
+
+CREATE UNIQUE INDEX idx_leave_ledger_period ON leave_ledger_entries (user_id, type, year, kind, period);
@@ -0,0 +1,4 @@
+-- Index untuk eksekusi terakhir per job (FindLastRun, FindLastFinishedRuns)
+-- Migration: 010_add_job_run_latest_index.sql
+
+CREATE INDEX idx_job_run_latest ON job_runs (scheduler, job, started_at);
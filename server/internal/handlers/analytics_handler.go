@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/services"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService}
+}
+
+// GetHeatmap mendapatkan heatmap harian task selesai
+// GET /api/analytics/heatmap?year=2026
+func (h *AnalyticsHandler) GetHeatmap(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	year := time.Now().In(h.analyticsService.Location(userID)).Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid year",
+			})
+		}
+		year = parsed
+	}
+
+	heatmap, err := h.analyticsService.GetCompletionHeatmap(userID, year)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"heatmap": heatmap,
+	})
+}
+
+// GetDistribution mendapatkan distribusi jam & hari penyelesaian task
+// GET /api/analytics/distribution?start_date=2026-01-01&end_date=2026-03-31
+func (h *AnalyticsHandler) GetDistribution(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	start, end, err := parseAnalyticsRange(c, h.analyticsService.Location(userID))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	distribution, err := h.analyticsService.GetCompletionDistribution(userID, start, end)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch completion distribution",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"distribution": distribution,
+		"start_date":   start.Format("2006-01-02"),
+		"end_date":     end.Format("2006-01-02"),
+	})
+}
+
+// GetCompletionTime mendapatkan rata-rata waktu penyelesaian per kategori
+// GET /api/analytics/completion-time?start_date=...&end_date=...
+func (h *AnalyticsHandler) GetCompletionTime(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	start, end, err := parseAnalyticsRange(c, h.analyticsService.Location(userID))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	completionTime, err := h.analyticsService.GetCompletionTimeByCategory(userID, start, end)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch completion time",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"completion_time": completionTime,
+		"start_date":      start.Format("2006-01-02"),
+		"end_date":        end.Format("2006-01-02"),
+	})
+}
+
+// GetLateness mendapatkan porsi task yang selesai setelah deadline
+// GET /api/analytics/lateness?start_date=...&end_date=...
+func (h *AnalyticsHandler) GetLateness(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	start, end, err := parseAnalyticsRange(c, h.analyticsService.Location(userID))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	lateness, err := h.analyticsService.GetLateCompletionShare(userID, start, end)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch lateness",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"lateness":   lateness,
+		"start_date": start.Format("2006-01-02"),
+		"end_date":   end.Format("2006-01-02"),
+	})
+}
+
+// parseAnalyticsRange membaca start_date/end_date (YYYY-MM-DD) di zona waktu user, default 90 hari terakhir
+func parseAnalyticsRange(c *fiber.Ctx, loc *time.Location) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -89)
+
+	if startStr := c.Query("start_date"); startStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", startStr, loc)
+		if err != nil {
+			return start, end, fiber.NewError(fiber.StatusBadRequest, "Invalid start_date format. Use YYYY-MM-DD")
+		}
+		start = parsed
+	}
+	if endStr := c.Query("end_date"); endStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", endStr, loc)
+		if err != nil {
+			return start, end, fiber.NewError(fiber.StatusBadRequest, "Invalid end_date format. Use YYYY-MM-DD")
+		}
+		end = parsed.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	}
+
+	if end.Before(start) {
+		return start, end, fiber.NewError(fiber.StatusBadRequest, "end_date must be after start_date")
+	}
+
+	return start, end, nil
+}
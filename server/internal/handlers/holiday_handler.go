@@ -70,9 +70,11 @@ func (h *HolidayHandler) CreatePersonalHoliday(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 
 	var requestBody struct {
-		Name        string  `json:"name"`
-		Date        string  `json:"date"` // Format: YYYY-MM-DD
-		Description *string `json:"description"`
+		Name               string  `json:"name"`
+		Date               string  `json:"date"` // Format: YYYY-MM-DD
+		Description        *string `json:"description"`
+		IsRecurring        bool    `json:"is_recurring"`         // berulang setiap tahun (ulang tahun, anniversary)
+		ReminderDaysBefore *int    `json:"reminder_days_before"` // opsional, kirim notifikasi N hari sebelumnya
 	}
 
 	if err := c.BodyParser(&requestBody); err != nil {
@@ -108,10 +110,12 @@ func (h *HolidayHandler) CreatePersonalHoliday(c *fiber.Ctx) error {
 		requestBody.Name,
 		date,
 		requestBody.Description,
+		requestBody.IsRecurring,
+		requestBody.ReminderDaysBefore,
 	)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create holiday",
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
@@ -121,6 +125,36 @@ func (h *HolidayHandler) CreatePersonalHoliday(c *fiber.Ctx) error {
 	})
 }
 
+// UpdatePersonalHoliday memperbarui personal holiday
+// PUT /api/holidays/personal/:id
+func (h *HolidayHandler) UpdatePersonalHoliday(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+	holidayID := c.Params("id")
+
+	var req services.UpdatePersonalHolidayDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	holiday, err := h.holidayService.UpdatePersonalHoliday(holidayID, userID, req)
+	if err != nil {
+		status := fiber.StatusBadRequest
+		if err.Error() == "holiday not found" {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Personal holiday updated successfully",
+		"holiday": holiday,
+	})
+}
+
 // DeletePersonalHoliday menghapus personal holiday
 // DELETE /api/holidays/personal/:id
 func (h *HolidayHandler) DeletePersonalHoliday(c *fiber.Ctx) error {
@@ -143,3 +177,68 @@ func (h *HolidayHandler) DeletePersonalHoliday(c *fiber.Ctx) error {
 		"message": "Personal holiday deleted successfully",
 	})
 }
+
+// GetRegions mendapatkan daftar region libur daerah + region user
+// GET /api/holidays/regions
+func (h *HolidayHandler) GetRegions(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	regions, err := h.holidayService.GetRegions(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch holiday regions",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(regions)
+}
+
+// UpdateRegion memilih region libur daerah user
+// PUT /api/holidays/region
+func (h *HolidayHandler) UpdateRegion(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var requestBody struct {
+		Region string `json:"region"` // ISO 3166-2, kosong = hanya libur nasional
+	}
+
+	if err := c.BodyParser(&requestBody); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.holidayService.UpdateUserRegion(userID, requestBody.Region); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Holiday region updated successfully",
+		"region":  requestBody.Region,
+	})
+}
+
+// ImportHolidays import data holiday satu tahun (admin)
+// POST /api/holidays/import
+func (h *HolidayHandler) ImportHolidays(c *fiber.Ctx) error {
+	var req services.ImportHolidaysDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	result, err := h.holidayService.ImportFromSource(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Holidays imported successfully",
+		"result":  result,
+	})
+}
@@ -2,18 +2,30 @@ package handlers
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/models"
 	"github.com/workradar/server/internal/services"
 )
 
 type LeaveHandler struct {
-	leaveService *services.LeaveService
+	leaveService        *services.LeaveService
+	leaveBalanceService *services.LeaveBalanceService
+	leaveImpactService  *services.LeaveImpactService
 }
 
-func NewLeaveHandler(leaveService *services.LeaveService) *LeaveHandler {
-	return &LeaveHandler{leaveService: leaveService}
+func NewLeaveHandler(
+	leaveService *services.LeaveService,
+	leaveBalanceService *services.LeaveBalanceService,
+	leaveImpactService *services.LeaveImpactService,
+) *LeaveHandler {
+	return &LeaveHandler{
+		leaveService:        leaveService,
+		leaveBalanceService: leaveBalanceService,
+		leaveImpactService:  leaveImpactService,
+	}
 }
 
 // GetLeaves mendapatkan leaves berdasarkan filter
@@ -83,55 +95,70 @@ func (h *LeaveHandler) GetLeaves(c *fiber.Ctx) error {
 func (h *LeaveHandler) CreateLeave(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 
-	var requestBody struct {
-		Date   string `json:"date"` // Format: YYYY-MM-DD
-		Reason string `json:"reason"`
-	}
-
-	if err := c.BodyParser(&requestBody); err != nil {
+	req, err := parseLeaveRequest(c)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
+			"error": err.Error(),
 		})
 	}
 
-	// Validate required fields
-	if requestBody.Date == "" {
+	// Create leave
+	leave, err := h.leaveService.CreateLeave(userID, req, requestMeta(c))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Date is required",
+			"error": err.Error(),
 		})
 	}
 
-	if requestBody.Reason == "" {
+	response := fiber.Map{
+		"message": "Leave created successfully",
+		"leave":   leave,
+	}
+
+	// Task yang terdampak ditampilkan supaya user bisa langsung reschedule / mute
+	if impact, err := h.leaveImpactService.Analyze(leave.ID, userID); err == nil && len(impact.Tasks) > 0 {
+		response["impact"] = impact
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// UpdateLeave mengupdate leave
+// PUT /api/leaves/:id
+func (h *LeaveHandler) UpdateLeave(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+	leaveID := c.Params("id")
+
+	if leaveID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Reason is required",
+			"error": "Leave ID is required",
 		})
 	}
 
-	// Parse date
-	date, err := time.Parse("2006-01-02", requestBody.Date)
+	req, err := parseLeaveRequest(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid date format. Use YYYY-MM-DD",
+			"error": err.Error(),
 		})
 	}
 
-	// Create leave
-	leave, err := h.leaveService.CreateLeave(userID, date, requestBody.Reason)
+	// Update leave
+	leave, err := h.leaveService.UpdateLeave(leaveID, userID, req, requestMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Leave created successfully",
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Leave updated successfully",
 		"leave":   leave,
 	})
 }
 
-// UpdateLeave mengupdate leave
-// PUT /api/leaves/:id
-func (h *LeaveHandler) UpdateLeave(c *fiber.Ctx) error {
+// DeleteLeave menghapus leave
+// DELETE /api/leaves/:id
+func (h *LeaveHandler) DeleteLeave(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 	leaveID := c.Params("id")
 
@@ -141,88 +168,502 @@ func (h *LeaveHandler) UpdateLeave(c *fiber.Ctx) error {
 		})
 	}
 
+	if err := h.leaveService.DeleteLeave(leaveID, userID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Leave deleted successfully",
+	})
+}
+
+// GetUpcomingCount mendapatkan jumlah leaves yang akan datang
+// GET /api/leaves/upcoming/count
+func (h *LeaveHandler) GetUpcomingCount(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	count, err := h.leaveService.GetUpcomingCount(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch upcoming leave count",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"count": count,
+	})
+}
+
+// parseLeaveRequest membaca body pengajuan cuti.
+// "date" tetap diterima sebagai alias start_date untuk client lama.
+func parseLeaveRequest(c *fiber.Ctx) (services.LeaveRequestDTO, error) {
 	var requestBody struct {
-		Date   string `json:"date"`
-		Reason string `json:"reason"`
+		Date       string              `json:"date"`       // Format: YYYY-MM-DD (legacy)
+		StartDate  string              `json:"start_date"` // Format: YYYY-MM-DD
+		EndDate    string              `json:"end_date"`   // Format: YYYY-MM-DD, kosong = satu hari
+		HalfDay    models.LeaveHalfDay `json:"half_day"`   // morning | afternoon
+		Type       models.LeaveType    `json:"type"`       // annual | sick | unpaid | maternity | custom
+		CustomType *string             `json:"custom_type"`
+		Reason     string              `json:"reason"`
 	}
 
+	var req services.LeaveRequestDTO
+	if err := c.BodyParser(&requestBody); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	startStr := requestBody.StartDate
+	if startStr == "" {
+		startStr = requestBody.Date
+	}
+
+	// Validate required fields
+	if startStr == "" {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Date is required")
+	}
+	if requestBody.Reason == "" {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Reason is required")
+	}
+
+	// Parse date
+	startDate, err := time.Parse("2006-01-02", startStr)
+	if err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD")
+	}
+	req.StartDate = startDate
+
+	if requestBody.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", requestBody.EndDate)
+		if err != nil {
+			return req, fiber.NewError(fiber.StatusBadRequest, "Invalid end_date format. Use YYYY-MM-DD")
+		}
+		req.EndDate = &endDate
+	}
+
+	req.HalfDay = requestBody.HalfDay
+	req.Type = requestBody.Type
+	req.CustomType = requestBody.CustomType
+	req.Reason = requestBody.Reason
+
+	return req, nil
+}
+
+// ==================== APPROVAL WORKFLOW ====================
+
+// GetApprovalInbox mendapatkan cuti yang perlu diputuskan approver
+// GET /api/leaves/approvals?status=pending|approved|rejected|cancelled
+func (h *LeaveHandler) GetApprovalInbox(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	status := models.LeaveStatus(c.Query("status", string(models.LeaveStatusPending)))
+	var statusFilter *models.LeaveStatus
+	if status != "all" {
+		if !status.IsValid() {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid status",
+			})
+		}
+		statusFilter = &status
+	}
+
+	leaves, err := h.leaveService.GetApprovalInbox(userID, statusFilter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch leave approvals",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"leaves": leaves,
+	})
+}
+
+// ApproveLeave menyetujui cuti
+// POST /api/leaves/:id/approve
+func (h *LeaveHandler) ApproveLeave(c *fiber.Ctx) error {
+	approve := func(leaveID, userID string, comment *string, meta services.RequestMeta) (*models.LeaveResponse, error) {
+		leave, err := h.leaveService.ApproveLeave(leaveID, userID, comment, meta)
+		if err == nil {
+			go h.leaveImpactService.NotifyApproved(leave.ID)
+		}
+		return leave, err
+	}
+	return h.decideLeave(c, approve, "Leave approved successfully")
+}
+
+// RejectLeave menolak cuti (comment wajib)
+// POST /api/leaves/:id/reject
+func (h *LeaveHandler) RejectLeave(c *fiber.Ctx) error {
+	return h.decideLeave(c, h.leaveService.RejectLeave, "Leave rejected successfully")
+}
+
+// CancelLeave membatalkan cuti oleh pengaju
+// POST /api/leaves/:id/cancel
+func (h *LeaveHandler) CancelLeave(c *fiber.Ctx) error {
+	return h.decideLeave(c, h.leaveService.CancelLeave, "Leave cancelled successfully")
+}
+
+func (h *LeaveHandler) decideLeave(
+	c *fiber.Ctx,
+	action func(leaveID, userID string, comment *string, meta services.RequestMeta) (*models.LeaveResponse, error),
+	message string,
+) error {
+	userID := c.Locals("user_id").(string)
+	leaveID := c.Params("id")
+
+	var requestBody struct {
+		Comment *string `json:"comment"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	leave, err := action(leaveID, userID, requestBody.Comment, requestMeta(c))
+	if err != nil {
+		status := fiber.StatusBadRequest
+		switch err.Error() {
+		case "leave not found":
+			status = fiber.StatusNotFound
+		case "unauthorized":
+			status = fiber.StatusForbidden
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": message,
+		"leave":   leave,
+	})
+}
+
+// GetLeaveHistory mendapatkan riwayat status cuti
+// GET /api/leaves/:id/history
+func (h *LeaveHandler) GetLeaveHistory(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	history, err := h.leaveService.GetLeaveHistory(c.Params("id"), userID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "leave not found":
+			status = fiber.StatusNotFound
+		case "unauthorized":
+			status = fiber.StatusForbidden
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"history": history,
+	})
+}
+
+// GetApprovers mendapatkan approver cuti user
+// GET /api/leaves/approvers?user_id=
+func (h *LeaveHandler) GetApprovers(c *fiber.Ctx) error {
+	actorID := c.Locals("user_id").(string)
+
+	targetID := c.Query("user_id", actorID)
+	approvers, err := h.leaveService.GetApprovers(actorID, targetID)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch approvers",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"approvers": approvers,
+	})
+}
+
+// AddApprover menunjuk approver cuti untuk member (admin / manager workspace)
+// POST /api/leaves/approvers
+func (h *LeaveHandler) AddApprover(c *fiber.Ctx) error {
+	actorID := c.Locals("user_id").(string)
+
+	var requestBody struct {
+		UserID string `json:"user_id"`
+		Email  string `json:"email"`
+	}
 	if err := c.BodyParser(&requestBody); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
 
-	// Validate required fields
-	if requestBody.Date == "" {
+	approver, err := h.leaveService.AddApprover(actorID, requestBody.UserID, requestBody.Email, requestMeta(c))
+	if err != nil {
+		return c.Status(leaveApproverErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":  "Approver added successfully",
+		"approver": approver,
+	})
+}
+
+// RemoveApprover menghapus approver cuti (admin / manager workspace)
+// DELETE /api/leaves/approvers/:id
+func (h *LeaveHandler) RemoveApprover(c *fiber.Ctx) error {
+	actorID := c.Locals("user_id").(string)
+
+	if err := h.leaveService.RemoveApprover(actorID, c.Params("id"), requestMeta(c)); err != nil {
+		return c.Status(leaveApproverErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Approver removed successfully",
+	})
+}
+
+func leaveApproverErrorStatus(err error) int {
+	switch err.Error() {
+	case "unauthorized":
+		return fiber.StatusForbidden
+	case "approver not found":
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusBadRequest
+}
+
+// ==================== TASK IMPACT ====================
+
+// GetLeaveImpact preview task yang deadline / reminder-nya jatuh saat cuti
+// GET /api/leaves/:id/impact
+func (h *LeaveHandler) GetLeaveImpact(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	impact, err := h.leaveImpactService.Analyze(c.Params("id"), userID)
+	if err != nil {
+		return c.Status(leaveImpactErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"impact": impact,
+	})
+}
+
+// ApplyLeaveImpact konfirmasi reschedule / mute task terdampak
+// POST /api/leaves/:id/impact/apply
+func (h *LeaveHandler) ApplyLeaveImpact(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var requestBody struct {
+		Actions []services.LeaveImpactActionDTO `json:"actions"`
+	}
+	if err := c.BodyParser(&requestBody); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Date is required",
+			"error": "Invalid request body",
 		})
 	}
 
-	if requestBody.Reason == "" {
+	if len(requestBody.Actions) == 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Reason is required",
+			"error": "Actions are required",
 		})
 	}
 
-	// Parse date
-	date, err := time.Parse("2006-01-02", requestBody.Date)
+	results, err := h.leaveImpactService.Apply(c.Params("id"), userID, requestBody.Actions, requestMeta(c))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid date format. Use YYYY-MM-DD",
+		return c.Status(leaveImpactErrorStatus(err)).JSON(fiber.Map{
+			"error":   err.Error(),
+			"results": results,
 		})
 	}
 
-	// Update leave
-	leave, err := h.leaveService.UpdateLeave(leaveID, userID, date, requestBody.Reason)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Leave impact applied successfully",
+		"results": results,
+	})
+}
+
+func leaveImpactErrorStatus(err error) int {
+	switch err.Error() {
+	case "leave not found":
+		return fiber.StatusNotFound
+	case "unauthorized":
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusBadRequest
+}
+
+// ==================== BALANCE ====================
+
+// GetBalance mendapatkan saldo cuti (taken, pending, remaining)
+// GET /api/leaves/balance?year=2026&type=annual
+func (h *LeaveHandler) GetBalance(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	year, err := leaveYearQuery(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	if leaveType := c.Query("type"); leaveType != "" {
+		balance, err := h.leaveBalanceService.GetBalance(userID, models.LeaveType(leaveType), year)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"balance": balance,
+		})
+	}
+
+	balances, err := h.leaveBalanceService.GetBalances(userID, year)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch leave balance",
+		})
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Leave updated successfully",
-		"leave":   leave,
+		"balances": balances,
+		"year":     year,
 	})
 }
 
-// DeleteLeave menghapus leave
-// DELETE /api/leaves/:id
-func (h *LeaveHandler) DeleteLeave(c *fiber.Ctx) error {
+// GetLedger mendapatkan mutasi saldo cuti
+// GET /api/leaves/balance/ledger?type=annual&year=2026
+func (h *LeaveHandler) GetLedger(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
-	leaveID := c.Params("id")
 
-	if leaveID == "" {
+	year, err := leaveYearQuery(c)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Leave ID is required",
+			"error": err.Error(),
 		})
 	}
 
-	if err := h.leaveService.DeleteLeave(leaveID, userID); err != nil {
+	entries, err := h.leaveBalanceService.GetLedger(userID, models.LeaveType(c.Query("type", string(models.LeaveTypeAnnual))), year)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Leave deleted successfully",
+		"entries": entries,
+		"year":    year,
 	})
 }
 
-// GetUpcomingCount mendapatkan jumlah leaves yang akan datang
-// GET /api/leaves/upcoming/count
-func (h *LeaveHandler) GetUpcomingCount(c *fiber.Ctx) error {
-	userID := c.Locals("user_id").(string)
+// SetBalancePolicy mengatur jatah, accrual dan carry-over cap
+// PUT /api/leaves/balance/policy
+func (h *LeaveHandler) SetBalancePolicy(c *fiber.Ctx) error {
+	actorID := c.Locals("user_id").(string)
 
-	count, err := h.leaveService.GetUpcomingCount(userID)
+	var requestBody struct {
+		UserID string `json:"user_id"` // kosong = diri sendiri
+		services.LeavePolicyDTO
+	}
+	if err := c.BodyParser(&requestBody); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	targetID := requestBody.UserID
+	if targetID == "" {
+		targetID = actorID
+	}
+
+	policy, err := h.leaveBalanceService.SetPolicy(actorID, targetID, requestBody.LeavePolicyDTO)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch upcoming leave count",
+		return c.Status(leaveBalanceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"count": count,
+		"message": "Leave policy updated successfully",
+		"policy":  policy,
 	})
 }
+
+// AddBalanceAdjustment penyesuaian manual saldo cuti
+// POST /api/leaves/balance/adjustments
+func (h *LeaveHandler) AddBalanceAdjustment(c *fiber.Ctx) error {
+	actorID := c.Locals("user_id").(string)
+
+	var requestBody struct {
+		UserID string `json:"user_id"` // kosong = diri sendiri
+		services.LeaveAdjustmentDTO
+	}
+	if err := c.BodyParser(&requestBody); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	targetID := requestBody.UserID
+	if targetID == "" {
+		targetID = actorID
+	}
+
+	entry, err := h.leaveBalanceService.AddAdjustment(actorID, targetID, requestBody.LeaveAdjustmentDTO)
+	if err != nil {
+		return c.Status(leaveBalanceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Leave balance adjusted successfully",
+		"entry":   entry,
+	})
+}
+
+func leaveBalanceErrorStatus(err error) int {
+	if strings.HasPrefix(err.Error(), "unauthorized") {
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusBadRequest
+}
+
+// leaveYearQuery membaca ?year=, default tahun berjalan
+func leaveYearQuery(c *fiber.Ctx) (int, error) {
+	yearStr := c.Query("year")
+	if yearStr == "" {
+		return time.Now().Year(), nil
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil || year < 2000 || year > 2100 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid year format")
+	}
+	return year, nil
+}
+
+// requestMeta informasi request untuk audit log
+func requestMeta(c *fiber.Ctx) services.RequestMeta {
+	return services.RequestMeta{
+		IP:        c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Path:      c.Path(),
+	}
+}
@@ -16,14 +16,18 @@ type MonitoringHandler struct {
 	auditService    *services.SecurityAuditService
 	vulnScanner     *services.VulnerabilityScannerService
 	auditLogService *services.AuditService
+	jobRunService   *services.JobRunService
+	jobRegistry     *services.JobRegistry
 }
 
 // NewMonitoringHandler creates a new monitoring handler
-func NewMonitoringHandler() *MonitoringHandler {
+func NewMonitoringHandler(jobRunService *services.JobRunService, jobRegistry *services.JobRegistry) *MonitoringHandler {
 	return &MonitoringHandler{
 		auditService:    services.GetSecurityAuditService(),
 		vulnScanner:     services.GetVulnerabilityScannerService(),
 		auditLogService: services.GetAuditService(),
+		jobRunService:   jobRunService,
+		jobRegistry:     jobRegistry,
 	}
 }
 
@@ -677,15 +681,15 @@ func (h *MonitoringHandler) DetectVulnerabilities(c *fiber.Ctx) error {
 
 // GetSchedulerStatus godoc
 // @Summary Get scheduler status
-// @Description Returns status of security scheduled tasks
+// @Description Lists all scheduled jobs (notification and security) with cron schedule, next/last run, pause state and running count
 // @Tags Monitoring
 // @Produce json
 // @Security BearerAuth
+// @Param scheduler query string false "Scheduler (notifications, security)"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/monitoring/scheduler/status [get]
 func (h *MonitoringHandler) GetSchedulerStatus(c *fiber.Ctx) error {
-	scheduler := services.GetSecuritySchedulerService()
-	status := scheduler.GetSchedulerStatus()
+	status := h.jobRegistry.Status(c.Query("scheduler"))
 
 	return c.JSON(fiber.Map{
 		"success": true,
@@ -693,13 +697,43 @@ func (h *MonitoringHandler) GetSchedulerStatus(c *fiber.Ctx) error {
 	})
 }
 
+// GetJobRuns godoc
+// @Summary Get scheduler job-run history
+// @Description Returns persisted runs of all scheduled jobs (notification and security schedulers) with duration and outcome
+// @Tags Monitoring
+// @Produce json
+// @Security BearerAuth
+// @Param scheduler query string false "Scheduler (notifications, security)"
+// @Param job query string false "Job name"
+// @Param limit query int false "Limit (default 50)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/monitoring/scheduler/runs [get]
+func (h *MonitoringHandler) GetJobRuns(c *fiber.Ctx) error {
+	runs, total, err := h.jobRunService.GetRuns(c.Query("scheduler"), c.Query("job"), c.QueryInt("limit", 50), c.QueryInt("offset", 0))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"message": "Failed to fetch job runs",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": fiber.Map{
+			"runs":  runs,
+			"total": total,
+		},
+	})
+}
+
 // RunScheduledTask godoc
 // @Summary Run scheduled task immediately
-// @Description Triggers a specific scheduled task to run immediately
+// @Description Triggers a scheduled job to run immediately on this instance
 // @Tags Monitoring
 // @Produce json
 // @Security BearerAuth
-// @Param type path string true "Task type"
+// @Param type path string true "Job name (e.g. task_reminder, SECURITY_AUDIT)"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/monitoring/scheduler/task/{type}/run [post]
 func (h *MonitoringHandler) RunScheduledTask(c *fiber.Ctx) error {
@@ -711,15 +745,17 @@ func (h *MonitoringHandler) RunScheduledTask(c *fiber.Ctx) error {
 		})
 	}
 
-	scheduler := services.GetSecuritySchedulerService()
-	err := scheduler.RunTaskNow(services.SecurityScheduledTaskType(taskType))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	if err := h.jobRegistry.RunNow(taskType); err != nil {
+		return c.Status(schedulerErrorStatus(err)).JSON(fiber.Map{
 			"success": false,
 			"message": err.Error(),
 		})
 	}
 
+	userID := c.Locals("user_id").(string)
+	h.auditLogService.LogCreate(&userID, "job_runs", taskType, fiber.Map{"job": taskType, "trigger": "manual"},
+		c.IP(), c.Get("User-Agent"), c.Path(), fiber.StatusOK, 0)
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"message": "Task triggered successfully",
@@ -728,14 +764,15 @@ func (h *MonitoringHandler) RunScheduledTask(c *fiber.Ctx) error {
 }
 
 // EnableScheduledTask godoc
-// @Summary Enable scheduled task
-// @Description Enables a specific scheduled task
+// @Summary Resume scheduled task
+// @Description Resumes a paused scheduled job on all instances
 // @Tags Monitoring
 // @Produce json
 // @Security BearerAuth
-// @Param type path string true "Task type"
+// @Param type path string true "Job name"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/monitoring/scheduler/task/{type}/enable [post]
+// @Router /api/monitoring/scheduler/task/{type}/resume [post]
 func (h *MonitoringHandler) EnableScheduledTask(c *fiber.Ctx) error {
 	taskType := c.Params("type")
 	if taskType == "" {
@@ -745,31 +782,31 @@ func (h *MonitoringHandler) EnableScheduledTask(c *fiber.Ctx) error {
 		})
 	}
 
-	scheduler := services.GetSecuritySchedulerService()
-	err := scheduler.EnableTask(services.SecurityScheduledTaskType(taskType))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	if err := h.jobRegistry.Resume(taskType); err != nil {
+		return c.Status(schedulerErrorStatus(err)).JSON(fiber.Map{
 			"success": false,
 			"message": err.Error(),
 		})
 	}
+	h.auditJobPause(c, taskType, false)
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Task enabled successfully",
+		"message": "Task resumed successfully",
 		"task":    taskType,
 	})
 }
 
 // DisableScheduledTask godoc
-// @Summary Disable scheduled task
-// @Description Disables a specific scheduled task
+// @Summary Pause scheduled task
+// @Description Pauses a scheduled job on all instances until it is resumed (manual runs are still possible)
 // @Tags Monitoring
 // @Produce json
 // @Security BearerAuth
-// @Param type path string true "Task type"
+// @Param type path string true "Job name"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/monitoring/scheduler/task/{type}/disable [post]
+// @Router /api/monitoring/scheduler/task/{type}/pause [post]
 func (h *MonitoringHandler) DisableScheduledTask(c *fiber.Ctx) error {
 	taskType := c.Params("type")
 	if taskType == "" {
@@ -779,18 +816,35 @@ func (h *MonitoringHandler) DisableScheduledTask(c *fiber.Ctx) error {
 		})
 	}
 
-	scheduler := services.GetSecuritySchedulerService()
-	err := scheduler.DisableTask(services.SecurityScheduledTaskType(taskType))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	if err := h.jobRegistry.Pause(taskType); err != nil {
+		return c.Status(schedulerErrorStatus(err)).JSON(fiber.Map{
 			"success": false,
 			"message": err.Error(),
 		})
 	}
+	h.auditJobPause(c, taskType, true)
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Task disabled successfully",
+		"message": "Task paused successfully",
 		"task":    taskType,
 	})
 }
+
+// auditJobPause mencatat pause/resume job (berlaku untuk semua replica) di audit log
+func (h *MonitoringHandler) auditJobPause(c *fiber.Ctx, job string, paused bool) {
+	userID := c.Locals("user_id").(string)
+	h.auditLogService.LogUpdate(&userID, "scheduled_job_states", job, fiber.Map{"paused": !paused}, fiber.Map{"paused": paused},
+		c.IP(), c.Get("User-Agent"), c.Path(), fiber.StatusOK, 0)
+}
+
+func schedulerErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "job not found"):
+		return fiber.StatusNotFound
+	case strings.HasPrefix(message, "job is already running"):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/models"
 	"github.com/workradar/server/internal/services"
 )
 
@@ -15,51 +18,307 @@ func NewNotificationHandler(notificationService *services.NotificationService) *
 	}
 }
 
-// RegisterDevice registers a device FCM token
+// RegisterDevice registers a device FCM token (one entry per device)
 // POST /api/notifications/register-device
 func (h *NotificationHandler) RegisterDevice(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 
+	var req services.RegisterDeviceDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	device, err := h.notificationService.RegisterDevice(userID, req)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "FCM token is required") || strings.HasPrefix(err.Error(), "invalid platform") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Device registered successfully",
+		"device":  device,
+	})
+}
+
+// UnregisterDevice removes one device token (fcm_token in body or query); without token all devices are removed
+// DELETE /api/notifications/register-device
+func (h *NotificationHandler) UnregisterDevice(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
 	var req struct {
 		FCMToken string `json:"fcm_token"`
 	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+	if req.FCMToken == "" {
+		req.FCMToken = c.Query("fcm_token")
+	}
+
+	if err := h.notificationService.UnregisterDevice(userID, req.FCMToken); err != nil {
+		status := fiber.StatusInternalServerError
+		if err.Error() == "device not found" {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Device unregistered successfully",
+	})
+}
+
+// GetDevices lists registered devices
+// GET /api/notifications/devices
+func (h *NotificationHandler) GetDevices(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	devices, err := h.notificationService.GetDevices(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch devices",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"devices": devices,
+		"count":   len(devices),
+	})
+}
+
+// GetWebPushPublicKey returns the VAPID key used as applicationServerKey by browsers
+// GET /api/notifications/web-push/public-key
+func (h *NotificationHandler) GetWebPushPublicKey(c *fiber.Ctx) error {
+	publicKey, err := h.notificationService.WebPushPublicKey()
+	if err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"public_key": publicKey,
+	})
+}
+
+// RegisterWebPush saves a browser push subscription (body = PushSubscription.toJSON())
+// POST /api/notifications/web-push
+func (h *NotificationHandler) RegisterWebPush(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
 
+	var req services.RegisterWebPushDTO
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
 
-	if req.FCMToken == "" {
+	subscription, err := h.notificationService.RegisterWebPush(userID, req)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":      "Web push subscription registered successfully",
+		"subscription": subscription,
+	})
+}
+
+// UnregisterWebPush removes a browser push subscription
+// DELETE /api/notifications/web-push
+func (h *NotificationHandler) UnregisterWebPush(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req struct {
+		Endpoint string `json:"endpoint"`
+	}
+	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "FCM token is required",
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.notificationService.UnregisterWebPush(userID, req.Endpoint); err != nil {
+		status := fiber.StatusInternalServerError
+		switch err.Error() {
+		case "endpoint is required":
+			status = fiber.StatusBadRequest
+		case "subscription not found":
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
-	if err := h.notificationService.RegisterDevice(userID, req.FCMToken); err != nil {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Web push subscription removed successfully",
+	})
+}
+
+// GetWebhook returns the notification webhook of the user
+// GET /api/notifications/webhook
+func (h *NotificationHandler) GetWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	webhook, err := h.notificationService.GetWebhook(userID)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch webhook",
+		})
+	}
+	if webhook == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook not configured",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"webhook": webhook,
+	})
+}
+
+// SetWebhook sets the notification webhook URL. The signing secret is only returned
+// when the webhook is created or rotate_secret is true.
+// PUT /api/notifications/webhook
+func (h *NotificationHandler) SetWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req struct {
+		URL          string `json:"url"`
+		RotateSecret bool   `json:"rotate_secret"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	webhook, secret, err := h.notificationService.SetWebhook(userID, req.URL, req.RotateSecret)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid") {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	response := fiber.Map{
+		"message": "Webhook saved successfully",
+		"webhook": webhook,
+	}
+	if secret != "" {
+		response["secret"] = secret
+	}
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DeleteWebhook removes the notification webhook
+// DELETE /api/notifications/webhook
+func (h *NotificationHandler) DeleteWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	if err := h.notificationService.DeleteWebhook(userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete webhook",
+		})
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Device registered successfully",
+		"message": "Webhook deleted successfully",
 	})
 }
 
-// UnregisterDevice removes FCM token from user
-// DELETE /api/notifications/register-device
-func (h *NotificationHandler) UnregisterDevice(c *fiber.Ctx) error {
+// GetHistory returns the user's notification delivery history
+// GET /api/notifications/history?status=failed&limit=50&offset=0
+func (h *NotificationHandler) GetHistory(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var status *models.NotificationStatus
+	if value := c.Query("status"); value != "" {
+		parsed := models.NotificationStatus(value)
+		switch parsed {
+		case models.NotificationStatusQueued, models.NotificationStatusSent, models.NotificationStatusFailed, models.NotificationStatusDead:
+			status = &parsed
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid status. Use: queued, sent, failed, or dead",
+			})
+		}
+	}
+
+	notifications, total, err := h.notificationService.GetHistory(userID, status, c.QueryInt("limit", 50), c.QueryInt("offset", 0))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch notification history",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"notifications": notifications,
+		"total":         total,
+	})
+}
+
+// GetPreferences returns time zone, quiet hours and per-type notification preferences
+// GET /api/notifications/preferences
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 
-	if err := h.notificationService.UnregisterDevice(userID); err != nil {
+	preferences, err := h.notificationService.GetPreferences(userID)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch notification preferences",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(preferences)
+}
+
+// UpdatePreferences updates time zone, quiet hours and/or per-type notification preferences
+// PUT /api/notifications/preferences
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.UpdateNotificationPreferencesDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Device unregistered successfully",
+		"message":     "Notification preferences updated successfully",
+		"settings":    preferences.Settings,
+		"preferences": preferences.Preferences,
 	})
 }
 
@@ -103,6 +362,6 @@ func (h *NotificationHandler) SendTestNotification(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Test notification sent successfully",
+		"message": "Test notification queued successfully",
 	})
 }
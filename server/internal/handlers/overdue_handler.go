@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/services"
+)
+
+type OverdueHandler struct {
+	overdueService *services.OverdueService
+}
+
+func NewOverdueHandler(overdueService *services.OverdueService) *OverdueHandler {
+	return &OverdueHandler{overdueService: overdueService}
+}
+
+// GetOverdueTasks mendapatkan task yang lewat deadline beserta policy & tindakan terakhir
+// GET /api/tasks/overdue
+func (h *OverdueHandler) GetOverdueTasks(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	items, err := h.overdueService.GetOverdueTasks(userID, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch overdue tasks",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tasks": items,
+		"count": len(items),
+	})
+}
+
+// GetPolicies mendapatkan overdue policy default & per kategori
+// GET /api/tasks/overdue/policies
+func (h *OverdueHandler) GetPolicies(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	policies, err := h.overdueService.GetPolicies(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch overdue policies",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(policies)
+}
+
+// UpdatePolicy membuat/memperbarui overdue policy (tanpa category_id = default user)
+// PUT /api/tasks/overdue/policies
+func (h *OverdueHandler) UpdatePolicy(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.UpdateOverduePolicyDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	policy, err := h.overdueService.UpdatePolicy(userID, req)
+	if err != nil {
+		status := fiber.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Overdue policy updated successfully",
+		"policy":  policy,
+	})
+}
+
+// DeletePolicy menghapus overdue policy kategori (atau default jika category_id kosong)
+// DELETE /api/tasks/overdue/policies?category_id=
+func (h *OverdueHandler) DeletePolicy(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var categoryID *string
+	if id := c.Query("category_id"); id != "" {
+		categoryID = &id
+	}
+
+	if err := h.overdueService.DeletePolicy(userID, categoryID); err != nil {
+		status := fiber.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Overdue policy deleted successfully",
+	})
+}
+
+// GetActionLog riwayat tindakan job overdue (ingatkan ulang, rollover, tandai terlewat)
+// GET /api/tasks/overdue/log?limit=50
+func (h *OverdueHandler) GetActionLog(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	entries, err := h.overdueService.GetActionLog(userID, c.QueryInt("limit", 50))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch overdue log",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
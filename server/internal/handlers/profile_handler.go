@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/models"
 	"github.com/workradar/server/internal/services"
 )
 
@@ -88,3 +89,47 @@ func (h *ProfileHandler) UpdateWorkHours(c *fiber.Ctx) error {
 		"work_days": requestBody.WorkDays,
 	})
 }
+
+// GetDeadlinePolicy mendapatkan policy default pergeseran deadline
+// GET /api/profile/deadline-policy
+func (h *ProfileHandler) GetDeadlinePolicy(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	policy, err := h.profileService.GetDeadlineShiftPolicy(userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"deadline_shift_policy": policy,
+	})
+}
+
+// UpdateDeadlinePolicy mengupdate policy default pergeseran deadline
+// PUT /api/profile/deadline-policy
+func (h *ProfileHandler) UpdateDeadlinePolicy(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var requestBody struct {
+		DeadlineShiftPolicy models.DeadlineShiftPolicy `json:"deadline_shift_policy"`
+	}
+
+	if err := c.BodyParser(&requestBody); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.profileService.UpdateDeadlineShiftPolicy(userID, requestBody.DeadlineShiftPolicy); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":               "Deadline policy updated successfully",
+		"deadline_shift_policy": requestBody.DeadlineShiftPolicy,
+	})
+}
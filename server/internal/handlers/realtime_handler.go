@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/services"
+)
+
+// realtimeHeartbeat interval komentar keep-alive agar proxy tidak menutup koneksi idle
+const realtimeHeartbeat = 25 * time.Second
+
+type RealtimeHandler struct {
+	broker services.EventBroker
+}
+
+func NewRealtimeHandler(broker services.EventBroker) *RealtimeHandler {
+	return &RealtimeHandler{broker: broker}
+}
+
+// Stream membuka koneksi Server-Sent Events untuk update real-time.
+// Resume: kirim header Last-Event-ID (atau query last_event_id) dengan ID event terakhir.
+// GET /api/events/stream
+func (h *RealtimeHandler) Stream(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	lastEventID := c.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	subscription := h.broker.Subscribe(userID, lastEventID)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer subscription.Close()
+
+		// Saran jeda reconnect untuk EventSource (ms)
+		fmt.Fprint(w, "retry: 3000\n\n")
+
+		// Event terlewat tidak bisa di-replay utuh (buffer terlewati atau ID dari proses
+		// server sebelumnya): minta client refetch data
+		if subscription.Gap {
+			fmt.Fprint(w, "event: resync\ndata: {}\n\n")
+		}
+		for _, event := range subscription.Missed {
+			writeRealtimeEvent(w, event)
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(realtimeHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					// Diputus broker (client lambat); client reconnect dengan Last-Event-ID
+					return
+				}
+				writeRealtimeEvent(w, event)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			// Flush gagal = client sudah disconnect
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+func writeRealtimeEvent(w *bufio.Writer, event services.RealtimeEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
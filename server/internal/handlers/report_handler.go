@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/services"
+)
+
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// GetReports mendapatkan daftar laporan produktivitas user
+// GET /api/reports?period=weekly|monthly
+func (h *ReportHandler) GetReports(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var periodPtr *models.ReportPeriod
+	if period := c.Query("period"); period != "" {
+		p := models.ReportPeriod(period)
+		periodPtr = &p
+	}
+
+	reports, err := h.reportService.GetReports(userID, periodPtr)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch reports",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"reports": reports,
+		"count":   len(reports),
+	})
+}
+
+// GenerateReport membuat laporan on-demand
+// POST /api/reports/generate
+func (h *ReportHandler) GenerateReport(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req struct {
+		Period  models.ReportPeriod `json:"period"`
+		Date    string              `json:"date"`    // Format: YYYY-MM-DD, default periode lengkap terakhir
+		Deliver bool                `json:"deliver"` // kirim via bot message/email
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Period == "" {
+		req.Period = models.ReportPeriodWeekly
+	}
+
+	var start, end time.Time
+	if req.Date != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid date format. Use YYYY-MM-DD",
+			})
+		}
+		start, end = services.ReportPeriodRange(req.Period, date)
+	} else {
+		start, end = services.PreviousReportPeriodRange(req.Period, time.Now())
+	}
+
+	var report *models.ProductivityReport
+	var err error
+	if req.Deliver {
+		report, err = h.reportService.GenerateAndDeliver(userID, req.Period, start, end)
+	} else {
+		report, err = h.reportService.GenerateReport(userID, req.Period, start, end)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Report generated successfully",
+		"report":  report,
+	})
+}
+
+// GetReport mendapatkan detail laporan
+// GET /api/reports/:id
+func (h *ReportHandler) GetReport(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	report, err := h.reportService.GetReport(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"report": report,
+	})
+}
+
+// DownloadReport mengunduh laporan dalam format PDF atau HTML
+// GET /api/reports/:id/download?format=pdf|html
+func (h *ReportHandler) DownloadReport(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+	format := c.Query("format", "pdf")
+
+	if format != "pdf" && format != "html" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid format. Use 'pdf' or 'html'",
+		})
+	}
+
+	report, err := h.reportService.GetReport(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+services.ReportFilename(report, format)+`"`)
+	if format == "html" {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(report.HTML)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	return c.Send(report.PDF)
+}
+
+// GetSettings mendapatkan setting opt-in laporan
+// GET /api/reports/settings
+func (h *ReportHandler) GetSettings(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	setting, err := h.reportService.GetSetting(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch report settings",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"settings": setting,
+	})
+}
+
+// UpdateSettings memperbarui setting opt-in laporan
+// PUT /api/reports/settings
+func (h *ReportHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.UpdateReportSettingDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	setting, err := h.reportService.UpdateSetting(userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":  "Report settings updated successfully",
+		"settings": setting,
+	})
+}
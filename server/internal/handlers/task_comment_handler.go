@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/services"
+)
+
+type TaskCommentHandler struct {
+	commentService *services.TaskCommentService
+}
+
+func NewTaskCommentHandler(commentService *services.TaskCommentService) *TaskCommentHandler {
+	return &TaskCommentHandler{commentService: commentService}
+}
+
+// GetComments mendapatkan thread komentar task
+// GET /api/tasks/:id/comments
+func (h *TaskCommentHandler) GetComments(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	comments, err := h.commentService.GetComments(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"comments": comments,
+		"count":    len(comments),
+	})
+}
+
+// CreateComment menambah komentar atau balasan
+// POST /api/tasks/:id/comments
+func (h *TaskCommentHandler) CreateComment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.TaskCommentDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	comment, err := h.commentService.CreateComment(userID, c.Params("id"), req)
+	if err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Comment created successfully",
+		"comment": comment,
+	})
+}
+
+// UpdateComment mengubah komentar
+// PUT /api/tasks/:id/comments/:commentId
+func (h *TaskCommentHandler) UpdateComment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.TaskCommentDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	comment, err := h.commentService.UpdateComment(userID, c.Params("id"), c.Params("commentId"), req)
+	if err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Comment updated successfully",
+		"comment": comment,
+	})
+}
+
+// DeleteComment menghapus komentar beserta balasannya
+// DELETE /api/tasks/:id/comments/:commentId
+func (h *TaskCommentHandler) DeleteComment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	if err := h.commentService.DeleteComment(userID, c.Params("id"), c.Params("commentId")); err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Comment deleted successfully",
+	})
+}
+
+func commentErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		return fiber.StatusNotFound
+	case strings.HasPrefix(message, "unauthorized"):
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusBadRequest
+}
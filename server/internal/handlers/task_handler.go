@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/workradar/server/internal/services"
 )
@@ -25,17 +27,22 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 		})
 	}
 
-	task, err := h.taskService.CreateTask(userID, req)
+	task, err := h.taskService.CreateTask(userID, req, requestMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	response := fiber.Map{
 		"message": "Task created successfully",
 		"task":    task,
-	})
+	}
+	if task.DeadlineShift != nil {
+		response["deadline_shift"] = task.DeadlineShift.Note
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
 // GetTasks mendapatkan semua tasks user
@@ -63,6 +70,76 @@ func (h *TaskHandler) GetTasks(c *fiber.Ctx) error {
 	})
 }
 
+// GetAssignedToMe mendapatkan task bersama yang ditugaskan ke user
+// GET /api/tasks/assigned-to-me
+func (h *TaskHandler) GetAssignedToMe(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	tasks, err := h.taskService.GetAssignedToMe(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch assigned tasks",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tasks": tasks,
+		"count": len(tasks),
+	})
+}
+
+// GetAssignedByMe mendapatkan task yang ditugaskan user ke member lain
+// GET /api/tasks/assigned-by-me
+func (h *TaskHandler) GetAssignedByMe(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	tasks, err := h.taskService.GetAssignedByMe(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch assigned tasks",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tasks": tasks,
+		"count": len(tasks),
+	})
+}
+
+// SetAssignees mengganti assignee task bersama
+// PUT /api/tasks/:id/assignees
+func (h *TaskHandler) SetAssignees(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req struct {
+		AssigneeIDs []string `json:"assignee_ids"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	task, err := h.taskService.SetAssignees(userID, c.Params("id"), req.AssigneeIDs, requestMeta(c))
+	if err != nil {
+		status := fiber.StatusBadRequest
+		switch {
+		case err.Error() == "task not found":
+			status = fiber.StatusNotFound
+		case strings.HasPrefix(err.Error(), "unauthorized"):
+			status = fiber.StatusForbidden
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Task assignees updated successfully",
+		"task":    task,
+	})
+}
+
 // GetTaskByID mendapatkan detail task
 // GET /api/tasks/:id
 func (h *TaskHandler) GetTaskByID(c *fiber.Ctx) error {
@@ -94,17 +171,22 @@ func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
 		})
 	}
 
-	task, err := h.taskService.UpdateTask(userID, taskID, req)
+	task, err := h.taskService.UpdateTask(userID, taskID, req, requestMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+	response := fiber.Map{
 		"message": "Task updated successfully",
 		"task":    task,
-	})
+	}
+	if task.DeadlineShift != nil {
+		response["deadline_shift"] = task.DeadlineShift.Note
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
 }
 
 // DeleteTask menghapus task
@@ -113,7 +195,7 @@ func (h *TaskHandler) DeleteTask(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 	taskID := c.Params("id")
 
-	if err := h.taskService.DeleteTask(userID, taskID); err != nil {
+	if err := h.taskService.DeleteTask(userID, taskID, requestMeta(c)); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -130,7 +212,7 @@ func (h *TaskHandler) ToggleComplete(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(string)
 	taskID := c.Params("id")
 
-	task, err := h.taskService.ToggleTaskComplete(userID, taskID)
+	task, err := h.taskService.ToggleTaskComplete(userID, taskID, requestMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
@@ -142,3 +224,55 @@ func (h *TaskHandler) ToggleComplete(c *fiber.Ctx) error {
 		"task":    task,
 	})
 }
+
+// BulkUpdateTasks menerapkan perubahan yang sama ke banyak task
+// PATCH /api/tasks/bulk
+func (h *TaskHandler) BulkUpdateTasks(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.BulkUpdateTaskDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	result, err := h.taskService.BulkUpdateTasks(userID, req, requestMeta(c))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Tasks updated successfully",
+		"tasks":   result.Tasks,
+		"failed":  result.Failed,
+		"count":   len(result.Tasks),
+	})
+}
+
+// GetActivity timeline riwayat perubahan task
+// GET /api/tasks/:id/activity?limit=50&offset=0
+func (h *TaskHandler) GetActivity(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	activities, total, err := h.taskService.GetActivity(userID, c.Params("id"), c.QueryInt("limit", 50), c.QueryInt("offset", 0))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case err.Error() == "task not found":
+			status = fiber.StatusNotFound
+		case strings.HasPrefix(err.Error(), "unauthorized"):
+			status = fiber.StatusForbidden
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"activity": activities,
+		"total":    total,
+	})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/services"
+)
+
+type TaskSnoozeHandler struct {
+	snoozeService *services.TaskSnoozeService
+}
+
+func NewTaskSnoozeHandler(snoozeService *services.TaskSnoozeService) *TaskSnoozeHandler {
+	return &TaskSnoozeHandler{snoozeService: snoozeService}
+}
+
+// Snooze menunda pengingat task (dipanggil juga dari tombol aksi notifikasi)
+// POST /api/tasks/:id/snooze {"duration": "10m" | "1h" | "tomorrow"}
+func (h *TaskSnoozeHandler) Snooze(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req struct {
+		Duration models.SnoozeOption `json:"duration"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	snooze, err := h.snoozeService.Snooze(userID, c.Params("id"), req.Duration, time.Now())
+	if err != nil {
+		return c.Status(snoozeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Reminder snoozed",
+		"snooze":  snooze,
+	})
+}
+
+// GetSnooze snooze yang sedang berjalan untuk task (null jika tidak ada)
+// GET /api/tasks/:id/snooze
+func (h *TaskSnoozeHandler) GetSnooze(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	snooze, err := h.snoozeService.GetSnooze(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(snoozeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"snooze": snooze,
+	})
+}
+
+// Unsnooze membatalkan snooze, reminder reguler berjalan lagi
+// DELETE /api/tasks/:id/snooze
+func (h *TaskSnoozeHandler) Unsnooze(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	if err := h.snoozeService.Unsnooze(userID, c.Params("id")); err != nil {
+		return c.Status(snoozeErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Snooze cancelled",
+	})
+}
+
+func snoozeErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		return fiber.StatusNotFound
+	case strings.HasPrefix(message, "unauthorized"):
+		return fiber.StatusForbidden
+	case strings.HasPrefix(message, "invalid"), strings.HasPrefix(message, "task already"):
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/services"
+)
+
+type WorkspaceHandler struct {
+	workspaceService    *services.WorkspaceService
+	taskService         *services.TaskService
+	categoryService     *services.CategoryService
+	teamWorkloadService *services.TeamWorkloadService
+}
+
+func NewWorkspaceHandler(
+	workspaceService *services.WorkspaceService,
+	taskService *services.TaskService,
+	categoryService *services.CategoryService,
+	teamWorkloadService *services.TeamWorkloadService,
+) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceService:    workspaceService,
+		taskService:         taskService,
+		categoryService:     categoryService,
+		teamWorkloadService: teamWorkloadService,
+	}
+}
+
+// GetWorkspaces mendapatkan workspace milik / diikuti user
+// GET /api/workspaces
+func (h *WorkspaceHandler) GetWorkspaces(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	workspaces, err := h.workspaceService.GetWorkspaces(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch workspaces",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"workspaces": workspaces,
+		"count":      len(workspaces),
+	})
+}
+
+// CreateWorkspace membuat workspace baru
+// POST /api/workspaces
+func (h *WorkspaceHandler) CreateWorkspace(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.WorkspaceDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	workspace, err := h.workspaceService.CreateWorkspace(userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":   "Workspace created successfully",
+		"workspace": workspace,
+	})
+}
+
+// GetWorkspace mendapatkan detail workspace beserta member
+// GET /api/workspaces/:id
+func (h *WorkspaceHandler) GetWorkspace(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	workspace, err := h.workspaceService.GetWorkspace(c.Params("id"), userID)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"workspace": workspace,
+	})
+}
+
+// UpdateWorkspace memperbarui workspace
+// PUT /api/workspaces/:id
+func (h *WorkspaceHandler) UpdateWorkspace(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.WorkspaceDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	workspace, err := h.workspaceService.UpdateWorkspace(c.Params("id"), userID, req)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":   "Workspace updated successfully",
+		"workspace": workspace,
+	})
+}
+
+// DeleteWorkspace menghapus workspace
+// DELETE /api/workspaces/:id
+func (h *WorkspaceHandler) DeleteWorkspace(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	if err := h.workspaceService.DeleteWorkspace(c.Params("id"), userID); err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Workspace deleted successfully",
+	})
+}
+
+// ==================== MEMBERS ====================
+
+// GetMembers mendapatkan member workspace
+// GET /api/workspaces/:id/members
+func (h *WorkspaceHandler) GetMembers(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	members, err := h.workspaceService.GetMembers(c.Params("id"), userID)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"members": members,
+		"count":   len(members),
+	})
+}
+
+// UpdateMemberRole mengubah role member
+// PUT /api/workspaces/:id/members/:userId
+func (h *WorkspaceHandler) UpdateMemberRole(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req struct {
+		Role models.WorkspaceRole `json:"role"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.workspaceService.UpdateMemberRole(c.Params("id"), userID, c.Params("userId"), req.Role); err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Member role updated successfully",
+	})
+}
+
+// RemoveMember mengeluarkan member (atau keluar dari workspace jika userId = diri sendiri)
+// DELETE /api/workspaces/:id/members/:userId
+func (h *WorkspaceHandler) RemoveMember(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	if err := h.workspaceService.RemoveMember(c.Params("id"), userID, c.Params("userId")); err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Member removed successfully",
+	})
+}
+
+// ==================== INVITATIONS ====================
+
+// InviteMember mengundang user lewat email
+// POST /api/workspaces/:id/invitations
+func (h *WorkspaceHandler) InviteMember(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var req services.InviteMemberDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	invitation, err := h.workspaceService.InviteMember(c.Params("id"), userID, req)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":    "Invitation sent successfully",
+		"invitation": invitation,
+	})
+}
+
+// GetInvitations mendapatkan undangan workspace
+// GET /api/workspaces/:id/invitations
+func (h *WorkspaceHandler) GetInvitations(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	invitations, err := h.workspaceService.GetInvitations(c.Params("id"), userID)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"invitations": invitations,
+	})
+}
+
+// RevokeInvitation membatalkan undangan
+// DELETE /api/workspaces/:id/invitations/:invitationId
+func (h *WorkspaceHandler) RevokeInvitation(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	if err := h.workspaceService.RevokeInvitation(c.Params("id"), userID, c.Params("invitationId")); err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Invitation revoked successfully",
+	})
+}
+
+// GetMyInvitations mendapatkan undangan pending untuk user
+// GET /api/workspaces/invitations
+func (h *WorkspaceHandler) GetMyInvitations(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	invitations, err := h.workspaceService.GetMyInvitations(userID)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"invitations": invitations,
+	})
+}
+
+// AcceptInvitation menerima undangan
+// POST /api/workspaces/invitations/:invitationId/accept
+func (h *WorkspaceHandler) AcceptInvitation(c *fiber.Ctx) error {
+	return h.respondInvitation(c, true, "Invitation accepted successfully")
+}
+
+// DeclineInvitation menolak undangan
+// POST /api/workspaces/invitations/:invitationId/decline
+func (h *WorkspaceHandler) DeclineInvitation(c *fiber.Ctx) error {
+	return h.respondInvitation(c, false, "Invitation declined successfully")
+}
+
+func (h *WorkspaceHandler) respondInvitation(c *fiber.Ctx, accept bool, message string) error {
+	userID := c.Locals("user_id").(string)
+
+	invitation, err := h.workspaceService.RespondInvitation(c.Params("invitationId"), userID, accept)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":    message,
+		"invitation": invitation,
+	})
+}
+
+// ==================== SHARED TASKS & CATEGORIES ====================
+
+// GetTasks mendapatkan task bersama di workspace
+// GET /api/workspaces/:id/tasks?category_id=xxx
+func (h *WorkspaceHandler) GetTasks(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	var categoryID *string
+	if value := c.Query("category_id"); value != "" {
+		categoryID = &value
+	}
+
+	tasks, err := h.taskService.GetWorkspaceTasks(userID, c.Params("id"), categoryID)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tasks": tasks,
+		"count": len(tasks),
+	})
+}
+
+// GetCategories mendapatkan kategori bersama di workspace
+// GET /api/workspaces/:id/categories
+func (h *WorkspaceHandler) GetCategories(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	categories, err := h.categoryService.GetWorkspaceCategories(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"categories": categories,
+		"count":      len(categories),
+	})
+}
+
+// GetTeamWorkload dashboard beban kerja tim (owner/admin workspace)
+// GET /api/workspaces/:id/workload?start=2026-01-01&end=2026-01-31
+func (h *WorkspaceHandler) GetTeamWorkload(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(string)
+
+	// Default: 30 hari terakhir termasuk hari ini
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, 0, -29)
+
+	if startStr := c.Query("start"); startStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", startStr, now.Location())
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid start date format (use YYYY-MM-DD)",
+			})
+		}
+		start = parsed
+	}
+	if endStr := c.Query("end"); endStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", endStr, now.Location())
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid end date format (use YYYY-MM-DD)",
+			})
+		}
+		end = parsed
+	}
+
+	// Set time to end of day for end date
+	end = time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 59, 0, end.Location())
+
+	workload, err := h.teamWorkloadService.GetTeamWorkload(c.Params("id"), userID, start, end)
+	if err != nil {
+		return c.Status(workspaceErrorStatus(err)).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"workload": workload,
+	})
+}
+
+func workspaceErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		return fiber.StatusNotFound
+	case strings.HasPrefix(message, "unauthorized"), strings.HasPrefix(message, "access denied"):
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusBadRequest
+}
@@ -15,6 +15,9 @@ const (
 	MessageTypeTip     MessageType = "tip"
 	MessageTypeAlert   MessageType = "alert"
 	MessageTypeUpdate  MessageType = "update"
+	MessageTypeReport  MessageType = "report"
+	MessageTypeLeave   MessageType = "leave"
+	MessageTypeTask    MessageType = "task"
 )
 
 type BotMessage struct {
@@ -8,13 +8,14 @@ import (
 )
 
 type Category struct {
-	ID        string    `gorm:"type:varchar(36);primaryKey" json:"id"`
-	UserID    string    `gorm:"type:varchar(36);not null;index:idx_user_id" json:"user_id"`
-	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
-	Color     string    `gorm:"type:varchar(20);default:'#6C5CE7'" json:"color"`
-	IsDefault bool      `gorm:"default:false" json:"is_default"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID      string    `gorm:"type:varchar(36);not null;index:idx_user_id" json:"user_id"`
+	WorkspaceID *string   `gorm:"type:varchar(36);index" json:"workspace_id,omitempty"` // NULL = kategori pribadi
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Color       string    `gorm:"type:varchar(20);default:'#6C5CE7'" json:"color"`
+	IsDefault   bool      `gorm:"default:false" json:"is_default"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 
 	// Relations
 	User  User   `gorm:"foreignKey:UserID" json:"-"`
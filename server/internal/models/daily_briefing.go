@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DailyBriefingKind string
+
+const (
+	DailyBriefingAgenda DailyBriefingKind = "agenda" // rencana pagi
+	DailyBriefingDigest DailyBriefingKind = "digest" // ringkasan sore/malam
+)
+
+// DailyBriefingLog penanda agenda/digest yang sudah diproses per user per tanggal lokal user,
+// agar tiap briefing hanya dikirim sekali walau scheduler jalan tiap menit atau restart
+type DailyBriefingLog struct {
+	ID        string            `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID    string            `gorm:"type:varchar(36);not null;uniqueIndex:idx_daily_briefing_once,priority:1" json:"user_id"`
+	Kind      DailyBriefingKind `gorm:"type:varchar(10);not null;uniqueIndex:idx_daily_briefing_once,priority:2" json:"kind"`
+	Date      string            `gorm:"type:varchar(10);not null;uniqueIndex:idx_daily_briefing_once,priority:3;index" json:"date"` // YYYY-MM-DD di zona waktu user
+	Skipped   bool              `gorm:"default:false" json:"skipped"`                                                               // tidak ada isi untuk dikirim
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (l *DailyBriefingLog) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
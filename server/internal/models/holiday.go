@@ -7,15 +7,47 @@ import (
 	"gorm.io/gorm"
 )
 
+type HolidayType string
+
+const (
+	HolidayTypeNational        HolidayType = "national"
+	HolidayTypeRegional        HolidayType = "regional"
+	HolidayTypeCollectiveLeave HolidayType = "collective_leave" // cuti bersama
+	HolidayTypePersonal        HolidayType = "personal"
+)
+
 type Holiday struct {
-	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
-	UserID      *string   `gorm:"type:varchar(36)" json:"user_id,omitempty"` // NULL for national holidays
-	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
-	Date        time.Time `gorm:"type:date;not null" json:"date"`
-	IsNational  bool      `gorm:"default:false" json:"is_national"`
-	Description *string   `gorm:"type:text" json:"description,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string      `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID      *string     `gorm:"type:varchar(36)" json:"user_id,omitempty"` // NULL for national holidays
+	Name        string      `gorm:"type:varchar(255);not null" json:"name"`
+	Date        time.Time   `gorm:"type:date;not null" json:"date"`
+	IsNational  bool        `gorm:"default:false" json:"is_national"`
+	Type        HolidayType `gorm:"type:varchar(20);index" json:"type"`
+	Region      *string     `gorm:"type:varchar(10);index" json:"region,omitempty"` // ISO 3166-2, e.g. ID-BA; NULL = berlaku nasional
+	Source      string      `gorm:"type:varchar(100)" json:"source,omitempty"`      // bundled, ics:<host>/<hash>, manual
+	Description *string     `gorm:"type:text" json:"description,omitempty"`
+
+	// Recurring personal holiday (ulang tahun, anniversary) - berulang setiap tahun di tanggal yang sama
+	IsRecurring        bool       `gorm:"default:false;index" json:"is_recurring"`
+	ReminderDaysBefore *int       `json:"reminder_days_before,omitempty"` // NULL = tanpa reminder
+	LastRemindedFor    *time.Time `gorm:"type:date" json:"-"`             // occurrence terakhir yang sudah diingatkan
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OccurrenceIn tanggal holiday recurring di tahun tertentu.
+// 29 Februari jatuh ke 28 Februari di tahun non-kabisat.
+func (h *Holiday) OccurrenceIn(year int) time.Time {
+	month, day := h.Date.Month(), h.Date.Day()
+	if month == time.February && day == 29 && !isLeapYear(year) {
+		day = 28
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, h.Date.Location())
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
 }
 
 // BeforeCreate hook untuk generate UUID
@@ -26,16 +58,38 @@ func (h *Holiday) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// HolidayKind tipe holiday, dengan fallback untuk data lama yang belum punya kolom type
+func (h *Holiday) HolidayKind() HolidayType {
+	if h.Type != "" {
+		return h.Type
+	}
+	if h.UserID != nil {
+		return HolidayTypePersonal
+	}
+	if h.Region != nil && *h.Region != "" {
+		return HolidayTypeRegional
+	}
+	return HolidayTypeNational
+}
+
 // HolidayResponse untuk response API
 type HolidayResponse struct {
-	ID          string    `json:"id"`
-	UserID      *string   `json:"user_id,omitempty"`
-	Name        string    `json:"name"`
-	Date        time.Time `json:"date"`
-	IsNational  bool      `json:"is_national"`
-	Description *string   `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string      `json:"id"`
+	UserID      *string     `json:"user_id,omitempty"`
+	Name        string      `json:"name"`
+	Date        time.Time   `json:"date"`
+	IsNational  bool        `json:"is_national"`
+	Type        HolidayType `json:"type"`
+	Region      *string     `json:"region,omitempty"`
+	Description *string     `json:"description,omitempty"`
+
+	IsRecurring        bool       `json:"is_recurring"`
+	ReminderDaysBefore *int       `json:"reminder_days_before,omitempty"`
+	OriginalDate       *time.Time `json:"original_date,omitempty"` // tanggal asli untuk occurrence recurring
+	YearsSince         int        `json:"years_since,omitempty"`   // ulang tahun ke-N
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func (h *Holiday) ToResponse() HolidayResponse {
@@ -45,8 +99,24 @@ func (h *Holiday) ToResponse() HolidayResponse {
 		Name:        h.Name,
 		Date:        h.Date,
 		IsNational:  h.IsNational,
+		Type:        h.HolidayKind(),
+		Region:      h.Region,
 		Description: h.Description,
-		CreatedAt:   h.CreatedAt,
-		UpdatedAt:   h.UpdatedAt,
+
+		IsRecurring:        h.IsRecurring,
+		ReminderDaysBefore: h.ReminderDaysBefore,
+
+		CreatedAt: h.CreatedAt,
+		UpdatedAt: h.UpdatedAt,
 	}
 }
+
+// ToOccurrenceResponse response untuk satu occurrence holiday recurring
+func (h *Holiday) ToOccurrenceResponse(occurrence time.Time) HolidayResponse {
+	response := h.ToResponse()
+	original := h.Date
+	response.Date = occurrence
+	response.OriginalDate = &original
+	response.YearsSince = occurrence.Year() - h.Date.Year()
+	return response
+}
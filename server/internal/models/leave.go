@@ -7,16 +7,78 @@ import (
 	"gorm.io/gorm"
 )
 
+type LeaveStatus string
+
+const (
+	LeaveStatusPending   LeaveStatus = "pending"
+	LeaveStatusApproved  LeaveStatus = "approved"
+	LeaveStatusRejected  LeaveStatus = "rejected"
+	LeaveStatusCancelled LeaveStatus = "cancelled"
+)
+
+// IsValid mengecek status cuti yang dikenal
+func (s LeaveStatus) IsValid() bool {
+	switch s {
+	case LeaveStatusPending, LeaveStatusApproved, LeaveStatusRejected, LeaveStatusCancelled:
+		return true
+	}
+	return false
+}
+
+type LeaveType string
+
+const (
+	LeaveTypeAnnual    LeaveType = "annual"
+	LeaveTypeSick      LeaveType = "sick"
+	LeaveTypeUnpaid    LeaveType = "unpaid"
+	LeaveTypeMaternity LeaveType = "maternity"
+	LeaveTypeCustom    LeaveType = "custom"
+)
+
+// IsValid mengecek tipe cuti yang dikenal
+func (t LeaveType) IsValid() bool {
+	switch t {
+	case LeaveTypeAnnual, LeaveTypeSick, LeaveTypeUnpaid, LeaveTypeMaternity, LeaveTypeCustom:
+		return true
+	}
+	return false
+}
+
+// LeaveHalfDay bagian hari untuk cuti setengah hari ("" = seharian)
+type LeaveHalfDay string
+
+const (
+	LeaveFullDay       LeaveHalfDay = ""
+	LeaveHalfMorning   LeaveHalfDay = "morning"
+	LeaveHalfAfternoon LeaveHalfDay = "afternoon"
+)
+
+// IsValid mengecek nilai half day yang dikenal
+func (h LeaveHalfDay) IsValid() bool {
+	switch h {
+	case LeaveFullDay, LeaveHalfMorning, LeaveHalfAfternoon:
+		return true
+	}
+	return false
+}
+
 type Leave struct {
-	ID         string     `gorm:"type:varchar(36);primaryKey" json:"id"`
-	UserID     string     `gorm:"type:varchar(36);not null" json:"user_id"`
-	Date       time.Time  `gorm:"type:date;not null" json:"date"`
-	Reason     string     `gorm:"type:varchar(255);not null" json:"reason"`
-	IsApproved bool       `gorm:"default:false" json:"is_approved"`
-	ApprovedBy *string    `gorm:"type:varchar(36)" json:"approved_by,omitempty"`
-	ApprovedAt *time.Time `json:"approved_at,omitempty"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
+	ID            string       `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID        string       `gorm:"type:varchar(36);not null" json:"user_id"`
+	Date          time.Time    `gorm:"type:date;not null" json:"date"`            // tanggal mulai
+	EndDate       *time.Time   `gorm:"type:date;index" json:"end_date,omitempty"` // nil = cuti satu hari (data lama)
+	HalfDay       LeaveHalfDay `gorm:"type:varchar(10);default:''" json:"half_day,omitempty"`
+	Type          LeaveType    `gorm:"type:varchar(20);default:'annual';index" json:"type"`
+	CustomType    *string      `gorm:"type:varchar(50)" json:"custom_type,omitempty"`
+	WorkingDays   float64      `gorm:"type:decimal(5,1);default:1" json:"working_days"`
+	Reason        string       `gorm:"type:varchar(255);not null" json:"reason"`
+	IsApproved    bool         `gorm:"default:false" json:"is_approved"`
+	ApprovedBy    *string      `gorm:"type:varchar(36)" json:"approved_by,omitempty"`
+	ApprovedAt    *time.Time   `json:"approved_at,omitempty"`
+	Status        LeaveStatus  `gorm:"type:varchar(20);default:'approved';index" json:"status"` // data lama dianggap sudah approved
+	StatusComment *string      `gorm:"type:text" json:"status_comment,omitempty"`               // komentar transisi terakhir
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
 }
 
 // BeforeCreate hook untuk generate UUID
@@ -27,29 +89,101 @@ func (l *Leave) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// LastDate tanggal terakhir cuti (inklusif)
+func (l *Leave) LastDate() time.Time {
+	if l.EndDate != nil && l.EndDate.After(l.Date) {
+		return *l.EndDate
+	}
+	return l.Date
+}
+
+// IsHalfDay cuti setengah hari
+func (l *Leave) IsHalfDay() bool {
+	return l.HalfDay == LeaveHalfMorning || l.HalfDay == LeaveHalfAfternoon
+}
+
 // LeaveResponse untuk response API
 type LeaveResponse struct {
-	ID         string     `json:"id"`
-	UserID     string     `json:"user_id"`
-	Date       time.Time  `json:"date"`
-	Reason     string     `json:"reason"`
-	IsApproved bool       `json:"is_approved"`
-	ApprovedBy *string    `json:"approved_by,omitempty"`
-	ApprovedAt *time.Time `json:"approved_at,omitempty"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
+	ID            string       `json:"id"`
+	UserID        string       `json:"user_id"`
+	Date          time.Time    `json:"date"`
+	StartDate     time.Time    `json:"start_date"`
+	EndDate       time.Time    `json:"end_date"`
+	HalfDay       LeaveHalfDay `json:"half_day,omitempty"`
+	Type          LeaveType    `json:"type"`
+	CustomType    *string      `json:"custom_type,omitempty"`
+	WorkingDays   float64      `json:"working_days"`
+	Reason        string       `json:"reason"`
+	IsApproved    bool         `json:"is_approved"`
+	ApprovedBy    *string      `json:"approved_by,omitempty"`
+	ApprovedAt    *time.Time   `json:"approved_at,omitempty"`
+	Status        LeaveStatus  `json:"status"`
+	StatusComment *string      `json:"status_comment,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
 }
 
 func (l *Leave) ToResponse() LeaveResponse {
+	leaveType := l.Type
+	if leaveType == "" {
+		leaveType = LeaveTypeAnnual
+	}
+
 	return LeaveResponse{
-		ID:         l.ID,
-		UserID:     l.UserID,
-		Date:       l.Date,
-		Reason:     l.Reason,
-		IsApproved: l.IsApproved,
-		ApprovedBy: l.ApprovedBy,
-		ApprovedAt: l.ApprovedAt,
-		CreatedAt:  l.CreatedAt,
-		UpdatedAt:  l.UpdatedAt,
+		ID:            l.ID,
+		UserID:        l.UserID,
+		Date:          l.Date,
+		StartDate:     l.Date,
+		EndDate:       l.LastDate(),
+		HalfDay:       l.HalfDay,
+		Type:          leaveType,
+		CustomType:    l.CustomType,
+		WorkingDays:   l.WorkingDays,
+		Reason:        l.Reason,
+		IsApproved:    l.IsApproved || l.Status == LeaveStatusApproved,
+		ApprovedBy:    l.ApprovedBy,
+		ApprovedAt:    l.ApprovedAt,
+		Status:        l.Status,
+		StatusComment: l.StatusComment,
+		CreatedAt:     l.CreatedAt,
+		UpdatedAt:     l.UpdatedAt,
+	}
+}
+
+// LeaveApprover approver cuti user, ditunjuk oleh admin / manager workspace
+type LeaveApprover struct {
+	ID         string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID     string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_leave_approver_pair" json:"user_id"`
+	ApproverID string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_leave_approver_pair;index" json:"approver_id"`
+	AssignedBy *string   `gorm:"type:varchar(36)" json:"assigned_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	Approver *User `gorm:"foreignKey:ApproverID" json:"approver,omitempty"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (a *LeaveApprover) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// LeaveTransition riwayat perubahan status cuti beserta komentarnya
+type LeaveTransition struct {
+	ID         string      `gorm:"type:varchar(36);primaryKey" json:"id"`
+	LeaveID    string      `gorm:"type:varchar(36);not null;index" json:"leave_id"`
+	ActorID    string      `gorm:"type:varchar(36);not null" json:"actor_id"`
+	FromStatus LeaveStatus `gorm:"type:varchar(20)" json:"from_status,omitempty"`
+	ToStatus   LeaveStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	Comment    *string     `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (t *LeaveTransition) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
 	}
+	return nil
 }
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LeaveAccrualMode cara jatah cuti diberikan
+type LeaveAccrualMode string
+
+const (
+	LeaveAccrualYearly  LeaveAccrualMode = "yearly"  // jatah penuh di awal tahun
+	LeaveAccrualMonthly LeaveAccrualMode = "monthly" // jatah/12 setiap awal bulan
+)
+
+// IsValid mengecek mode accrual yang dikenal
+func (m LeaveAccrualMode) IsValid() bool {
+	return m == LeaveAccrualYearly || m == LeaveAccrualMonthly
+}
+
+// LeaveLedgerKind jenis entri ledger saldo cuti
+type LeaveLedgerKind string
+
+const (
+	LeaveLedgerAllotment  LeaveLedgerKind = "allotment"
+	LeaveLedgerAccrual    LeaveLedgerKind = "accrual"
+	LeaveLedgerCarryOver  LeaveLedgerKind = "carry_over"
+	LeaveLedgerAdjustment LeaveLedgerKind = "adjustment"
+)
+
+// LeavePolicy aturan saldo cuti per user dan tipe cuti.
+// Tipe tanpa policy tidak dibatasi (kecuali annual yang punya default).
+type LeavePolicy struct {
+	ID              string           `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID          string           `gorm:"type:varchar(36);not null;uniqueIndex:idx_leave_policy_user_type" json:"user_id"`
+	Type            LeaveType        `gorm:"type:varchar(20);not null;uniqueIndex:idx_leave_policy_user_type" json:"type"`
+	YearlyAllotment float64          `gorm:"type:decimal(5,1);not null" json:"yearly_allotment"`
+	AccrualMode     LeaveAccrualMode `gorm:"type:varchar(10);default:'yearly'" json:"accrual_mode"`
+	CarryOverCap    float64          `gorm:"type:decimal(5,1);default:0" json:"carry_over_cap"` // 0 = saldo tidak dibawa ke tahun berikutnya
+	UpdatedBy       *string          `gorm:"type:varchar(36)" json:"updated_by,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *LeavePolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// LeaveLedgerEntry mutasi saldo cuti (positif = tambah, negatif = kurang)
+type LeaveLedgerEntry struct {
+	ID     string          `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID string          `gorm:"type:varchar(36);not null;index:idx_leave_ledger_lookup;uniqueIndex:idx_leave_ledger_period" json:"user_id"`
+	Type   LeaveType       `gorm:"type:varchar(20);not null;index:idx_leave_ledger_lookup;uniqueIndex:idx_leave_ledger_period" json:"type"`
+	Year   int             `gorm:"not null;index:idx_leave_ledger_lookup;uniqueIndex:idx_leave_ledger_period" json:"year"`
+	Kind   LeaveLedgerKind `gorm:"type:varchar(20);not null;uniqueIndex:idx_leave_ledger_period" json:"kind"`
+	// "2026" / "2026-03" untuk entri otomatis (unik per periode); NULL untuk penyesuaian manual
+	Period    *string   `gorm:"type:varchar(7);uniqueIndex:idx_leave_ledger_period" json:"period,omitempty"`
+	Days      float64   `gorm:"type:decimal(5,2);not null" json:"days"`
+	Note      *string   `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedBy *string   `gorm:"type:varchar(36)" json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (e *LeaveLedgerEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebPushSubscription subscription Push API dari browser (channel web_push)
+type WebPushSubscription struct {
+	ID        string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID    string    `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Endpoint  string    `gorm:"type:varchar(500);not null;uniqueIndex" json:"endpoint"`
+	P256dh    string    `gorm:"type:varchar(255);not null" json:"-"` // public key browser (base64url)
+	Auth      string    `gorm:"type:varchar(64);not null" json:"-"`  // auth secret (base64url)
+	UserAgent *string   `gorm:"type:varchar(255)" json:"user_agent,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (w *WebPushSubscription) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// NotificationWebhook URL milik user yang menerima notifikasi (channel webhook).
+// Payload ditandatangani HMAC-SHA256 dengan Secret.
+type NotificationWebhook struct {
+	UserID    string    `gorm:"type:varchar(36);primaryKey" json:"user_id"`
+	URL       string    `gorm:"type:varchar(500);not null" json:"url"`
+	Secret    string    `gorm:"type:varchar(64);not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationStatusQueued NotificationStatus = "queued" // menunggu dikirim
+	NotificationStatusSent   NotificationStatus = "sent"
+	NotificationStatusFailed NotificationStatus = "failed" // gagal, akan dicoba lagi pada next_attempt_at
+	NotificationStatusDead   NotificationStatus = "dead"   // berhenti dicoba (batas percobaan / error permanen)
+)
+
+// NotificationOutbox notifikasi yang disimpan dulu sebelum dikirim, agar tidak hilang saat
+// restart dan bisa di-retry. IdempotencyKey mencegah notifikasi yang sama masuk dua kali.
+type NotificationOutbox struct {
+	ID       string            `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID   string            `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Type     string            `gorm:"type:varchar(50);not null;index" json:"type"`
+	Title    string            `gorm:"type:varchar(255);not null" json:"title"`
+	Body     string            `gorm:"type:text" json:"body"`
+	Data     map[string]string `gorm:"serializer:json;type:text" json:"data,omitempty"`
+	Priority string            `gorm:"type:varchar(10);default:'normal'" json:"priority"` // high, normal
+	Color    string            `gorm:"type:varchar(10)" json:"-"`
+	// Channels tujuan sesuai preferensi user; DeliveredChannels yang sudah berhasil (tidak dikirim ulang saat retry)
+	Channels          []NotificationChannel `gorm:"serializer:json;type:text" json:"channels"`
+	DeliveredChannels []NotificationChannel `gorm:"serializer:json;type:text" json:"delivered_channels,omitempty"`
+	IdempotencyKey    *string               `gorm:"type:varchar(191);uniqueIndex" json:"-"`
+	Status            NotificationStatus    `gorm:"type:varchar(10);not null;default:'queued';index:idx_outbox_due,priority:1" json:"status"`
+	Attempts          int                   `gorm:"default:0" json:"attempts"`
+	NextAttemptAt     time.Time             `gorm:"index:idx_outbox_due,priority:2" json:"next_attempt_at"`
+	LastError         *string               `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt            *time.Time            `json:"sent_at,omitempty"`
+	LockedUntil       *time.Time            `json:"-"` // lease worker yang sedang memproses
+	ClaimToken        *string               `gorm:"type:varchar(36);index" json:"-"`
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (n *NotificationOutbox) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == "" {
+		n.ID = uuid.New().String()
+	}
+	return nil
+}
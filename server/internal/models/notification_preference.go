@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationChannel string
+
+const (
+	NotificationChannelPush    NotificationChannel = "push"
+	NotificationChannelEmail   NotificationChannel = "email"
+	NotificationChannelInApp   NotificationChannel = "in_app"   // bot message di aplikasi
+	NotificationChannelWebPush NotificationChannel = "web_push" // browser (VAPID)
+	NotificationChannelWebhook NotificationChannel = "webhook"  // URL milik user, mis. Slack/Zapier
+)
+
+// IsValid mengecek apakah channel dikenal
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case NotificationChannelPush, NotificationChannelEmail, NotificationChannelInApp,
+		NotificationChannelWebPush, NotificationChannelWebhook:
+		return true
+	}
+	return false
+}
+
+// Jenis notifikasi yang bisa diatur user
+const (
+	NotificationTypeTaskReminder    = "task_reminder"
+	NotificationTypeWeatherAlert    = "weather_alert"
+	NotificationTypeHealth          = "health_recommendation"
+	NotificationTypeHolidayReminder = "holiday_reminder"
+	NotificationTypeLeaveUpdate     = "leave_update"
+	NotificationTypeTaskAssignment  = "task_assignment"
+	NotificationTypeCommentMention  = "comment_mention"
+	NotificationTypeDailyAgenda     = "daily_agenda"
+	NotificationTypeDailyDigest     = "daily_digest"
+	NotificationTypeTaskOverdue     = "task_overdue"
+)
+
+// NotificationTypes urutan tampil jenis notifikasi di pengaturan
+var NotificationTypes = []string{
+	NotificationTypeTaskReminder,
+	NotificationTypeTaskOverdue,
+	NotificationTypeTaskAssignment,
+	NotificationTypeCommentMention,
+	NotificationTypeLeaveUpdate,
+	NotificationTypeHolidayReminder,
+	NotificationTypeHealth,
+	NotificationTypeWeatherAlert,
+	NotificationTypeDailyAgenda,
+	NotificationTypeDailyDigest,
+}
+
+// IsNotificationType mengecek apakah jenis notifikasi dikenal
+func IsNotificationType(notificationType string) bool {
+	for _, t := range NotificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// Default pengaturan jika user belum mengatur
+const (
+	DefaultNotificationTimeZone = "Asia/Jakarta"
+	DefaultAgendaTime           = "07:00"
+	DefaultDigestTime           = "18:00"
+)
+
+// NotificationSettings pengaturan notifikasi umum user: zona waktu & jam tenang
+type NotificationSettings struct {
+	UserID            string    `gorm:"type:varchar(36);primaryKey" json:"user_id"`
+	TimeZone          string    `gorm:"type:varchar(64);not null" json:"time_zone"` // IANA, mis. Asia/Jakarta
+	QuietHoursEnabled bool      `gorm:"not null" json:"quiet_hours_enabled"`
+	QuietHoursStart   string    `gorm:"type:varchar(5);not null" json:"quiet_hours_start"` // HH:MM
+	QuietHoursEnd     string    `gorm:"type:varchar(5);not null" json:"quiet_hours_end"`   // HH:MM, boleh melewati tengah malam
+	AgendaTime        string    `gorm:"type:varchar(5)" json:"agenda_time"`                // HH:MM, agenda pagi
+	DigestTime        string    `gorm:"type:varchar(5)" json:"digest_time"`                // HH:MM, ringkasan akhir hari
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// NotificationPreference pengaturan per jenis notifikasi. Tidak ada baris = default
+// (aktif, lewat push, mengikuti jam tenang).
+type NotificationPreference struct {
+	ID               string                `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID           string                `gorm:"type:varchar(36);not null;uniqueIndex:idx_notification_pref" json:"user_id"`
+	Type             string                `gorm:"type:varchar(50);not null;uniqueIndex:idx_notification_pref" json:"type"`
+	Enabled          bool                  `gorm:"not null" json:"enabled"`
+	Channels         []NotificationChannel `gorm:"serializer:json;type:text" json:"channels"`
+	BypassQuietHours bool                  `gorm:"not null" json:"bypass_quiet_hours"` // tetap dikirim saat jam tenang
+	CreatedAt        time.Time             `json:"created_at"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *NotificationPreference) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OverdueAction tindakan untuk task yang lewat deadline dan belum selesai
+type OverdueAction string
+
+const (
+	OverdueActionNone       OverdueAction = "none"        // biarkan saja
+	OverdueActionRenotify   OverdueAction = "renotify"    // ingatkan lagi tiap interval
+	OverdueActionRollover   OverdueAction = "rollover"    // pindahkan deadline ke hari ini
+	OverdueActionMarkMissed OverdueAction = "mark_missed" // tandai terlewat
+)
+
+// IsValid mengecek apakah action dikenal
+func (a OverdueAction) IsValid() bool {
+	switch a {
+	case OverdueActionNone, OverdueActionRenotify, OverdueActionRollover, OverdueActionMarkMissed:
+		return true
+	}
+	return false
+}
+
+// OverduePolicy aturan overdue milik user; CategoryID NULL = default user,
+// policy kategori menimpa default untuk task di kategori tersebut
+type OverduePolicy struct {
+	ID                   string        `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID               string        `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	CategoryID           *string       `gorm:"type:varchar(36);index" json:"category_id,omitempty"`
+	Action               OverdueAction `gorm:"type:varchar(20);not null" json:"action"`
+	RenotifyIntervalDays int           `gorm:"not null" json:"renotify_interval_days"` // renotify: jeda antar pengingat
+	MaxRenotify          int           `gorm:"not null" json:"max_renotify"`           // renotify: 0 = tanpa batas
+	CreatedAt            time.Time     `json:"created_at"`
+	UpdatedAt            time.Time     `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *OverduePolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// OverdueActionLog catatan tindakan job overdue malam hari
+type OverdueActionLog struct {
+	ID          string        `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TaskID      string        `gorm:"type:varchar(36);not null;index" json:"task_id"`
+	UserID      string        `gorm:"type:varchar(36);not null;index" json:"user_id"` // pemilik task
+	TaskTitle   string        `gorm:"type:varchar(255)" json:"task_title"`
+	Action      OverdueAction `gorm:"type:varchar(20);not null" json:"action"`
+	Deadline    time.Time     `json:"deadline"`               // deadline yang terlewat
+	NewDeadline *time.Time    `json:"new_deadline,omitempty"` // rollover
+	Note        *string       `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedAt   time.Time     `gorm:"index" json:"created_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (l *OverdueActionLog) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReportPeriod string
+
+const (
+	ReportPeriodWeekly  ReportPeriod = "weekly"
+	ReportPeriodMonthly ReportPeriod = "monthly"
+)
+
+// CategoryStat ringkasan task per kategori dalam satu periode laporan
+type CategoryStat struct {
+	CategoryName string `json:"category_name"`
+	Created      int    `json:"created"`
+	Completed    int    `json:"completed"`
+}
+
+// ProductivityStats angka-angka yang ditampilkan di laporan produktivitas
+type ProductivityStats struct {
+	TasksCreated        int            `json:"tasks_created"`
+	TasksCompleted      int            `json:"tasks_completed"`
+	CompletedWithDue    int            `json:"completed_with_deadline"`
+	CompletedOnTime     int            `json:"completed_on_time"`
+	OnTimeRate          float64        `json:"on_time_rate"`           // persen
+	AverageLatenessHour float64        `json:"average_lateness_hours"` // rata-rata keterlambatan task yang telat
+	OvertimeHours       float64        `json:"overtime_hours"`
+	WeekendHours        float64        `json:"weekend_hours"`
+	CategoryBreakdown   []CategoryStat `json:"category_breakdown"`
+}
+
+// ProductivityReport laporan produktivitas mingguan/bulanan yang sudah di-generate
+type ProductivityReport struct {
+	ID          string            `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID      string            `gorm:"type:varchar(36);not null;uniqueIndex:idx_report_user_period" json:"user_id"`
+	Period      ReportPeriod      `gorm:"type:varchar(10);not null;uniqueIndex:idx_report_user_period" json:"period"`
+	PeriodStart time.Time         `gorm:"type:date;not null;uniqueIndex:idx_report_user_period" json:"period_start"`
+	PeriodEnd   time.Time         `gorm:"type:date;not null" json:"period_end"`
+	Stats       ProductivityStats `gorm:"serializer:json;type:text" json:"stats"`
+	HTML        string            `gorm:"type:longtext" json:"-"`
+	PDF         []byte            `gorm:"type:longblob" json:"-"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (r *ProductivityReport) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ReportSetting opt-in user untuk laporan produktivitas otomatis
+type ReportSetting struct {
+	UserID         string    `gorm:"type:varchar(36);primaryKey" json:"user_id"`
+	WeeklyEnabled  bool      `gorm:"default:false" json:"weekly_enabled"`
+	MonthlyEnabled bool      `gorm:"default:false" json:"monthly_enabled"`
+	EmailEnabled   bool      `gorm:"default:true" json:"email_enabled"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SchedulerLease lease leader scheduler di database. Hanya pemegang lease yang belum
+// kedaluwarsa yang menjalankan job terjadwal; lease diperpanjang berkala oleh pemegangnya.
+type SchedulerLease struct {
+	Name       string    `gorm:"type:varchar(100);primaryKey" json:"name"`
+	Holder     string    `gorm:"type:varchar(150);not null" json:"holder"` // instance ID
+	ExpiresAt  time.Time `gorm:"not null" json:"expires_at"`
+	AcquiredAt time.Time `gorm:"not null" json:"acquired_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "running"
+	JobRunCompleted JobRunStatus = "completed"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// JobRun riwayat satu eksekusi job terjadwal
+type JobRun struct {
+	ID         string       `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Scheduler  string       `gorm:"type:varchar(50);not null;index:idx_job_run_job,priority:1;index:idx_job_run_latest,priority:1" json:"scheduler"` // notifications, security
+	Job        string       `gorm:"type:varchar(100);not null;index:idx_job_run_job,priority:2;index:idx_job_run_latest,priority:2" json:"job"`
+	Instance   string       `gorm:"type:varchar(150);not null" json:"instance"`
+	Status     JobRunStatus `gorm:"type:varchar(20);not null" json:"status"`
+	StartedAt  time.Time    `gorm:"not null;index;index:idx_job_run_latest,priority:3" json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+	DurationMs int64        `json:"duration_ms"`
+	Result     *string      `gorm:"type:text" json:"result,omitempty"`
+	Error      *string      `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (r *JobRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// ScheduledJobState status runtime job terjadwal yang berlaku untuk semua replica
+// (job yang di-pause lewat API tetap ter-pause setelah restart atau pergantian leader)
+type ScheduledJobState struct {
+	Job       string    `gorm:"type:varchar(100);primaryKey" json:"job"`
+	Paused    bool      `gorm:"not null;default:false" json:"paused"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
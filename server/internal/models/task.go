@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,26 +18,115 @@ const (
 	RepeatMonthly RepeatType = "monthly"
 )
 
+// DeadlineShiftPolicy aturan jika deadline jatuh di hari libur, cuti, atau hari non-kerja
+type DeadlineShiftPolicy string
+
+const (
+	DeadlineShiftKeep     DeadlineShiftPolicy = "keep"
+	DeadlineShiftPrevious DeadlineShiftPolicy = "previous_work_day"
+	DeadlineShiftNext     DeadlineShiftPolicy = "next_work_day"
+)
+
+// IsValid mengecek apakah policy dikenal
+func (p DeadlineShiftPolicy) IsValid() bool {
+	return p == DeadlineShiftKeep || p == DeadlineShiftPrevious || p == DeadlineShiftNext
+}
+
 type Task struct {
 	ID              string     `gorm:"type:varchar(36);primaryKey" json:"id"`
 	UserID          string     `gorm:"type:varchar(36);not null;index:idx_user_id" json:"user_id"`
 	CategoryID      *string    `gorm:"type:varchar(36);index:idx_category_id" json:"category_id"`
+	WorkspaceID     *string    `gorm:"type:varchar(36);index" json:"workspace_id,omitempty"` // NULL = task pribadi
 	Title           string     `gorm:"type:varchar(255);not null" json:"title"`
 	Description     *string    `gorm:"type:text" json:"description,omitempty"`
 	Deadline        *time.Time `json:"deadline,omitempty"`
 	ReminderMinutes *int       `json:"reminder_minutes,omitempty"`
+	ReminderOffsets []int      `gorm:"serializer:json;type:text" json:"reminder_offsets,omitempty"` // pengingat tambahan, menit sebelum deadline
 	DurationMinutes *int       `json:"duration_minutes,omitempty"`
 	RepeatType      RepeatType `gorm:"type:enum('none','hourly','daily','weekly','monthly');default:'none'" json:"repeat_type"`
 	RepeatInterval  int        `gorm:"default:1" json:"repeat_interval"`
 	RepeatEndDate   *time.Time `gorm:"type:date" json:"repeat_end_date,omitempty"`
 	IsCompleted     bool       `gorm:"default:false;index:idx_is_completed" json:"is_completed"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Deadline shifting: NULL policy = ikut setting user
+	DeadlineShiftPolicy *DeadlineShiftPolicy `gorm:"type:varchar(20)" json:"deadline_shift_policy,omitempty"`
+	OriginalDeadline    *time.Time           `json:"original_deadline,omitempty"` // deadline sebelum digeser
+	DeadlineShift       *DeadlineShift       `gorm:"-" json:"deadline_shift,omitempty"`
+
+	// Reminder dibisukan sampai waktu ini (mis. selama cuti)
+	ReminderMutedUntil *time.Time `json:"reminder_muted_until,omitempty"`
+
+	// Overdue policy: task ditandai terlewat / sudah diingatkan ulang
+	MissedAt           *time.Time `json:"missed_at,omitempty"`
+	OverdueNotifiedAt  *time.Time `json:"overdue_notified_at,omitempty"`
+	OverdueNotifyCount int        `gorm:"default:0" json:"overdue_notify_count,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 
 	// Relations
-	User     User      `gorm:"foreignKey:UserID" json:"-"`
-	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	User      User           `gorm:"foreignKey:UserID" json:"-"`
+	Category  *Category      `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Assignees []TaskAssignee `gorm:"foreignKey:TaskID" json:"assignees,omitempty"`
+}
+
+// MaxReminderOffsetMinutes pengingat paling awal: 7 hari sebelum deadline
+const MaxReminderOffsetMinutes = 7 * 24 * 60
+
+// ReminderSchedule semua offset pengingat (menit sebelum deadline), terbesar dulu.
+// ReminderMinutes lama dihitung sebagai salah satu offset.
+func (t *Task) ReminderSchedule() []int {
+	seen := map[int]bool{}
+	offsets := []int{}
+	add := func(minutes int) {
+		if minutes < 0 || seen[minutes] {
+			return
+		}
+		seen[minutes] = true
+		offsets = append(offsets, minutes)
+	}
+
+	if t.ReminderMinutes != nil {
+		add(*t.ReminderMinutes)
+	}
+	for _, minutes := range t.ReminderOffsets {
+		add(minutes)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+	return offsets
+}
+
+// AssigneeIDs user yang ditugaskan; kosong = task dikerjakan pembuatnya
+func (t *Task) AssigneeIDs() []string {
+	ids := make([]string, 0, len(t.Assignees))
+	for _, assignee := range t.Assignees {
+		ids = append(ids, assignee.UserID)
+	}
+	return ids
+}
+
+// IsRecipient true jika user pemilik task atau salah satu assignee-nya
+func (t *Task) IsRecipient(userID string) bool {
+	if t.UserID == userID {
+		return true
+	}
+	for _, assignee := range t.Assignees {
+		if assignee.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadlineShift catatan pemindahan deadline otomatis (hanya di response, tidak disimpan)
+type DeadlineShift struct {
+	From   time.Time           `json:"from"`
+	To     time.Time           `json:"to"`
+	Policy DeadlineShiftPolicy `json:"policy"`
+	Reason string              `json:"reason"`
+	Note   string              `json:"note"`
 }
 
 // BeforeCreate hook untuk generate UUID
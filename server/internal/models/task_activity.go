@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TaskActivityAction string
+
+const (
+	TaskActivityCreated     TaskActivityAction = "created"
+	TaskActivityUpdated     TaskActivityAction = "updated"
+	TaskActivityCompleted   TaskActivityAction = "completed"
+	TaskActivityReopened    TaskActivityAction = "reopened"
+	TaskActivityBulkUpdated TaskActivityAction = "bulk_updated"
+	TaskActivitySpawned     TaskActivityAction = "spawned" // occurrence berikutnya dari task berulang
+	TaskActivityAssigned    TaskActivityAction = "assigned"
+	TaskActivityRescheduled TaskActivityAction = "rescheduled" // dipindah / dibisukan karena cuti / rollover overdue
+	TaskActivityMissed      TaskActivityAction = "missed"      // ditandai terlewat oleh overdue policy
+)
+
+// TaskFieldChange perubahan satu field, nilai sudah diformat untuk ditampilkan
+type TaskFieldChange struct {
+	Field string `json:"field"`
+	Label string `json:"label"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// TaskActivity riwayat perubahan task (timeline per task)
+type TaskActivity struct {
+	ID        string             `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TaskID    string             `gorm:"type:varchar(36);not null;index" json:"task_id"`
+	UserID    string             `gorm:"type:varchar(36);not null;index" json:"user_id"` // pelaku perubahan
+	Action    TaskActivityAction `gorm:"type:varchar(20);not null" json:"action"`
+	Summary   string             `gorm:"type:varchar(500)" json:"summary"`
+	Changes   []TaskFieldChange  `gorm:"serializer:json;type:text" json:"changes"`
+	CreatedAt time.Time          `gorm:"index" json:"created_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (a *TaskActivity) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// TaskActivityResponse item timeline beserta pelaku & diff yang mudah dibaca
+type TaskActivityResponse struct {
+	ID        string             `json:"id"`
+	Action    TaskActivityAction `json:"action"`
+	Actor     *UserSummary       `json:"actor,omitempty"`
+	Summary   string             `json:"summary"`
+	Changes   []TaskFieldChange  `json:"changes"`
+	Diff      []string           `json:"diff"` // "Deadline: 10 Jan 2026 09:00 → 12 Jan 2026 17:00"
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+func (a *TaskActivity) ToResponse() TaskActivityResponse {
+	response := TaskActivityResponse{
+		ID:        a.ID,
+		Action:    a.Action,
+		Summary:   a.Summary,
+		Changes:   a.Changes,
+		Diff:      make([]string, 0, len(a.Changes)),
+		CreatedAt: a.CreatedAt,
+	}
+	if response.Changes == nil {
+		response.Changes = []TaskFieldChange{}
+	}
+	for _, change := range a.Changes {
+		response.Diff = append(response.Diff, change.Label+": "+change.From+" → "+change.To)
+	}
+	if a.User != nil {
+		summary := a.User.ToSummary()
+		response.Actor = &summary
+	}
+	return response
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TaskAssignee member workspace yang ditugaskan mengerjakan task bersama
+type TaskAssignee struct {
+	ID         string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TaskID     string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_task_assignee" json:"task_id"`
+	UserID     string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_task_assignee;index" json:"user_id"`
+	AssignedBy string    `gorm:"type:varchar(36);not null;index" json:"assigned_by"`
+	CreatedAt  time.Time `json:"assigned_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (a *TaskAssignee) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
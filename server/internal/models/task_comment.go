@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TaskComment komentar diskusi di task. ParentID terisi untuk balasan;
+// balasan selalu menempel ke komentar utama (thread satu tingkat).
+type TaskComment struct {
+	ID        string     `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TaskID    string     `gorm:"type:varchar(36);not null;index" json:"task_id"`
+	UserID    string     `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	ParentID  *string    `gorm:"type:varchar(36);index" json:"parent_id,omitempty"`
+	Body      string     `gorm:"type:text;not null" json:"body"`
+	Mentions  []string   `gorm:"serializer:json;type:text" json:"mentions"` // user ID yang di-@mention
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (c *TaskComment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// TaskCommentResponse komentar beserta penulis & balasannya
+type TaskCommentResponse struct {
+	ID        string                `json:"id"`
+	TaskID    string                `json:"task_id"`
+	ParentID  *string               `json:"parent_id,omitempty"`
+	Body      string                `json:"body"`
+	Mentions  []string              `json:"mentions"`
+	Author    *UserSummary          `json:"author,omitempty"`
+	EditedAt  *time.Time            `json:"edited_at,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	Replies   []TaskCommentResponse `json:"replies,omitempty"`
+}
+
+func (c *TaskComment) ToResponse() TaskCommentResponse {
+	response := TaskCommentResponse{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		ParentID:  c.ParentID,
+		Body:      c.Body,
+		Mentions:  c.Mentions,
+		EditedAt:  c.EditedAt,
+		CreatedAt: c.CreatedAt,
+	}
+	if response.Mentions == nil {
+		response.Mentions = []string{}
+	}
+	if c.User != nil {
+		summary := c.User.ToSummary()
+		response.Author = &summary
+	}
+	return response
+}
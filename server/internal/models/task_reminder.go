@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TaskReminderLog penanda reminder yang sudah diproses per occurrence (task + deadline),
+// per penerima dan per offset, agar tiap reminder hanya dikirim sekali
+type TaskReminderLog struct {
+	ID            string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TaskID        string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_task_reminder_once,priority:1" json:"task_id"`
+	UserID        string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_task_reminder_once,priority:2" json:"user_id"`
+	Deadline      time.Time `gorm:"not null;uniqueIndex:idx_task_reminder_once,priority:3" json:"deadline"`
+	OffsetMinutes int       `gorm:"not null;uniqueIndex:idx_task_reminder_once,priority:4" json:"offset_minutes"`
+	Skipped       bool      `gorm:"default:false" json:"skipped"` // terlewat (mis. downtime), digantikan reminder yang lebih dekat
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (l *TaskReminderLog) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
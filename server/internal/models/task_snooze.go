@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SnoozeOption pilihan tunda pengingat dari notifikasi
+type SnoozeOption string
+
+const (
+	SnoozeTenMinutes SnoozeOption = "10m"
+	SnoozeOneHour    SnoozeOption = "1h"
+	SnoozeTomorrow   SnoozeOption = "tomorrow" // besok pada jam agenda pagi user
+)
+
+// IsValid mengecek apakah pilihan snooze dikenal
+func (o SnoozeOption) IsValid() bool {
+	return o == SnoozeTenMinutes || o == SnoozeOneHour || o == SnoozeTomorrow
+}
+
+// TaskSnooze pengingat task yang ditunda oleh satu penerima. Satu baris per task + user;
+// snooze ulang menimpa baris yang sama. Selama aktif, reminder reguler untuk occurrence
+// yang sama dilewati dan scheduler mengirim ulang pengingat pada SnoozedUntil.
+type TaskSnooze struct {
+	ID           string       `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TaskID       string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_task_snooze,priority:1" json:"task_id"`
+	UserID       string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_task_snooze,priority:2" json:"user_id"`
+	Deadline     *time.Time   `json:"deadline,omitempty"` // occurrence yang ditunda; snooze batal jika deadline berubah
+	Duration     SnoozeOption `gorm:"type:varchar(20);not null" json:"duration"`
+	SnoozedUntil time.Time    `gorm:"not null;index" json:"snoozed_until"`
+	FiredAt      *time.Time   `json:"fired_at,omitempty"` // pengingat ulang sudah dikirim
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (s *TaskSnooze) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
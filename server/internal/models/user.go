@@ -10,12 +10,20 @@ import (
 type UserType string
 type AuthProvider string
 
+// UserRole peran administratif user, terpisah dari paket langganan (UserType)
+type UserRole string
+
 const (
 	UserTypeRegular UserType = "regular"
 	UserTypeVIP     UserType = "vip"
 
 	AuthProviderLocal  AuthProvider = "local"
 	AuthProviderGoogle AuthProvider = "google"
+
+	UserRoleUser       UserRole = "user"
+	UserRoleModerator  UserRole = "moderator"
+	UserRoleAdmin      UserRole = "admin"
+	UserRoleSuperAdmin UserRole = "superadmin"
 )
 
 type User struct {
@@ -26,10 +34,14 @@ type User struct {
 	ProfilePicture *string      `gorm:"type:text" json:"profile_picture"`
 	AuthProvider   AuthProvider `gorm:"type:enum('local','google');default:'local'" json:"auth_provider"`
 	GoogleID       *string      `gorm:"type:varchar(255)" json:"google_id,omitempty"`
-	FCMToken       *string      `gorm:"type:varchar(255)" json:"-"` // Don't expose in JSON
+	FCMToken       *string      `gorm:"type:varchar(255)" json:"-"` // Legacy: dipindahkan ke user_devices saat startup
 	UserType       UserType     `gorm:"type:enum('regular','vip');default:'regular'" json:"user_type"`
 	VIPExpiresAt   *time.Time   `gorm:"column:vip_expires_at" json:"vip_expires_at,omitempty"`
+	Role           UserRole     `gorm:"type:varchar(20);not null;default:'user'" json:"-"` // admin diatur lewat ADMIN_EMAILS
 	WorkDays       *string      `gorm:"type:json" json:"work_days,omitempty"`
+	HolidayRegion  *string      `gorm:"type:varchar(10)" json:"holiday_region,omitempty"` // ISO 3166-2, e.g. ID-BA
+
+	DeadlineShiftPolicy DeadlineShiftPolicy `gorm:"type:varchar(20);default:'keep'" json:"deadline_shift_policy"`
 
 	// Field-Level Encryption Fields (Minggu 4: Enkripsi & Perlindungan Data)
 	Phone          *string `gorm:"type:varchar(20)" json:"phone,omitempty"`
@@ -68,31 +80,52 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 
 // UserResponse untuk response tanpa sensitive data
 type UserResponse struct {
-	ID             string       `json:"id"`
-	Email          string       `json:"email"`
-	Username       string       `json:"username"`
-	ProfilePicture *string      `json:"profile_picture"`
-	AuthProvider   AuthProvider `json:"auth_provider"`
-	UserType       UserType     `json:"user_type"`
-	EmailVerified  bool         `json:"email_verified"`
-	VIPExpiresAt   *time.Time   `json:"vip_expires_at,omitempty"`
-	WorkDays       *string      `json:"work_days,omitempty"`
-	CreatedAt      time.Time    `json:"created_at"`
-	UpdatedAt      time.Time    `json:"updated_at"`
+	ID                  string              `json:"id"`
+	Email               string              `json:"email"`
+	Username            string              `json:"username"`
+	ProfilePicture      *string             `json:"profile_picture"`
+	AuthProvider        AuthProvider        `json:"auth_provider"`
+	UserType            UserType            `json:"user_type"`
+	EmailVerified       bool                `json:"email_verified"`
+	VIPExpiresAt        *time.Time          `json:"vip_expires_at,omitempty"`
+	WorkDays            *string             `json:"work_days,omitempty"`
+	HolidayRegion       *string             `json:"holiday_region,omitempty"`
+	DeadlineShiftPolicy DeadlineShiftPolicy `json:"deadline_shift_policy"`
+	CreatedAt           time.Time           `json:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at"`
 }
 
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
+		ID:                  u.ID,
+		Email:               u.Email,
+		Username:            u.Username,
+		ProfilePicture:      u.ProfilePicture,
+		AuthProvider:        u.AuthProvider,
+		UserType:            u.UserType,
+		EmailVerified:       u.EmailVerified,
+		VIPExpiresAt:        u.VIPExpiresAt,
+		WorkDays:            u.WorkDays,
+		HolidayRegion:       u.HolidayRegion,
+		DeadlineShiftPolicy: u.DeadlineShiftPolicy,
+		CreatedAt:           u.CreatedAt,
+		UpdatedAt:           u.UpdatedAt,
+	}
+}
+
+// UserSummary data publik user untuk ditampilkan ke anggota workspace
+type UserSummary struct {
+	ID             string  `json:"id"`
+	Username       string  `json:"username"`
+	Email          string  `json:"email"`
+	ProfilePicture *string `json:"profile_picture,omitempty"`
+}
+
+func (u *User) ToSummary() UserSummary {
+	return UserSummary{
 		ID:             u.ID,
-		Email:          u.Email,
 		Username:       u.Username,
+		Email:          u.Email,
 		ProfilePicture: u.ProfilePicture,
-		AuthProvider:   u.AuthProvider,
-		UserType:       u.UserType,
-		EmailVerified:  u.EmailVerified,
-		VIPExpiresAt:   u.VIPExpiresAt,
-		WorkDays:       u.WorkDays,
-		CreatedAt:      u.CreatedAt,
-		UpdatedAt:      u.UpdatedAt,
 	}
 }
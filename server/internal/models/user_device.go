@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DevicePlatform string
+
+const (
+	DevicePlatformAndroid DevicePlatform = "android"
+	DevicePlatformIOS     DevicePlatform = "ios"
+	DevicePlatformWeb     DevicePlatform = "web"
+	DevicePlatformUnknown DevicePlatform = "unknown"
+)
+
+// UserDevice perangkat user yang menerima push notification (satu FCM token per perangkat)
+type UserDevice struct {
+	ID         string         `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID     string         `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Token      string         `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"` // Don't expose in JSON
+	Platform   DevicePlatform `gorm:"type:varchar(20);default:'unknown'" json:"platform"`
+	AppVersion *string        `gorm:"type:varchar(50)" json:"app_version,omitempty"`
+	DeviceName *string        `gorm:"type:varchar(100)" json:"device_name,omitempty"`
+	LastSeenAt time.Time      `json:"last_seen_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (d *UserDevice) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
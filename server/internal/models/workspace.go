@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleAdmin  WorkspaceRole = "admin"
+	WorkspaceRoleMember WorkspaceRole = "member"
+)
+
+// IsValid mengecek role workspace yang dikenal
+func (r WorkspaceRole) IsValid() bool {
+	return r == WorkspaceRoleOwner || r == WorkspaceRoleAdmin || r == WorkspaceRoleMember
+}
+
+// CanManage owner dan admin boleh mengelola member, kategori bersama & undangan
+func (r WorkspaceRole) CanManage() bool {
+	return r == WorkspaceRoleOwner || r == WorkspaceRoleAdmin
+}
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// Workspace ruang kerja tim; task & kategori dengan workspace_id terlihat oleh semua member
+type Workspace struct {
+	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description *string   `gorm:"type:text" json:"description,omitempty"`
+	OwnerID     string    `gorm:"type:varchar(36);not null;index" json:"owner_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (w *Workspace) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+	return nil
+}
+
+type WorkspaceMember struct {
+	ID          string        `gorm:"type:varchar(36);primaryKey" json:"id"`
+	WorkspaceID string        `gorm:"type:varchar(36);not null;uniqueIndex:idx_workspace_member" json:"workspace_id"`
+	UserID      string        `gorm:"type:varchar(36);not null;uniqueIndex:idx_workspace_member;index" json:"user_id"`
+	Role        WorkspaceRole `gorm:"type:varchar(20);not null;default:'member'" json:"role"`
+	CreatedAt   time.Time     `json:"joined_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (m *WorkspaceMember) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// WorkspaceMemberResponse member beserta data publik user
+type WorkspaceMemberResponse struct {
+	ID          string        `json:"id"`
+	WorkspaceID string        `json:"workspace_id"`
+	Role        WorkspaceRole `json:"role"`
+	JoinedAt    time.Time     `json:"joined_at"`
+	User        *UserSummary  `json:"user,omitempty"`
+}
+
+func (m *WorkspaceMember) ToResponse() WorkspaceMemberResponse {
+	response := WorkspaceMemberResponse{
+		ID:          m.ID,
+		WorkspaceID: m.WorkspaceID,
+		Role:        m.Role,
+		JoinedAt:    m.CreatedAt,
+	}
+	if m.User != nil {
+		summary := m.User.ToSummary()
+		response.User = &summary
+	}
+	return response
+}
+
+type WorkspaceInvitation struct {
+	ID          string           `gorm:"type:varchar(36);primaryKey" json:"id"`
+	WorkspaceID string           `gorm:"type:varchar(36);not null;index" json:"workspace_id"`
+	Email       string           `gorm:"type:varchar(255);not null;index" json:"email"`
+	Role        WorkspaceRole    `gorm:"type:varchar(20);not null;default:'member'" json:"role"`
+	Status      InvitationStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	InvitedBy   string           `gorm:"type:varchar(36);not null" json:"invited_by"`
+	ExpiresAt   time.Time        `gorm:"not null" json:"expires_at"`
+	RespondedAt *time.Time       `json:"responded_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+
+	// Relations
+	Workspace *Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (i *WorkspaceInvitation) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// IsExpired undangan sudah lewat masa berlaku
+func (i *WorkspaceInvitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
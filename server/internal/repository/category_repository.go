@@ -18,10 +18,17 @@ func (r *CategoryRepository) Create(category *models.Category) error {
 	return r.db.Create(category).Error
 }
 
-// FindByUserID mencari semua kategori milik user
+// FindByUserID mencari semua kategori pribadi milik user
 func (r *CategoryRepository) FindByUserID(userID string) ([]models.Category, error) {
 	var categories []models.Category
-	err := r.db.Where("user_id = ?", userID).Find(&categories).Error
+	err := r.db.Where("user_id = ? AND workspace_id IS NULL", userID).Find(&categories).Error
+	return categories, err
+}
+
+// FindByWorkspaceID mencari semua kategori bersama dalam workspace
+func (r *CategoryRepository) FindByWorkspaceID(workspaceID string) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Where("workspace_id = ?", workspaceID).Order("name ASC").Find(&categories).Error
 	return categories, err
 }
 
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DailyBriefingRepository struct {
+	db *gorm.DB
+}
+
+func NewDailyBriefingRepository(db *gorm.DB) *DailyBriefingRepository {
+	return &DailyBriefingRepository{db: db}
+}
+
+// FindDueUserIDs user di zona waktu timeZone yang jam briefing kind-nya termasuk clocks dan
+// belum diproses pada tanggal lokal date; user tanpa pengaturan memakai zona waktu dan jam default
+func (r *DailyBriefingRepository) FindDueUserIDs(kind models.DailyBriefingKind, date, timeZone string, clocks []string) ([]string, error) {
+	var userIDs []string
+	if len(clocks) == 0 {
+		return userIDs, nil
+	}
+	column, defaultClock := "notification_settings.agenda_time", models.DefaultAgendaTime
+	if kind == models.DailyBriefingDigest {
+		column, defaultClock = "notification_settings.digest_time", models.DefaultDigestTime
+	}
+
+	err := r.db.Model(&models.User{}).
+		Joins("LEFT JOIN notification_settings ON notification_settings.user_id = users.id").
+		Where("COALESCE(notification_settings.time_zone, ?) = ?", models.DefaultNotificationTimeZone, timeZone).
+		Where("COALESCE(NULLIF(TRIM("+column+"), ''), ?) IN ?", defaultClock, clocks).
+		Where("NOT EXISTS (SELECT 1 FROM daily_briefing_logs WHERE daily_briefing_logs.user_id = users.id "+
+			"AND daily_briefing_logs.kind = ? AND daily_briefing_logs.date = ?)", kind, date).
+		Pluck("users.id", &userIDs).Error
+	return userIDs, err
+}
+
+// Claim menandai briefing sebagai diproses; false jika sudah pernah
+func (r *DailyBriefingRepository) Claim(log *models.DailyBriefingLog) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(log)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Release membatalkan claim (briefing gagal masuk antrian, boleh dicoba lagi)
+func (r *DailyBriefingRepository) Release(userID string, kind models.DailyBriefingKind, date string) error {
+	return r.db.Where("user_id = ? AND kind = ? AND date = ?", userID, kind, date).
+		Delete(&models.DailyBriefingLog{}).Error
+}
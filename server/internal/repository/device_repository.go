@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+)
+
+type DeviceRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceRepository(db *gorm.DB) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+// Upsert mendaftarkan perangkat; token yang sudah ada dipindahkan ke user terbaru
+// (mis. login dengan akun lain di perangkat yang sama)
+func (r *DeviceRepository) Upsert(device *models.UserDevice) error {
+	var existing models.UserDevice
+	err := r.db.Where("token = ?", device.Token).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.db.Create(device).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	device.ID = existing.ID
+	device.CreatedAt = existing.CreatedAt
+	return r.db.Save(device).Error
+}
+
+// FindByUserID mencari semua perangkat user
+func (r *DeviceRepository) FindByUserID(userID string) ([]models.UserDevice, error) {
+	var devices []models.UserDevice
+	err := r.db.Where("user_id = ?", userID).Order("last_seen_at DESC").Find(&devices).Error
+	return devices, err
+}
+
+// FindTokensByUserID mengambil FCM token semua perangkat user
+func (r *DeviceRepository) FindTokensByUserID(userID string) ([]string, error) {
+	var tokens []string
+	err := r.db.Model(&models.UserDevice{}).Where("user_id = ?", userID).Pluck("token", &tokens).Error
+	return tokens, err
+}
+
+// DeleteByToken menghapus satu perangkat milik user
+func (r *DeviceRepository) DeleteByToken(userID, token string) (int64, error) {
+	result := r.db.Where("user_id = ? AND token = ?", userID, token).Delete(&models.UserDevice{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByUserID menghapus semua perangkat user
+func (r *DeviceRepository) DeleteByUserID(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.UserDevice{}).Error
+}
+
+// DeleteTokens menghapus token yang dilaporkan tidak valid oleh FCM
+func (r *DeviceRepository) DeleteTokens(tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.db.Where("token IN ?", tokens).Delete(&models.UserDevice{}).Error
+}
+
+// MigrateLegacyTokens memindahkan users.fcm_token (satu token per user) ke tabel user_devices
+func (r *DeviceRepository) MigrateLegacyTokens() (int, error) {
+	var users []models.User
+	if err := r.db.Where("fcm_token IS NOT NULL AND fcm_token != ''").Find(&users).Error; err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, user := range users {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			var count int64
+			if err := tx.Model(&models.UserDevice{}).Where("token = ?", *user.FCMToken).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				device := &models.UserDevice{
+					UserID:     user.ID,
+					Token:      *user.FCMToken,
+					Platform:   models.DevicePlatformUnknown,
+					LastSeenAt: user.UpdatedAt,
+				}
+				if err := tx.Create(device).Error; err != nil {
+					return err
+				}
+				migrated++
+			}
+			return tx.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("fcm_token", nil).Error
+		})
+		if err != nil {
+			return migrated, err
+		}
+	}
+	return migrated, nil
+}
@@ -20,30 +20,33 @@ func (r *HolidayRepository) Create(holiday *models.Holiday) error {
 	return r.db.Create(holiday).Error
 }
 
-// FindAll mendapatkan semua holidays (national + user's personal)
-func (r *HolidayRepository) FindAll(userID *string) ([]models.Holiday, error) {
-	var holidays []models.Holiday
-	query := r.db.Where("is_national = ?", true)
-
+// visibleTo membatasi holiday ke yang berlaku untuk user:
+// holiday publik (nasional atau sesuai region user) + personal holiday miliknya
+func (r *HolidayRepository) visibleTo(userID *string, region string) *gorm.DB {
+	public := r.db.Where("user_id IS NULL AND (region IS NULL OR region = '' OR region = ?)", region)
 	if userID != nil {
-		query = query.Or("user_id = ?", *userID)
+		return public.Or("user_id = ?", *userID)
 	}
+	return public
+}
 
-	err := query.Order("date ASC").Find(&holidays).Error
+// FindAll mendapatkan semua holidays (national + regional user + user's personal)
+func (r *HolidayRepository) FindAll(userID *string, region string) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := r.db.Where(r.visibleTo(userID, region)).
+		Order("date ASC").
+		Find(&holidays).Error
 	return holidays, err
 }
 
 // FindByDateRange mendapatkan holidays dalam rentang tanggal
-func (r *HolidayRepository) FindByDateRange(userID *string, startDate, endDate time.Time) ([]models.Holiday, error) {
+func (r *HolidayRepository) FindByDateRange(userID *string, region string, startDate, endDate time.Time) ([]models.Holiday, error) {
 	var holidays []models.Holiday
-	query := r.db.Where("date BETWEEN ? AND ?", startDate, endDate)
-	query = query.Where("is_national = ?", true)
-
-	if userID != nil {
-		query = query.Or("(user_id = ? AND date BETWEEN ? AND ?)", *userID, startDate, endDate)
-	}
-
-	err := query.Order("date ASC").Find(&holidays).Error
+	// Recurring holiday di-expand terpisah di service
+	err := r.db.Where("date BETWEEN ? AND ? AND is_recurring = ?", startDate, endDate, false).
+		Where(r.visibleTo(userID, region)).
+		Order("date ASC").
+		Find(&holidays).Error
 	return holidays, err
 }
 
@@ -65,15 +68,67 @@ func (r *HolidayRepository) Delete(id string, userID string) error {
 }
 
 // IsHolidayOnDate mengecek apakah tanggal tertentu adalah holiday
-func (r *HolidayRepository) IsHolidayOnDate(userID *string, date time.Time) (bool, error) {
+func (r *HolidayRepository) IsHolidayOnDate(userID *string, region string, date time.Time) (bool, error) {
 	var count int64
-	query := r.db.Model(&models.Holiday{}).Where("date = ?", date)
-	query = query.Where("is_national = ?", true)
+	err := r.db.Model(&models.Holiday{}).
+		Where("date = ? AND is_recurring = ?", date.Format("2006-01-02"), false).
+		Where(r.visibleTo(userID, region)).
+		Count(&count).Error
+	return count > 0, err
+}
 
-	if userID != nil {
-		query = query.Or("(user_id = ? AND date = ?)", *userID, date)
+// FindRecurringByUserID mendapatkan personal holiday recurring milik user
+func (r *HolidayRepository) FindRecurringByUserID(userID string) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := r.db.Where("user_id = ? AND is_recurring = ?", userID, true).
+		Order("date ASC").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+// FindWithReminder mendapatkan personal holiday yang punya reminder (semua user)
+func (r *HolidayRepository) FindWithReminder() ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := r.db.Where("user_id IS NOT NULL AND reminder_days_before IS NOT NULL").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+// MarkReminded menyimpan occurrence yang sudah diingatkan
+func (r *HolidayRepository) MarkReminded(id string, occurrence time.Time) error {
+	return r.db.Model(&models.Holiday{}).
+		Where("id = ?", id).
+		Update("last_reminded_for", occurrence).Error
+}
+
+// FindPublicByYear mendapatkan semua holiday non-personal dalam satu tahun (untuk import)
+func (r *HolidayRepository) FindPublicByYear(year int) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := r.db.Where("user_id IS NULL AND YEAR(date) = ?", year).
+		Order("date ASC").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+// Update memperbarui holiday
+func (r *HolidayRepository) Update(holiday *models.Holiday) error {
+	return r.db.Save(holiday).Error
+}
+
+// DeletePublicByIDs menghapus holiday publik (bukan personal) berdasarkan ID
+func (r *HolidayRepository) DeletePublicByIDs(ids []string) error {
+	if len(ids) == 0 {
+		return nil
 	}
+	return r.db.Where("id IN ? AND user_id IS NULL", ids).Delete(&models.Holiday{}).Error
+}
 
-	err := query.Count(&count).Error
-	return count > 0, err
+// ListPublicRegions mendapatkan region yang punya data holiday
+func (r *HolidayRepository) ListPublicRegions() ([]string, error) {
+	var regions []string
+	err := r.db.Model(&models.Holiday{}).
+		Where("user_id IS NULL AND region IS NOT NULL AND region != ''").
+		Distinct().
+		Pluck("region", &regions).Error
+	return regions, err
 }
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LeaveBalanceRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveBalanceRepository(db *gorm.DB) *LeaveBalanceRepository {
+	return &LeaveBalanceRepository{db: db}
+}
+
+// FindPolicy mencari policy saldo cuti user untuk tipe tertentu
+func (r *LeaveBalanceRepository) FindPolicy(userID string, leaveType models.LeaveType) (*models.LeavePolicy, error) {
+	var policy models.LeavePolicy
+	err := r.db.Where("user_id = ? AND type = ?", userID, leaveType).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindPolicies mendapatkan semua policy saldo cuti user
+func (r *LeaveBalanceRepository) FindPolicies(userID string) ([]models.LeavePolicy, error) {
+	var policies []models.LeavePolicy
+	err := r.db.Where("user_id = ?", userID).Order("type ASC").Find(&policies).Error
+	return policies, err
+}
+
+// SavePolicy membuat / memperbarui policy
+func (r *LeaveBalanceRepository) SavePolicy(policy *models.LeavePolicy) error {
+	return r.db.Save(policy).Error
+}
+
+// CreateEntry menambah entri ledger
+func (r *LeaveBalanceRepository) CreateEntry(entry *models.LeaveLedgerEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// CreateAutoEntry menambah entri otomatis (allotment/accrual/carry-over); entri untuk periode
+// yang sama diabaikan (unique index), false berarti sudah dicatat request lain
+func (r *LeaveBalanceRepository) CreateAutoEntry(entry *models.LeaveLedgerEntry) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DedupeLedgerPeriods menyiapkan unique index periode: penyesuaian manual (period kosong)
+// menjadi NULL dan entri otomatis ganda dihapus, menyisakan yang paling awal
+func (r *LeaveBalanceRepository) DedupeLedgerPeriods() (int64, error) {
+	if !r.db.Migrator().HasTable(&models.LeaveLedgerEntry{}) {
+		return 0, nil
+	}
+	if err := r.db.Exec("UPDATE leave_ledger_entries SET period = NULL WHERE period = ''").Error; err != nil {
+		return 0, err
+	}
+	result := r.db.Exec(`DELETE e FROM leave_ledger_entries e
+		JOIN leave_ledger_entries k
+		  ON k.user_id = e.user_id AND k.type = e.type AND k.year = e.year AND k.kind = e.kind AND k.period = e.period
+		 AND (k.created_at < e.created_at OR (k.created_at = e.created_at AND k.id < e.id))`)
+	return result.RowsAffected, result.Error
+}
+
+// FindEntries mendapatkan entri ledger user untuk tipe & tahun
+func (r *LeaveBalanceRepository) FindEntries(userID string, leaveType models.LeaveType, year int) ([]models.LeaveLedgerEntry, error) {
+	var entries []models.LeaveLedgerEntry
+	err := r.db.Where("user_id = ? AND type = ? AND year = ?", userID, leaveType, year).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// FindPeriods mendapatkan period entri otomatis yang sudah tercatat (untuk idempotensi)
+func (r *LeaveBalanceRepository) FindPeriods(userID string, leaveType models.LeaveType, year int, kind models.LeaveLedgerKind) (map[string]bool, error) {
+	var periods []string
+	err := r.db.Model(&models.LeaveLedgerEntry{}).
+		Where("user_id = ? AND type = ? AND year = ? AND kind = ? AND period IS NOT NULL", userID, leaveType, year, kind).
+		Pluck("period", &periods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(periods))
+	for _, period := range periods {
+		result[period] = true
+	}
+	return result, nil
+}
+
+// FindEntitlementEntries entri jatah otomatis (allotment dan accrual) untuk tipe & tahun
+func (r *LeaveBalanceRepository) FindEntitlementEntries(userID string, leaveType models.LeaveType, year int) ([]models.LeaveLedgerEntry, error) {
+	var entries []models.LeaveLedgerEntry
+	err := r.db.Where("user_id = ? AND type = ? AND year = ? AND kind IN ? AND period IS NOT NULL",
+		userID, leaveType, year, []models.LeaveLedgerKind{models.LeaveLedgerAllotment, models.LeaveLedgerAccrual}).
+		Order("period ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// UpdateEntryDays mengubah jumlah hari entri otomatis (policy berubah)
+func (r *LeaveBalanceRepository) UpdateEntryDays(id string, days float64) error {
+	return r.db.Model(&models.LeaveLedgerEntry{}).Where("id = ?", id).Update("days", days).Error
+}
+
+// DeleteEntries menghapus entri otomatis yang tidak berlaku lagi
+func (r *LeaveBalanceRepository) DeleteEntries(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("id IN ?", ids).Delete(&models.LeaveLedgerEntry{}).Error
+}
+
+// CountEntries jumlah entri ledger untuk tipe & tahun
+func (r *LeaveBalanceRepository) CountEntries(userID string, leaveType models.LeaveType, year int) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.LeaveLedgerEntry{}).
+		Where("user_id = ? AND type = ? AND year = ?", userID, leaveType, year).
+		Count(&count).Error
+	return count, err
+}
+
+// SumEntries total saldo yang dikreditkan di ledger untuk tipe & tahun
+func (r *LeaveBalanceRepository) SumEntries(userID string, leaveType models.LeaveType, year int) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.LeaveLedgerEntry{}).
+		Select("COALESCE(SUM(days), 0)").
+		Where("user_id = ? AND type = ? AND year = ?", userID, leaveType, year).
+		Scan(&total).Error
+	return total, err
+}
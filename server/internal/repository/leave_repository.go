@@ -7,6 +7,12 @@ import (
 	"gorm.io/gorm"
 )
 
+// activeLeaveStatuses status cuti yang masih berlaku (belum ditolak/dibatalkan)
+var activeLeaveStatuses = []models.LeaveStatus{models.LeaveStatusPending, models.LeaveStatusApproved}
+
+// leaveLastDateExpr tanggal akhir cuti; data lama tanpa end_date = satu hari
+const leaveLastDateExpr = "COALESCE(end_date, date)"
+
 type LeaveRepository struct {
 	db *gorm.DB
 }
@@ -42,7 +48,7 @@ func (r *LeaveRepository) FindUpcoming(userID string) ([]models.Leave, error) {
 	var leaves []models.Leave
 	today := time.Now().Truncate(24 * time.Hour)
 
-	err := r.db.Where("user_id = ? AND date >= ?", userID, today).
+	err := r.db.Where("user_id = ? AND "+leaveLastDateExpr+" >= ?", userID, today).
 		Order("date ASC").
 		Find(&leaves).Error
 
@@ -54,27 +60,47 @@ func (r *LeaveRepository) FindPast(userID string) ([]models.Leave, error) {
 	var leaves []models.Leave
 	today := time.Now().Truncate(24 * time.Hour)
 
-	err := r.db.Where("user_id = ? AND date < ?", userID, today).
+	err := r.db.Where("user_id = ? AND "+leaveLastDateExpr+" < ?", userID, today).
 		Order("date DESC").
 		Find(&leaves).Error
 
 	return leaves, err
 }
 
-// FindByMonth mendapatkan leaves dalam bulan tertentu
+// FindByMonth mendapatkan leaves yang beririsan dengan bulan tertentu
 func (r *LeaveRepository) FindByMonth(userID string, year int, month time.Month) ([]models.Leave, error) {
-	var leaves []models.Leave
-
 	startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, -1)
 
-	err := r.db.Where("user_id = ? AND date BETWEEN ? AND ?", userID, startDate, endDate).
+	return r.FindByDateRange(userID, startDate, endDate)
+}
+
+// FindByDateRange mendapatkan leaves yang beririsan dengan rentang tanggal
+func (r *LeaveRepository) FindByDateRange(userID string, start, end time.Time) ([]models.Leave, error) {
+	var leaves []models.Leave
+
+	err := r.db.Where("user_id = ? AND date <= ? AND "+leaveLastDateExpr+" >= ?",
+		userID, end.Format("2006-01-02"), start.Format("2006-01-02")).
 		Order("date ASC").
 		Find(&leaves).Error
 
 	return leaves, err
 }
 
+// FindOverlapping mendapatkan cuti aktif yang beririsan dengan rentang (excludeID untuk update)
+func (r *LeaveRepository) FindOverlapping(userID string, start, end time.Time, excludeID string) ([]models.Leave, error) {
+	var leaves []models.Leave
+
+	query := r.db.Where("user_id = ? AND status IN ? AND date <= ? AND "+leaveLastDateExpr+" >= ?",
+		userID, activeLeaveStatuses, end.Format("2006-01-02"), start.Format("2006-01-02"))
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	err := query.Order("date ASC").Find(&leaves).Error
+	return leaves, err
+}
+
 // Update memperbarui leave
 func (r *LeaveRepository) Update(leave *models.Leave) error {
 	return r.db.Save(leave).Error
@@ -85,11 +111,24 @@ func (r *LeaveRepository) Delete(id string, userID string) error {
 	return r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Leave{}).Error
 }
 
-// IsLeaveOnDate mengecek apakah tanggal tertentu adalah leave day
+// IsLeaveOnDate mengecek apakah tanggal tertentu sudah ada pengajuan cuti aktif (pending/approved)
 func (r *LeaveRepository) IsLeaveOnDate(userID string, date time.Time) (bool, error) {
 	var count int64
+	day := date.Format("2006-01-02")
+	err := r.db.Model(&models.Leave{}).
+		Where("user_id = ? AND date <= ? AND "+leaveLastDateExpr+" >= ? AND status IN ?", userID, day, day, activeLeaveStatuses).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// IsApprovedLeaveOnDate mengecek apakah user cuti seharian (approved) pada tanggal tertentu.
+// Cuti setengah hari tidak dihitung karena user masih bekerja di separuh hari lainnya.
+func (r *LeaveRepository) IsApprovedLeaveOnDate(userID string, date time.Time) (bool, error) {
+	var count int64
+	day := date.Format("2006-01-02")
 	err := r.db.Model(&models.Leave{}).
-		Where("user_id = ? AND date = ?", userID, date).
+		Where("user_id = ? AND date <= ? AND "+leaveLastDateExpr+" >= ? AND status = ?", userID, day, day, models.LeaveStatusApproved).
+		Where("half_day = '' OR half_day IS NULL").
 		Count(&count).Error
 	return count > 0, err
 }
@@ -100,8 +139,121 @@ func (r *LeaveRepository) GetUpcomingCount(userID string) (int64, error) {
 	today := time.Now().Truncate(24 * time.Hour)
 
 	err := r.db.Model(&models.Leave{}).
-		Where("user_id = ? AND date >= ?", userID, today).
+		Where("user_id = ? AND "+leaveLastDateExpr+" >= ? AND status IN ?", userID, today, activeLeaveStatuses).
 		Count(&count).Error
 
 	return count, err
 }
+
+// ============ Approval Workflow ============
+
+// FindForApprover mendapatkan cuti dari user yang ditugaskan ke approver ini, ditambah
+// cuti member tanpa approver di workspace yang dikelola (owner/admin) approver ini
+func (r *LeaveRepository) FindForApprover(approverID string, status *models.LeaveStatus) ([]models.Leave, error) {
+	var leaves []models.Leave
+	assigned := r.db.Model(&models.LeaveApprover{}).Select("user_id").Where("approver_id = ?", approverID)
+	managed := r.db.Model(&models.WorkspaceMember{}).Select("user_id").
+		Where("user_id <> ? AND workspace_id IN (?)", approverID,
+			r.db.Model(&models.WorkspaceMember{}).Select("workspace_id").
+				Where("user_id = ? AND role IN ?", approverID,
+					[]models.WorkspaceRole{models.WorkspaceRoleOwner, models.WorkspaceRoleAdmin})).
+		Where("user_id NOT IN (?)", r.db.Model(&models.LeaveApprover{}).Select("user_id"))
+
+	query := r.db.Where(r.db.Where("user_id IN (?)", assigned).Or("user_id IN (?)", managed))
+
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	err := query.Order("date ASC").Find(&leaves).Error
+	return leaves, err
+}
+
+// CreateTransition mencatat perubahan status cuti
+func (r *LeaveRepository) CreateTransition(transition *models.LeaveTransition) error {
+	return r.db.Create(transition).Error
+}
+
+// FindTransitions mendapatkan riwayat status cuti
+func (r *LeaveRepository) FindTransitions(leaveID string) ([]models.LeaveTransition, error) {
+	var transitions []models.LeaveTransition
+	err := r.db.Where("leave_id = ?", leaveID).Order("created_at ASC").Find(&transitions).Error
+	return transitions, err
+}
+
+// FindApprovers mendapatkan approver yang ditunjuk user
+func (r *LeaveRepository) FindApprovers(userID string) ([]models.LeaveApprover, error) {
+	var approvers []models.LeaveApprover
+	err := r.db.Preload("Approver").Where("user_id = ?", userID).Order("created_at ASC").Find(&approvers).Error
+	return approvers, err
+}
+
+// FindApproverByID mencari penunjukan approver
+func (r *LeaveRepository) FindApproverByID(id string) (*models.LeaveApprover, error) {
+	var approver models.LeaveApprover
+	err := r.db.Where("id = ?", id).First(&approver).Error
+	if err != nil {
+		return nil, err
+	}
+	return &approver, nil
+}
+
+// IsApproverOf mengecek apakah approverID adalah approver untuk userID
+func (r *LeaveRepository) IsApproverOf(approverID, userID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.LeaveApprover{}).
+		Where("user_id = ? AND approver_id = ?", userID, approverID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateApprover menambah approver
+func (r *LeaveRepository) CreateApprover(approver *models.LeaveApprover) error {
+	return r.db.Create(approver).Error
+}
+
+// DeleteApprover menghapus approver milik user
+func (r *LeaveRepository) DeleteApprover(id, userID string) (int64, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.LeaveApprover{})
+	return result.RowsAffected, result.Error
+}
+
+// SumWorkingDays total hari kerja cuti per tipe yang seluruhnya jatuh di tahun tertentu.
+// Cuti yang melewati pergantian tahun diambil terpisah dengan FindSpanningYear.
+func (r *LeaveRepository) SumWorkingDays(userID string, leaveType models.LeaveType, year int, statuses []models.LeaveStatus, excludeID string) (float64, error) {
+	var total float64
+	start, end := leaveYearBounds(year)
+
+	query := r.db.Model(&models.Leave{}).
+		Select("COALESCE(SUM(working_days), 0)").
+		Where("user_id = ? AND type = ? AND status IN ? AND date >= ? AND "+leaveLastDateExpr+" <= ?",
+			userID, leaveType, statuses, start, end)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	err := query.Scan(&total).Error
+	return total, err
+}
+
+// FindSpanningYear cuti per tipe yang beririsan dengan tahun tertentu tapi melewati pergantian tahun
+func (r *LeaveRepository) FindSpanningYear(userID string, leaveType models.LeaveType, year int, statuses []models.LeaveStatus, excludeID string) ([]models.Leave, error) {
+	var leaves []models.Leave
+	start, end := leaveYearBounds(year)
+
+	query := r.db.Where("user_id = ? AND type = ? AND status IN ? AND date <= ? AND "+leaveLastDateExpr+" >= ?",
+		userID, leaveType, statuses, end, start).
+		Where("(date < ? OR "+leaveLastDateExpr+" > ?)", start, end)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	err := query.Order("date ASC").Find(&leaves).Error
+	return leaves, err
+}
+
+// leaveYearBounds tanggal pertama dan terakhir tahun (YYYY-MM-DD)
+func leaveYearBounds(year int) (string, string) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01-02"), start.AddDate(1, 0, -1).Format("2006-01-02")
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationEndpointRepository subscription Web Push & webhook notifikasi milik user
+type NotificationEndpointRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationEndpointRepository(db *gorm.DB) *NotificationEndpointRepository {
+	return &NotificationEndpointRepository{db: db}
+}
+
+// UpsertWebPush menyimpan subscription; endpoint yang sama diperbarui (dan dipindah ke user ini)
+func (r *NotificationEndpointRepository) UpsertWebPush(subscription *models.WebPushSubscription) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "p256dh", "auth", "user_agent", "updated_at"}),
+	}).Create(subscription).Error
+}
+
+// FindWebPushByUserID mendapatkan semua subscription Web Push user
+func (r *NotificationEndpointRepository) FindWebPushByUserID(userID string) ([]models.WebPushSubscription, error) {
+	var subscriptions []models.WebPushSubscription
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// DeleteWebPush menghapus subscription milik user; mengembalikan jumlah baris terhapus
+func (r *NotificationEndpointRepository) DeleteWebPush(userID, endpoint string) (int64, error) {
+	result := r.db.Where("user_id = ? AND endpoint = ?", userID, endpoint).Delete(&models.WebPushSubscription{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteWebPushByID menghapus subscription yang sudah tidak berlaku (404/410 dari push service)
+func (r *NotificationEndpointRepository) DeleteWebPushByID(id string) error {
+	return r.db.Delete(&models.WebPushSubscription{}, "id = ?", id).Error
+}
+
+// FindWebhook mendapatkan webhook user (nil jika belum diatur)
+func (r *NotificationEndpointRepository) FindWebhook(userID string) (*models.NotificationWebhook, error) {
+	var webhook models.NotificationWebhook
+	err := r.db.First(&webhook, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// SaveWebhook membuat atau memperbarui webhook user
+func (r *NotificationEndpointRepository) SaveWebhook(webhook *models.NotificationWebhook) error {
+	return r.db.Save(webhook).Error
+}
+
+// DeleteWebhook menghapus webhook user
+func (r *NotificationEndpointRepository) DeleteWebhook(userID string) error {
+	return r.db.Delete(&models.NotificationWebhook{}, "user_id = ?", userID).Error
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationOutboxRepository(db *gorm.DB) *NotificationOutboxRepository {
+	return &NotificationOutboxRepository{db: db}
+}
+
+// Enqueue menyimpan notifikasi; false jika idempotency key sudah pernah dipakai
+func (r *NotificationOutboxRepository) Enqueue(entry *models.NotificationOutbox) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimDue mengunci notifikasi yang jatuh tempo untuk diproses worker ini.
+// Lease kadaluarsa (worker mati di tengah proses) membuat notifikasi bisa diambil lagi.
+func (r *NotificationOutboxRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.NotificationOutbox, error) {
+	token := uuid.New().String()
+	lockedUntil := now.Add(lease)
+
+	err := r.db.Model(&models.NotificationOutbox{}).
+		Where("status IN ? AND next_attempt_at <= ?", []models.NotificationStatus{models.NotificationStatusQueued, models.NotificationStatusFailed}, now).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Updates(map[string]interface{}{"locked_until": lockedUntil, "claim_token": token}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.NotificationOutbox
+	err = r.db.Where("claim_token = ?", token).Order("next_attempt_at ASC").Find(&entries).Error
+	return entries, err
+}
+
+// MarkSent menandai notifikasi terkirim. false jika claim sudah hilang (lease habis dan
+// notifikasi diambil worker lain): status milik worker tersebut tidak ditimpa.
+func (r *NotificationOutboxRepository) MarkSent(id, claimToken string, attempts int, delivered []models.NotificationChannel, sentAt time.Time) (bool, error) {
+	// Update via struct + Select agar serializer JSON dipakai dan nilai nil tetap ditulis
+	result := r.db.Model(&models.NotificationOutbox{ID: id}).
+		Where("claim_token = ?", claimToken).
+		Select("status", "attempts", "delivered_channels", "sent_at", "last_error", "locked_until", "claim_token").
+		Updates(&models.NotificationOutbox{
+			Status:            models.NotificationStatusSent,
+			Attempts:          attempts,
+			DeliveredChannels: delivered,
+			SentAt:            &sentAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkFailed menyimpan error; status failed (retry pada nextAttemptAt) atau dead.
+// delivered = channel yang sudah berhasil sehingga tidak dikirim ulang.
+// false jika claim sudah hilang, seperti MarkSent.
+func (r *NotificationOutboxRepository) MarkFailed(id, claimToken string, status models.NotificationStatus, attempts int, delivered []models.NotificationChannel, lastError string, nextAttemptAt time.Time) (bool, error) {
+	result := r.db.Model(&models.NotificationOutbox{ID: id}).
+		Where("claim_token = ?", claimToken).
+		Select("status", "attempts", "delivered_channels", "last_error", "next_attempt_at", "locked_until", "claim_token").
+		Updates(&models.NotificationOutbox{
+			Status:            status,
+			Attempts:          attempts,
+			DeliveredChannels: delivered,
+			LastError:         &lastError,
+			NextAttemptAt:     nextAttemptAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindByUserID riwayat notifikasi user, terbaru dulu
+func (r *NotificationOutboxRepository) FindByUserID(userID string, status *models.NotificationStatus, limit, offset int) ([]models.NotificationOutbox, int64, error) {
+	query := r.db.Model(&models.NotificationOutbox{}).Where("user_id = ?", userID)
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.NotificationOutbox
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error
+	return entries, total, err
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordedStatement SQL yang dihasilkan GORM dalam mode dry run
+type recordedStatement struct {
+	sql  string
+	vars []interface{}
+}
+
+// newDryRunDB GORM MySQL tanpa koneksi: query tidak dijalankan, hanya dicatat
+func newDryRunDB(t *testing.T) (*gorm.DB, *[]recordedStatement) {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "dry:run@tcp(127.0.0.1:0)/dryrun?parseTime=true", SkipInitializeWithVersion: true}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open dry run database: %v", err)
+	}
+
+	var statements []recordedStatement
+	record := func(tx *gorm.DB) {
+		statements = append(statements, recordedStatement{sql: tx.Statement.SQL.String(), vars: tx.Statement.Vars})
+	}
+	db.Callback().Update().After("gorm:update").Register("test:record_update", record)
+	db.Callback().Query().After("gorm:query").Register("test:record_query", record)
+	return db, &statements
+}
+
+// TestClaimDueLease notifikasi hanya diklaim jika belum dikunci atau lease-nya kadaluarsa,
+// dan yang dibaca hanya baris milik claim token ini
+func TestClaimDueLease(t *testing.T) {
+	db, statements := newDryRunDB(t)
+	repo := NewNotificationOutboxRepository(db)
+
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	lease := 2 * time.Minute
+	if _, err := repo.ClaimDue(now, lease, 10); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(*statements) != 2 {
+		t.Fatalf("Expected UPDATE then SELECT, Got: %+v", *statements)
+	}
+	update, query := (*statements)[0], (*statements)[1]
+
+	testCases := []struct {
+		name     string
+		sql      string
+		contains string
+	}{
+		{"Claims due entries", update.sql, "next_attempt_at <= ?"},
+		{"Skips entries under a live lease", update.sql, "(locked_until IS NULL OR locked_until < ?)"},
+		{"Sets the lease and token", update.sql, "SET `claim_token`=?,`locked_until`=?"},
+		{"Oldest first, bounded", update.sql, "ORDER BY next_attempt_at ASC LIMIT 10"},
+		{"Reads back own claim", query.sql, "WHERE claim_token = ?"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !strings.Contains(tc.sql, tc.contains) {
+				t.Errorf("Expected SQL to contain: %s\nGot: %s", tc.contains, tc.sql)
+			}
+		})
+	}
+
+	// Var: claim_token, locked_until, status..., next_attempt_at, locked_until
+	token, _ := update.vars[0].(string)
+	if token == "" || len(query.vars) != 1 || query.vars[0] != token {
+		t.Errorf("Expected SELECT to use the claim token %q, Got vars: %v", token, query.vars)
+	}
+	if lockedUntil, _ := update.vars[1].(time.Time); !lockedUntil.Equal(now.Add(lease)) {
+		t.Errorf("Expected locked_until: %s, Got: %v", now.Add(lease), update.vars[1])
+	}
+	if expiredBefore, _ := update.vars[len(update.vars)-1].(time.Time); !expiredBefore.Equal(now) {
+		t.Errorf("Expected lease expiry compared with now %s, Got: %v", now, update.vars[len(update.vars)-1])
+	}
+}
+
+// TestMarkRequiresClaim status hanya ditulis worker yang masih memegang claim token
+func TestMarkRequiresClaim(t *testing.T) {
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name string
+		mark func(repo *NotificationOutboxRepository) (bool, error)
+	}{
+		{"Mark sent", func(repo *NotificationOutboxRepository) (bool, error) {
+			return repo.MarkSent("outbox-1", "token-1", 1, nil, now)
+		}},
+		{"Mark failed", func(repo *NotificationOutboxRepository) (bool, error) {
+			return repo.MarkFailed("outbox-1", "token-1", "failed", 1, nil, "timeout", now)
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, statements := newDryRunDB(t)
+			if _, err := tc.mark(NewNotificationOutboxRepository(db)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(*statements) != 1 {
+				t.Fatalf("Expected one UPDATE, Got: %+v", *statements)
+			}
+
+			update := (*statements)[0]
+			if !strings.Contains(update.sql, "claim_token = ?") || !strings.Contains(update.sql, "`id` = ?") {
+				t.Errorf("Expected UPDATE filtered by id and claim token\nGot: %s", update.sql)
+			}
+			found := false
+			for _, v := range update.vars {
+				if v == "token-1" {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected claim token in vars, Got: %v", update.vars)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// FindSettings mendapatkan pengaturan notifikasi user (default jika belum ada)
+func (r *NotificationPreferenceRepository) FindSettings(userID string) (*models.NotificationSettings, error) {
+	var settings models.NotificationSettings
+	err := r.db.First(&settings, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultNotificationSettings(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// FindTimeZones zona waktu yang dipakai di pengaturan notifikasi user
+func (r *NotificationPreferenceRepository) FindTimeZones() ([]string, error) {
+	var timeZones []string
+	err := r.db.Model(&models.NotificationSettings{}).Distinct().Pluck("time_zone", &timeZones).Error
+	return timeZones, err
+}
+
+func defaultNotificationSettings(userID string) *models.NotificationSettings {
+	return &models.NotificationSettings{
+		UserID:          userID,
+		TimeZone:        models.DefaultNotificationTimeZone,
+		QuietHoursStart: "22:00",
+		QuietHoursEnd:   "07:00",
+		AgendaTime:      models.DefaultAgendaTime,
+		DigestTime:      models.DefaultDigestTime,
+	}
+}
+
+// SaveSettings menyimpan pengaturan notifikasi user
+func (r *NotificationPreferenceRepository) SaveSettings(settings *models.NotificationSettings) error {
+	return r.db.Save(settings).Error
+}
+
+// FindPreferences mendapatkan semua preferensi per jenis notifikasi milik user
+func (r *NotificationPreferenceRepository) FindPreferences(userID string) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+// FindPreference mendapatkan preferensi satu jenis notifikasi (nil jika belum diatur)
+func (r *NotificationPreferenceRepository) FindPreference(userID, notificationType string) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := r.db.First(&preference, "user_id = ? AND type = ?", userID, notificationType).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// SavePreference membuat atau memperbarui preferensi (unik per user + jenis)
+func (r *NotificationPreferenceRepository) SavePreference(preference *models.NotificationPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "channels", "bypass_quiet_hours", "updated_at"}),
+	}).Create(preference).Error
+}
+
+// FindUserIDsWithDisabled mendapatkan user yang mematikan jenis notifikasi tertentu
+func (r *NotificationPreferenceRepository) FindUserIDsWithDisabled(notificationType string) ([]string, error) {
+	var userIDs []string
+	err := r.db.Model(&models.NotificationPreference{}).
+		Where("type = ? AND enabled = ?", notificationType, false).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+)
+
+type OverdueRepository struct {
+	db *gorm.DB
+}
+
+func NewOverdueRepository(db *gorm.DB) *OverdueRepository {
+	return &OverdueRepository{db: db}
+}
+
+// FindPoliciesByUserID mendapatkan policy default & per kategori milik user
+func (r *OverdueRepository) FindPoliciesByUserID(userID string) ([]models.OverduePolicy, error) {
+	var policies []models.OverduePolicy
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&policies).Error
+	return policies, err
+}
+
+// FindAllPolicies mendapatkan semua policy (untuk job malam)
+func (r *OverdueRepository) FindAllPolicies() ([]models.OverduePolicy, error) {
+	var policies []models.OverduePolicy
+	err := r.db.Find(&policies).Error
+	return policies, err
+}
+
+// FindPolicy mendapatkan policy user untuk kategori (nil = default user); nil jika belum ada
+func (r *OverdueRepository) FindPolicy(userID string, categoryID *string) (*models.OverduePolicy, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if categoryID == nil {
+		query = query.Where("category_id IS NULL")
+	} else {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+
+	var policy models.OverduePolicy
+	err := query.First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SavePolicy membuat atau memperbarui policy
+func (r *OverdueRepository) SavePolicy(policy *models.OverduePolicy) error {
+	return r.db.Save(policy).Error
+}
+
+// DeletePolicy menghapus policy user untuk kategori (nil = default user)
+func (r *OverdueRepository) DeletePolicy(userID string, categoryID *string) (int64, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if categoryID == nil {
+		query = query.Where("category_id IS NULL")
+	} else {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+	result := query.Delete(&models.OverduePolicy{})
+	return result.RowsAffected, result.Error
+}
+
+// CreateLog mencatat tindakan job overdue
+func (r *OverdueRepository) CreateLog(entry *models.OverdueActionLog) error {
+	return r.db.Create(entry).Error
+}
+
+// FindLogsByUserID riwayat tindakan overdue pada task milik user, terbaru dulu
+func (r *OverdueRepository) FindLogsByUserID(userID string, limit int) ([]models.OverdueActionLog, error) {
+	var logs []models.OverdueActionLog
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// FindLatestLogs tindakan terakhir per task
+func (r *OverdueRepository) FindLatestLogs(taskIDs []string) (map[string]models.OverdueActionLog, error) {
+	latest := make(map[string]models.OverdueActionLog, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return latest, nil
+	}
+
+	var logs []models.OverdueActionLog
+	if err := r.db.Where("task_id IN ?", taskIDs).Order("created_at ASC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	for _, entry := range logs {
+		latest[entry.TaskID] = entry
+	}
+	return latest, nil
+}
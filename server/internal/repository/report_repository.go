@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+)
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// Save menyimpan report baru atau menimpa report periode yang sama
+func (r *ReportRepository) Save(report *models.ProductivityReport) error {
+	var existing models.ProductivityReport
+	err := r.db.Select("id", "created_at").
+		Where("user_id = ? AND period = ? AND period_start = ?", report.UserID, report.Period, report.PeriodStart).
+		First(&existing).Error
+	if err == nil {
+		report.ID = existing.ID
+		report.CreatedAt = existing.CreatedAt
+		return r.db.Save(report).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return r.db.Create(report).Error
+}
+
+// FindByID mencari report by ID (termasuk isi HTML & PDF)
+func (r *ReportRepository) FindByID(id string) (*models.ProductivityReport, error) {
+	var report models.ProductivityReport
+	err := r.db.First(&report, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// FindByUserID mendapatkan daftar report user tanpa isi file
+func (r *ReportRepository) FindByUserID(userID string, period *models.ReportPeriod) ([]models.ProductivityReport, error) {
+	var reports []models.ProductivityReport
+	query := r.db.Omit("html", "pdf").Where("user_id = ?", userID)
+	if period != nil {
+		query = query.Where("period = ?", *period)
+	}
+	err := query.Order("period_start DESC").Find(&reports).Error
+	return reports, err
+}
+
+// ExistsForPeriod mengecek apakah report periode tertentu sudah dibuat
+func (r *ReportRepository) ExistsForPeriod(userID string, period models.ReportPeriod, start time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ProductivityReport{}).
+		Where("user_id = ? AND period = ? AND period_start = ?", userID, period, start).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindSetting mendapatkan setting laporan user (default jika belum ada)
+func (r *ReportRepository) FindSetting(userID string) (*models.ReportSetting, error) {
+	var setting models.ReportSetting
+	err := r.db.First(&setting, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.ReportSetting{UserID: userID, EmailEnabled: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// SaveSetting menyimpan setting laporan user
+func (r *ReportRepository) SaveSetting(setting *models.ReportSetting) error {
+	return r.db.Save(setting).Error
+}
+
+// FindSubscribedSettings mendapatkan semua user yang opt-in untuk periode tertentu
+func (r *ReportRepository) FindSubscribedSettings(period models.ReportPeriod) ([]models.ReportSetting, error) {
+	var settings []models.ReportSetting
+	column := "weekly_enabled"
+	if period == models.ReportPeriodMonthly {
+		column = "monthly_enabled"
+	}
+	err := r.db.Where(column+" = ?", true).Find(&settings).Error
+	return settings, err
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SchedulerRepository struct {
+	db *gorm.DB
+}
+
+func NewSchedulerRepository(db *gorm.DB) *SchedulerRepository {
+	return &SchedulerRepository{db: db}
+}
+
+// AcquireLease mengambil atau memperpanjang lease; true jika holder sekarang pemegang lease.
+// Lease milik instance lain hanya bisa diambil alih setelah kedaluwarsa. Semua waktu lease
+// memakai jam database (NOW(3)) agar selisih jam antar instance tidak membuat dua leader.
+func (r *SchedulerRepository) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	now := gorm.Expr("NOW(3)")
+	expiresAt := gorm.Expr("NOW(3) + INTERVAL ? MICROSECOND", ttl.Microseconds())
+
+	// Perpanjang lease sendiri
+	result := r.db.Model(&models.SchedulerLease{}).
+		Where("name = ? AND holder = ?", name, holder).
+		Updates(map[string]interface{}{"expires_at": expiresAt, "updated_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// Ambil alih lease instance lain yang sudah kedaluwarsa
+	result = r.db.Model(&models.SchedulerLease{}).
+		Where("name = ? AND expires_at < NOW(3)", name).
+		Updates(map[string]interface{}{"holder": holder, "expires_at": expiresAt, "acquired_at": now, "updated_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// Belum ada baris lease: instance pertama yang insert menjadi leader
+	result = r.db.Model(&models.SchedulerLease{}).Clauses(clause.OnConflict{DoNothing: true}).Create(map[string]interface{}{
+		"name":        name,
+		"holder":      holder,
+		"expires_at":  expiresAt,
+		"acquired_at": now,
+		"updated_at":  now,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseLease melepas lease (saat shutdown) agar instance lain bisa langsung mengambil alih
+func (r *SchedulerRepository) ReleaseLease(name, holder string) error {
+	return r.db.Where("name = ? AND holder = ?", name, holder).Delete(&models.SchedulerLease{}).Error
+}
+
+// FindLease mendapatkan lease (nil jika belum ada)
+func (r *SchedulerRepository) FindLease(name string) (*models.SchedulerLease, error) {
+	var lease models.SchedulerLease
+	err := r.db.First(&lease, "name = ?", name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// CreateRun mencatat job yang mulai berjalan
+func (r *SchedulerRepository) CreateRun(run *models.JobRun) error {
+	return r.db.Create(run).Error
+}
+
+// FinishRun menyimpan hasil job run
+func (r *SchedulerRepository) FinishRun(run *models.JobRun) error {
+	return r.db.Model(run).
+		Select("status", "finished_at", "duration_ms", "result", "error").
+		Updates(run).Error
+}
+
+// FindRuns riwayat job run terbaru, bisa difilter scheduler dan/atau job
+func (r *SchedulerRepository) FindRuns(scheduler, job string, limit, offset int) ([]models.JobRun, int64, error) {
+	query := r.db.Model(&models.JobRun{})
+	if scheduler != "" {
+		query = query.Where("scheduler = ?", scheduler)
+	}
+	if job != "" {
+		query = query.Where("job = ?", job)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var runs []models.JobRun
+	err := query.Order("started_at DESC").Limit(limit).Offset(offset).Find(&runs).Error
+	return runs, total, err
+}
+
+// FindLastFinishedRuns eksekusi terakhir yang sudah selesai untuk setiap job scheduler
+func (r *SchedulerRepository) FindLastFinishedRuns(scheduler string) (map[string]models.JobRun, error) {
+	var runs []models.JobRun
+	err := r.db.Where("scheduler = ? AND status <> ?", scheduler, models.JobRunRunning).
+		Where("started_at = (SELECT MAX(j2.started_at) FROM job_runs j2 WHERE j2.scheduler = job_runs.scheduler AND j2.job = job_runs.job AND j2.status <> ?)", models.JobRunRunning).
+		Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]models.JobRun, len(runs))
+	for _, run := range runs {
+		latest[run.Job] = run
+	}
+	return latest, nil
+}
+
+// FindLastRun eksekusi terakhir satu job, termasuk yang masih berjalan (nil jika belum pernah)
+func (r *SchedulerRepository) FindLastRun(scheduler, job string) (*models.JobRun, error) {
+	var run models.JobRun
+	err := r.db.Where("scheduler = ? AND job = ?", scheduler, job).
+		Order("started_at DESC").
+		First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// DeleteRunsBefore menghapus riwayat job run yang lebih lama dari cutoff
+func (r *SchedulerRepository) DeleteRunsBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("started_at < ?", cutoff).Delete(&models.JobRun{})
+	return result.RowsAffected, result.Error
+}
+
+// SetJobPaused menyimpan status pause job (upsert)
+func (r *SchedulerRepository) SetJobPaused(job string, paused bool) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job"}},
+		DoUpdates: clause.AssignmentColumns([]string{"paused", "updated_at"}),
+	}).Create(&models.ScheduledJobState{Job: job, Paused: paused}).Error
+}
+
+// FindPausedJobs nama semua job yang sedang di-pause
+func (r *SchedulerRepository) FindPausedJobs() (map[string]bool, error) {
+	var jobs []string
+	if err := r.db.Model(&models.ScheduledJobState{}).Where("paused = ?", true).Pluck("job", &jobs).Error; err != nil {
+		return nil, err
+	}
+	paused := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		paused[job] = true
+	}
+	return paused, nil
+}
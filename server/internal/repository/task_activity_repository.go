@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+)
+
+type TaskActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskActivityRepository(db *gorm.DB) *TaskActivityRepository {
+	return &TaskActivityRepository{db: db}
+}
+
+// Create mencatat aktivitas task
+func (r *TaskActivityRepository) Create(activity *models.TaskActivity) error {
+	return r.db.Create(activity).Error
+}
+
+// FindByTaskID mendapatkan timeline task, terbaru dulu
+func (r *TaskActivityRepository) FindByTaskID(taskID string, limit, offset int) ([]models.TaskActivity, error) {
+	var activities []models.TaskActivity
+	err := r.db.Preload("User").
+		Where("task_id = ?", taskID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&activities).Error
+	return activities, err
+}
+
+// CountByTaskID jumlah aktivitas task
+func (r *TaskActivityRepository) CountByTaskID(taskID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TaskActivity{}).Where("task_id = ?", taskID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TaskCommentRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskCommentRepository(db *gorm.DB) *TaskCommentRepository {
+	return &TaskCommentRepository{db: db}
+}
+
+// Create membuat komentar baru
+func (r *TaskCommentRepository) Create(comment *models.TaskComment) error {
+	return r.db.Create(comment).Error
+}
+
+// FindByID mencari komentar by ID beserta penulisnya
+func (r *TaskCommentRepository) FindByID(id string) (*models.TaskComment, error) {
+	var comment models.TaskComment
+	err := r.db.Preload("User").First(&comment, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// FindByTaskID mendapatkan semua komentar task, urut dari yang terlama
+func (r *TaskCommentRepository) FindByTaskID(taskID string) ([]models.TaskComment, error) {
+	var comments []models.TaskComment
+	err := r.db.Preload("User").
+		Where("task_id = ?", taskID).
+		Order("created_at ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+// Update memperbarui komentar
+func (r *TaskCommentRepository) Update(comment *models.TaskComment) error {
+	return r.db.Omit(clause.Associations).Save(comment).Error
+}
+
+// Delete menghapus komentar beserta balasannya
+func (r *TaskCommentRepository) Delete(id string) error {
+	return r.db.Where("id = ? OR parent_id = ?", id, id).Delete(&models.TaskComment{}).Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TaskReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskReminderRepository(db *gorm.DB) *TaskReminderRepository {
+	return &TaskReminderRepository{db: db}
+}
+
+// FindByTaskIDs mendapatkan reminder yang sudah diproses untuk daftar task
+func (r *TaskReminderRepository) FindByTaskIDs(taskIDs []string) ([]models.TaskReminderLog, error) {
+	var logs []models.TaskReminderLog
+	if len(taskIDs) == 0 {
+		return logs, nil
+	}
+	err := r.db.Where("task_id IN ?", taskIDs).Find(&logs).Error
+	return logs, err
+}
+
+// Claim menandai reminder sebagai terkirim; false jika sudah pernah diproses
+func (r *TaskReminderRepository) Claim(log *models.TaskReminderLog) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(log)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Release membatalkan claim (reminder gagal masuk antrian, boleh dicoba lagi)
+func (r *TaskReminderRepository) Release(taskID, userID string, deadline time.Time, offsetMinutes int) error {
+	return r.db.Where("task_id = ? AND user_id = ? AND deadline = ? AND offset_minutes = ?", taskID, userID, deadline, offsetMinutes).
+		Delete(&models.TaskReminderLog{}).Error
+}
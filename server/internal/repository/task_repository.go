@@ -23,7 +23,7 @@ func (r *TaskRepository) Create(task *models.Task) error {
 // FindByID mencari task by ID dengan category
 func (r *TaskRepository) FindByID(id string) (*models.Task, error) {
 	var task models.Task
-	err := r.db.Preload("Category").First(&task, "id = ?", id).Error
+	err := r.db.Preload("Category").Preload("Assignees").First(&task, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +60,208 @@ func (r *TaskRepository) FindByUserIDAndCategory(userID, categoryID string) ([]m
 	return tasks, err
 }
 
+// FindByWorkspaceID mencari tasks bersama dalam workspace
+func (r *TaskRepository) FindByWorkspaceID(workspaceID string, categoryID *string) ([]models.Task, error) {
+	var tasks []models.Task
+	query := r.db.Preload("Category").Preload("Assignees").Where("workspace_id = ?", workspaceID)
+	if categoryID != nil && *categoryID != "" {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+	err := query.Order("created_at DESC").Find(&tasks).Error
+	return tasks, err
+}
+
+// FindAssignedToUser mencari tasks yang ditugaskan ke user
+func (r *TaskRepository) FindAssignedToUser(userID string) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("Category").Preload("Assignees").
+		Where("id IN (?)", r.db.Model(&models.TaskAssignee{}).Select("task_id").Where("user_id = ?", userID)).
+		Order("is_completed ASC, deadline IS NULL, deadline ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindAssignedByUser mencari tasks yang ditugaskan user ke member lain
+func (r *TaskRepository) FindAssignedByUser(userID string) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("Category").Preload("Assignees").
+		Where("id IN (?)", r.db.Model(&models.TaskAssignee{}).Select("task_id").Where("assigned_by = ? AND user_id <> ?", userID, userID)).
+		Order("is_completed ASC, deadline IS NULL, deadline ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// workloadScope task yang menjadi beban kerja user: task yang ditugaskan ke user,
+// ditambah task milik user yang tidak punya assignee
+const workloadScope = "((user_id = ? AND NOT EXISTS (SELECT 1 FROM task_assignees WHERE task_assignees.task_id = tasks.id)) OR id IN (SELECT task_id FROM task_assignees WHERE user_id = ?))"
+
+// FindWorkloadByUserIDAndDateRange mencari tasks yang menjadi beban kerja user dalam range tanggal
+func (r *TaskRepository) FindWorkloadByUserIDAndDateRange(userID string, start, end time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("Category").
+		Where(workloadScope, userID, userID).
+		Where("deadline BETWEEN ? AND ?", start, end).
+		Order("deadline ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// CountOpenWorkload menghitung task belum selesai yang menjadi beban kerja user
+func (r *TaskRepository) CountOpenWorkload(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).
+		Where(workloadScope, userID, userID).
+		Where("is_completed = ?", false).
+		Count(&count).Error
+	return count, err
+}
+
+// CountOverdueWorkload menghitung task beban kerja user yang lewat deadline dan belum selesai
+func (r *TaskRepository) CountOverdueWorkload(userID string, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).
+		Where(workloadScope, userID, userID).
+		Where("is_completed = ? AND deadline IS NOT NULL AND deadline < ?", false, now).
+		Count(&count).Error
+	return count, err
+}
+
+// FindWorkspaceWorkloadByDateRange beban kerja user dalam range tanggal, hanya task di workspace
+func (r *TaskRepository) FindWorkspaceWorkloadByDateRange(workspaceID, userID string, start, end time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("Category").
+		Where("workspace_id = ?", workspaceID).
+		Where(workloadScope, userID, userID).
+		Where("deadline BETWEEN ? AND ?", start, end).
+		Order("deadline ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// CountOpenWorkspaceWorkload task belum selesai yang menjadi beban kerja user di workspace
+func (r *TaskRepository) CountOpenWorkspaceWorkload(workspaceID, userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).
+		Where("workspace_id = ?", workspaceID).
+		Where(workloadScope, userID, userID).
+		Where("is_completed = ?", false).
+		Count(&count).Error
+	return count, err
+}
+
+// CountOverdueWorkspaceWorkload task beban kerja user di workspace yang lewat deadline dan belum selesai
+func (r *TaskRepository) CountOverdueWorkspaceWorkload(workspaceID, userID string, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).
+		Where("workspace_id = ?", workspaceID).
+		Where(workloadScope, userID, userID).
+		Where("is_completed = ? AND deadline IS NOT NULL AND deadline < ?", false, now).
+		Count(&count).Error
+	return count, err
+}
+
+// FindOverdueWorkload mencari task beban kerja user yang belum selesai dengan deadline sebelum `before`
+func (r *TaskRepository) FindOverdueWorkload(userID string, before time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("Category").
+		Where(workloadScope, userID, userID).
+		Where("is_completed = ? AND deadline IS NOT NULL AND deadline < ?", false, before).
+		Order("deadline ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindOverdueForPolicy mencari task yang lewat deadline, belum selesai dan belum ditandai terlewat.
+// Keyset paging: halaman berikutnya dimulai setelah (afterDeadline, afterID); afterID kosong = halaman pertama.
+func (r *TaskRepository) FindOverdueForPolicy(now time.Time, afterDeadline time.Time, afterID string, limit int) ([]models.Task, error) {
+	var tasks []models.Task
+	query := r.db.Preload("Assignees").
+		Where("is_completed = ? AND deadline IS NOT NULL AND deadline < ? AND missed_at IS NULL", false, now)
+	if afterID != "" {
+		query = query.Where("(deadline > ? OR (deadline = ? AND id > ?))", afterDeadline, afterDeadline, afterID)
+	}
+	err := query.Order("deadline ASC, id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// UpdateOverdueState mengubah kolom overdue saja, hanya jika task masih belum selesai dan
+// deadline-nya belum diubah sejak dibaca. false berarti task sudah berubah (dilewati).
+func (r *TaskRepository) UpdateOverdueState(taskID string, deadline time.Time, updates map[string]interface{}) (bool, error) {
+	result := r.db.Model(&models.Task{}).
+		Where("id = ? AND is_completed = ? AND deadline = ?", taskID, false, deadline).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindCompletedWorkload mencari task beban kerja user yang diselesaikan dalam range waktu
+func (r *TaskRepository) FindCompletedWorkload(userID string, start, end time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Where(workloadScope, userID, userID).
+		Where("is_completed = ? AND completed_at BETWEEN ? AND ?", true, start, end).
+		Order("completed_at ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindWithPendingReminders mencari task belum selesai yang punya reminder dan deadline dalam
+// (since, until], beserta assignee; reminder yang dibisukan pada now dilewati
+func (r *TaskRepository) FindWithPendingReminders(since, now, until time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("Assignees").
+		Where("is_completed = ? AND deadline > ? AND deadline <= ?", false, since, until).
+		Where("reminder_minutes IS NOT NULL OR (reminder_offsets IS NOT NULL AND reminder_offsets NOT IN ('null', '[]'))").
+		Where("reminder_muted_until IS NULL OR reminder_muted_until <= ?", now).
+		Order("deadline ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// ReplaceAssignees mengganti daftar assignee task dalam satu transaksi
+func (r *TaskRepository) ReplaceAssignees(taskID, assignedBy string, userIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("task_id = ?", taskID)
+		if len(userIDs) > 0 {
+			query = query.Where("user_id NOT IN ?", userIDs)
+		}
+		if err := query.Delete(&models.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+
+		var existing []string
+		if err := tx.Model(&models.TaskAssignee{}).Where("task_id = ?", taskID).Pluck("user_id", &existing).Error; err != nil {
+			return err
+		}
+		kept := make(map[string]bool, len(existing))
+		for _, id := range existing {
+			kept[id] = true
+		}
+
+		for _, userID := range userIDs {
+			if kept[userID] {
+				continue
+			}
+			if err := tx.Create(&models.TaskAssignee{TaskID: taskID, UserID: userID, AssignedBy: assignedBy}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CopyAssignees menyalin assignee ke task lain (occurrence berikutnya dari task berulang)
+func (r *TaskRepository) CopyAssignees(assignees []models.TaskAssignee, taskID string) error {
+	copies := make([]models.TaskAssignee, 0, len(assignees))
+	for _, assignee := range assignees {
+		copies = append(copies, models.TaskAssignee{TaskID: taskID, UserID: assignee.UserID, AssignedBy: assignee.AssignedBy})
+	}
+	if len(copies) == 0 {
+		return nil
+	}
+	return r.db.Create(&copies).Error
+}
+
 // FindByUserIDAndDateRange mencari tasks dalam range tanggal
 func (r *TaskRepository) FindByUserIDAndDateRange(userID string, start, end time.Time) ([]models.Task, error) {
 	var tasks []models.Task
@@ -70,14 +272,63 @@ func (r *TaskRepository) FindByUserIDAndDateRange(userID string, start, end time
 	return tasks, err
 }
 
-// Update memperbarui task
+// FindIncompleteByUserIDAndDateRange mencari tasks belum selesai dengan deadline dalam range
+func (r *TaskRepository) FindIncompleteByUserIDAndDateRange(userID string, start, end time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Where("user_id = ? AND is_completed = ? AND deadline BETWEEN ? AND ?", userID, false, start, end).
+		Order("deadline ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindCreatedByUserIDAndDateRange mencari tasks yang dibuat dalam range tanggal
+func (r *TaskRepository) FindCreatedByUserIDAndDateRange(userID string, start, end time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("Category").
+		Where("user_id = ? AND created_at BETWEEN ? AND ?", userID, start, end).
+		Order("created_at ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindCompletedByUserIDAndDateRange mencari tasks yang diselesaikan dalam range tanggal
+func (r *TaskRepository) FindCompletedByUserIDAndDateRange(userID string, start, end time.Time) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Preload("Category").
+		Where("user_id = ? AND is_completed = ? AND completed_at BETWEEN ? AND ?", userID, true, start, end).
+		Order("completed_at ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// Update memperbarui task (assignee dikelola lewat ReplaceAssignees)
 func (r *TaskRepository) Update(task *models.Task) error {
-	return r.db.Save(task).Error
+	return r.db.Omit("Assignees").Save(task).Error
 }
 
-// Delete menghapus task
+// Delete menghapus task beserta assignee, komentar, riwayat aktivitas, log reminder, snooze & log overdue
 func (r *TaskRepository) Delete(id string) error {
-	return r.db.Delete(&models.Task{}, "id = ?", id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ?", id).Delete(&models.TaskReminderLog{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ?", id).Delete(&models.TaskSnooze{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ?", id).Delete(&models.OverdueActionLog{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ?", id).Delete(&models.TaskActivity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ?", id).Delete(&models.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id = ?", id).Delete(&models.TaskComment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Task{}, "id = ?", id).Error
+	})
 }
 
 // CountByUserID menghitung total tasks user
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TaskSnoozeRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskSnoozeRepository(db *gorm.DB) *TaskSnoozeRepository {
+	return &TaskSnoozeRepository{db: db}
+}
+
+// Save membuat snooze atau menimpa snooze user sebelumnya untuk task yang sama
+func (r *TaskSnoozeRepository) Save(snooze *models.TaskSnooze) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"deadline", "duration", "snoozed_until", "fired_at", "updated_at"}),
+	}).Create(snooze).Error
+}
+
+// Find mendapatkan snooze user untuk task (nil jika tidak ada)
+func (r *TaskSnoozeRepository) Find(taskID, userID string) (*models.TaskSnooze, error) {
+	var snooze models.TaskSnooze
+	err := r.db.First(&snooze, "task_id = ? AND user_id = ?", taskID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snooze, nil
+}
+
+// FindPendingByTaskIDs snooze yang belum dikirim ulang untuk daftar task
+func (r *TaskSnoozeRepository) FindPendingByTaskIDs(taskIDs []string) ([]models.TaskSnooze, error) {
+	var snoozes []models.TaskSnooze
+	if len(taskIDs) == 0 {
+		return snoozes, nil
+	}
+	err := r.db.Where("task_id IN ? AND fired_at IS NULL", taskIDs).Find(&snoozes).Error
+	return snoozes, err
+}
+
+// FindDue snooze yang waktunya sudah tiba dan belum dikirim ulang
+func (r *TaskSnoozeRepository) FindDue(now time.Time) ([]models.TaskSnooze, error) {
+	var snoozes []models.TaskSnooze
+	err := r.db.Where("fired_at IS NULL AND snoozed_until <= ?", now).
+		Order("snoozed_until ASC").
+		Find(&snoozes).Error
+	return snoozes, err
+}
+
+// ClaimFired menandai snooze sudah dikirim ulang; false jika sudah diproses (mis. instance lain)
+// atau sudah diganti snooze baru
+func (r *TaskSnoozeRepository) ClaimFired(snooze *models.TaskSnooze, firedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.TaskSnooze{}).
+		Where("id = ? AND fired_at IS NULL AND snoozed_until = ?", snooze.ID, snooze.SnoozedUntil).
+		Update("fired_at", firedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseFired membatalkan claim (pengingat gagal masuk antrian, boleh dicoba lagi)
+func (r *TaskSnoozeRepository) ReleaseFired(snooze *models.TaskSnooze) error {
+	return r.db.Model(&models.TaskSnooze{}).
+		Where("id = ? AND snoozed_until = ?", snooze.ID, snooze.SnoozedUntil).
+		Update("fired_at", nil).Error
+}
+
+// Delete membatalkan snooze user untuk task
+func (r *TaskSnoozeRepository) Delete(taskID, userID string) (int64, error) {
+	result := r.db.Where("task_id = ? AND user_id = ?", taskID, userID).Delete(&models.TaskSnooze{})
+	return result.RowsAffected, result.Error
+}
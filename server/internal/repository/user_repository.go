@@ -111,6 +111,31 @@ func (r *UserRepository) UpdateWorkDays(userID string, workDays *string) error {
 		Update("work_days", workDays).Error
 }
 
+// UpdateHolidayRegion memperbarui region libur daerah user
+func (r *UserRepository) UpdateHolidayRegion(userID string, region *string) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("holiday_region", region).Error
+}
+
+// UpdateDeadlineShiftPolicy memperbarui policy default pergeseran deadline user
+func (r *UserRepository) UpdateDeadlineShiftPolicy(userID string, policy models.DeadlineShiftPolicy) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("deadline_shift_policy", policy).Error
+}
+
+// PromoteToAdmin memberi peran admin ke user dengan email tersebut (superadmin tidak diturunkan)
+func (r *UserRepository) PromoteToAdmin(emails []string) (int64, error) {
+	if len(emails) == 0 {
+		return 0, nil
+	}
+	result := r.db.Model(&models.User{}).
+		Where("email IN ? AND role NOT IN ?", emails, []models.UserRole{models.UserRoleAdmin, models.UserRoleSuperAdmin}).
+		Update("role", models.UserRoleAdmin)
+	return result.RowsAffected, result.Error
+}
+
 // GetByID alias for FindByID
 func (r *UserRepository) GetByID(id string) (*models.User, error) {
 	return r.FindByID(id)
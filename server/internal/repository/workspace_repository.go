@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WorkspaceRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository(db *gorm.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+// CreateWithOwner membuat workspace sekaligus member owner
+func (r *WorkspaceRepository) CreateWithOwner(workspace *models.Workspace) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      workspace.OwnerID,
+			Role:        models.WorkspaceRoleOwner,
+		}).Error
+	})
+}
+
+// FindByID mencari workspace by ID
+func (r *WorkspaceRepository) FindByID(id string) (*models.Workspace, error) {
+	var workspace models.Workspace
+	err := r.db.First(&workspace, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// FindByMember mendapatkan workspace di mana user menjadi member
+func (r *WorkspaceRepository) FindByMember(userID string) ([]models.Workspace, error) {
+	var workspaces []models.Workspace
+	err := r.db.Where("id IN (?)",
+		r.db.Model(&models.WorkspaceMember{}).Select("workspace_id").Where("user_id = ?", userID)).
+		Order("name ASC").
+		Find(&workspaces).Error
+	return workspaces, err
+}
+
+// Update memperbarui workspace
+func (r *WorkspaceRepository) Update(workspace *models.Workspace) error {
+	return r.db.Save(workspace).Error
+}
+
+// Delete menghapus workspace beserta member & undangan.
+// Task dan kategori bersama dikembalikan menjadi milik pribadi pembuatnya, assignee dilepas.
+func (r *WorkspaceRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id IN (?)", tx.Model(&models.Task{}).Select("id").Where("workspace_id = ?", id)).
+			Delete(&models.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Task{}).Where("workspace_id = ?", id).Update("workspace_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("workspace_id = ?", id).Update("workspace_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.WorkspaceInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", id).Delete(&models.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Workspace{}, "id = ?", id).Error
+	})
+}
+
+// ============ Members ============
+
+// FindMember mencari keanggotaan user di workspace
+func (r *WorkspaceRepository) FindMember(workspaceID, userID string) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	err := r.db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindMembers mendapatkan semua member workspace
+func (r *WorkspaceRepository) FindMembers(workspaceID string) ([]models.WorkspaceMember, error) {
+	var members []models.WorkspaceMember
+	err := r.db.Preload("User").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// FindSharedRoles role actor di workspace yang juga diikuti user
+func (r *WorkspaceRepository) FindSharedRoles(actorID, userID string) ([]models.WorkspaceRole, error) {
+	var roles []models.WorkspaceRole
+	err := r.db.Model(&models.WorkspaceMember{}).
+		Where("user_id = ? AND workspace_id IN (?)", actorID,
+			r.db.Model(&models.WorkspaceMember{}).Select("workspace_id").Where("user_id = ?", userID)).
+		Distinct().
+		Pluck("role", &roles).Error
+	return roles, err
+}
+
+// FindManagerIDs owner/admin dari workspace yang diikuti user (tanpa user itu sendiri)
+func (r *WorkspaceRepository) FindManagerIDs(userID string) ([]string, error) {
+	var ids []string
+	err := r.db.Model(&models.WorkspaceMember{}).
+		Where("user_id <> ? AND role IN ? AND workspace_id IN (?)", userID,
+			[]models.WorkspaceRole{models.WorkspaceRoleOwner, models.WorkspaceRoleAdmin},
+			r.db.Model(&models.WorkspaceMember{}).Select("workspace_id").Where("user_id = ?", userID)).
+		Distinct().
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// CountMembers jumlah member workspace
+func (r *WorkspaceRepository) CountMembers(workspaceID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.WorkspaceMember{}).Where("workspace_id = ?", workspaceID).Count(&count).Error
+	return count, err
+}
+
+// CreateMember menambah member
+func (r *WorkspaceRepository) CreateMember(member *models.WorkspaceMember) error {
+	return r.db.Create(member).Error
+}
+
+// UpdateMemberRole mengubah role member
+func (r *WorkspaceRepository) UpdateMemberRole(workspaceID, userID string, role models.WorkspaceRole) error {
+	return r.db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Update("role", role).Error
+}
+
+// DeleteMember menghapus member beserta penugasannya di task workspace
+func (r *WorkspaceRepository) DeleteMember(workspaceID, userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND task_id IN (?)", userID,
+			tx.Model(&models.Task{}).Select("id").Where("workspace_id = ?", workspaceID)).
+			Delete(&models.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+			Delete(&models.WorkspaceMember{}).Error
+	})
+}
+
+// ============ Invitations ============
+
+// CreateInvitation membuat undangan
+func (r *WorkspaceRepository) CreateInvitation(invitation *models.WorkspaceInvitation) error {
+	return r.db.Create(invitation).Error
+}
+
+// FindInvitationByID mencari undangan by ID
+func (r *WorkspaceRepository) FindInvitationByID(id string) (*models.WorkspaceInvitation, error) {
+	var invitation models.WorkspaceInvitation
+	err := r.db.Preload("Workspace").First(&invitation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindPendingInvitation mencari undangan pending yang masih berlaku untuk email
+func (r *WorkspaceRepository) FindPendingInvitation(workspaceID, email string) (*models.WorkspaceInvitation, error) {
+	var invitation models.WorkspaceInvitation
+	err := r.db.Where("workspace_id = ? AND email = ? AND status = ? AND expires_at > ?",
+		workspaceID, email, models.InvitationPending, time.Now()).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindInvitationsByWorkspace mendapatkan undangan workspace
+func (r *WorkspaceRepository) FindInvitationsByWorkspace(workspaceID string) ([]models.WorkspaceInvitation, error) {
+	var invitations []models.WorkspaceInvitation
+	err := r.db.Where("workspace_id = ?", workspaceID).Order("created_at DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+// FindPendingInvitationsByEmail mendapatkan undangan pending untuk email
+func (r *WorkspaceRepository) FindPendingInvitationsByEmail(email string) ([]models.WorkspaceInvitation, error) {
+	var invitations []models.WorkspaceInvitation
+	err := r.db.Preload("Workspace").
+		Where("email = ? AND status = ? AND expires_at > ?", email, models.InvitationPending, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// UpdateInvitation memperbarui undangan
+func (r *WorkspaceRepository) UpdateInvitation(invitation *models.WorkspaceInvitation) error {
+	return r.db.Omit(clause.Associations).Save(invitation).Error
+}
+
+// AcceptInvitation menandai undangan diterima dan menambah member dalam satu transaksi
+func (r *WorkspaceRepository) AcceptInvitation(invitation *models.WorkspaceInvitation, member *models.WorkspaceMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(invitation).Error; err != nil {
+			return err
+		}
+		return tx.Create(member).Error
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/workradar/server/internal/database"
+	"github.com/workradar/server/internal/models"
 	"gorm.io/gorm"
 )
 
@@ -46,8 +47,19 @@ const (
 	PermissionPaymentRead    Permission = "payment:read"
 	PermissionPaymentProcess Permission = "payment:process"
 
+	// Holiday data permissions
+	PermissionHolidayManage Permission = "holiday:manage"
+
+	// Leave permissions (policy, saldo & approval cuti user lain)
+	PermissionLeaveManage Permission = "leave:manage"
+
 	// Admin permissions
 	PermissionAdminFull Permission = "admin:full"
+
+	// Workspace permissions (dicek terhadap role di workspace, bukan user_type)
+	PermissionWorkspaceRead         Permission = "workspace:read"
+	PermissionWorkspaceManage       Permission = "workspace:manage"
+	PermissionWorkspaceWorkloadRead Permission = "workspace:workload_read"
 )
 
 // Role represents a user role with permissions
@@ -63,9 +75,10 @@ const (
 
 // AccessControlService manages permissions and access control
 type AccessControlService struct {
-	mu              sync.RWMutex
-	rolePermissions map[Role][]Permission
-	connManager     *database.MultiConnectionManager
+	mu                       sync.RWMutex
+	rolePermissions          map[Role][]Permission
+	workspaceRolePermissions map[models.WorkspaceRole][]Permission
+	connManager              *database.MultiConnectionManager
 }
 
 var (
@@ -77,8 +90,9 @@ var (
 func GetAccessControlService() *AccessControlService {
 	accessControlServiceOnce.Do(func() {
 		accessControlService = &AccessControlService{
-			rolePermissions: make(map[Role][]Permission),
-			connManager:     database.GetConnectionManager(),
+			rolePermissions:          make(map[Role][]Permission),
+			workspaceRolePermissions: make(map[models.WorkspaceRole][]Permission),
+			connManager:              database.GetConnectionManager(),
 		}
 		accessControlService.initializeDefaultPermissions()
 	})
@@ -142,6 +156,8 @@ func (s *AccessControlService) initializeDefaultPermissions() {
 		PermissionSecurityManage,
 		PermissionPaymentRead,
 		PermissionPaymentProcess,
+		PermissionHolidayManage,
+		PermissionLeaveManage,
 	}
 
 	// Super admin has all permissions
@@ -166,8 +182,27 @@ func (s *AccessControlService) initializeDefaultPermissions() {
 		PermissionSecurityManage,
 		PermissionPaymentRead,
 		PermissionPaymentProcess,
+		PermissionHolidayManage,
+		PermissionLeaveManage,
 		PermissionAdminFull,
 	}
+
+	// Workspace role permissions
+	s.workspaceRolePermissions[models.WorkspaceRoleMember] = []Permission{
+		PermissionWorkspaceRead,
+	}
+	s.workspaceRolePermissions[models.WorkspaceRoleAdmin] = []Permission{
+		PermissionWorkspaceRead,
+		PermissionWorkspaceManage,
+		PermissionWorkspaceWorkloadRead,
+		PermissionLeaveManage,
+	}
+	s.workspaceRolePermissions[models.WorkspaceRoleOwner] = []Permission{
+		PermissionWorkspaceRead,
+		PermissionWorkspaceManage,
+		PermissionWorkspaceWorkloadRead,
+		PermissionLeaveManage,
+	}
 }
 
 // HasPermission checks if a role has a specific permission
@@ -189,6 +224,28 @@ func (s *AccessControlService) HasPermission(role Role, permission Permission) b
 	return false
 }
 
+// HasWorkspacePermission checks if a workspace role has a specific permission
+func (s *AccessControlService) HasWorkspacePermission(role models.WorkspaceRole, permission Permission) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, p := range s.workspaceRolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnforceWorkspaceAccess returns error if the workspace role doesn't have permission
+func (s *AccessControlService) EnforceWorkspaceAccess(role models.WorkspaceRole, permission Permission) error {
+	if !s.HasWorkspacePermission(role, permission) {
+		return errors.New("access denied: insufficient workspace permissions")
+	}
+	return nil
+}
+
 // GetRolePermissions returns all permissions for a role
 func (s *AccessControlService) GetRolePermissions(role Role) []Permission {
 	s.mu.RLock()
@@ -223,17 +280,30 @@ func (s *AccessControlService) getUserRole(userID string) (Role, error) {
 		db = database.DB
 	}
 
-	var userType string
+	var user struct {
+		UserType string
+		Role     string
+	}
 	err := db.Table("users").
-		Select("user_type").
+		Select("user_type, role").
 		Where("id = ?", userID).
-		Scan(&userType).Error
+		Scan(&user).Error
 
 	if err != nil {
 		return "", err
 	}
 
-	switch userType {
+	// Peran administratif lebih tinggi dari paket langganan
+	switch models.UserRole(user.Role) {
+	case models.UserRoleModerator:
+		return RoleModerator, nil
+	case models.UserRoleAdmin:
+		return RoleAdmin, nil
+	case models.UserRoleSuperAdmin:
+		return RoleSuperAdmin, nil
+	}
+
+	switch user.UserType {
 	case "regular":
 		return RoleUser, nil
 	case "vip":
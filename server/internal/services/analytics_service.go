@@ -0,0 +1,296 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// AnalyticsService analisis waktu penyelesaian task (CompletedAt, Deadline, CreatedAt).
+// Bucket hari, jam dan minggu mengikuti zona waktu notifikasi user.
+type AnalyticsService struct {
+	taskRepo          *repository.TaskRepository
+	preferenceService *NotificationPreferenceService
+}
+
+func NewAnalyticsService(taskRepo *repository.TaskRepository, preferenceService *NotificationPreferenceService) *AnalyticsService {
+	return &AnalyticsService{taskRepo: taskRepo, preferenceService: preferenceService}
+}
+
+// Location zona waktu notifikasi user (default Asia/Jakarta)
+func (s *AnalyticsService) Location(userID string) *time.Location {
+	if s.preferenceService == nil {
+		return notificationLocation(models.DefaultNotificationTimeZone)
+	}
+	return s.preferenceService.Location(userID)
+}
+
+// HeatmapDay jumlah task selesai dalam satu hari
+type HeatmapDay struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+	Level int    `json:"level"` // 0-4, untuk intensitas warna ala GitHub
+}
+
+// HeatmapResponse response heatmap satu tahun
+type HeatmapResponse struct {
+	Year           int          `json:"year"`
+	TotalCompleted int          `json:"total_completed"`
+	MaxPerDay      int          `json:"max_per_day"`
+	ActiveDays     int          `json:"active_days"`
+	Days           []HeatmapDay `json:"days"`
+}
+
+// DistributionResponse distribusi jam & hari penyelesaian task
+type DistributionResponse struct {
+	TotalCompleted int            `json:"total_completed"`
+	HourOfDay      []WorkloadData `json:"hour_of_day"` // 24 bucket, label "00".."23"
+	Weekday        []WorkloadData `json:"weekday"`     // 7 bucket, Senin duluan
+	PeakHour       *int           `json:"peak_hour,omitempty"`
+	PeakWeekday    string         `json:"peak_weekday,omitempty"`
+}
+
+// CategoryCompletionTime rata-rata waktu dari dibuat sampai selesai per kategori
+type CategoryCompletionTime struct {
+	CategoryID   *string `json:"category_id,omitempty"`
+	CategoryName string  `json:"category_name"`
+	Completed    int     `json:"completed"`
+	AverageHours float64 `json:"average_hours"`
+	MedianHours  float64 `json:"median_hours"`
+}
+
+// CompletionTimeResponse response waktu penyelesaian per kategori
+type CompletionTimeResponse struct {
+	OverallAverageHours float64                  `json:"overall_average_hours"`
+	Categories          []CategoryCompletionTime `json:"categories"`
+}
+
+// LatenessResponse porsi task yang selesai setelah deadline
+type LatenessResponse struct {
+	CompletedWithDeadline int     `json:"completed_with_deadline"`
+	CompletedLate         int     `json:"completed_late"`
+	LateShare             float64 `json:"late_share"` // persen
+	AverageLatenessHours  float64 `json:"average_lateness_hours"`
+}
+
+// GetCompletionHeatmap heatmap harian task selesai selama satu tahun
+func (s *AnalyticsService) GetCompletionHeatmap(userID string, year int) (*HeatmapResponse, error) {
+	if year < 2000 || year > 2100 {
+		return nil, errors.New("invalid year")
+	}
+
+	loc := s.Location(userID)
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0).Add(-time.Second)
+
+	tasks, err := s.taskRepo.FindCompletedByUserIDAndDateRange(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, task := range tasks {
+		counts[task.CompletedAt.In(loc).Format("2006-01-02")]++
+	}
+
+	response := &HeatmapResponse{
+		Year:           year,
+		TotalCompleted: len(tasks),
+		Days:           []HeatmapDay{},
+	}
+	for _, count := range counts {
+		if count > response.MaxPerDay {
+			response.MaxPerDay = count
+		}
+	}
+
+	for day := start; day.Year() == year; day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		count := counts[key]
+		if count > 0 {
+			response.ActiveDays++
+		}
+		response.Days = append(response.Days, HeatmapDay{
+			Date:  key,
+			Count: count,
+			Level: heatmapLevel(count, response.MaxPerDay),
+		})
+	}
+
+	return response, nil
+}
+
+// GetCompletionDistribution distribusi jam (0-23) dan hari penyelesaian task
+func (s *AnalyticsService) GetCompletionDistribution(userID string, start, end time.Time) (*DistributionResponse, error) {
+	tasks, err := s.taskRepo.FindCompletedByUserIDAndDateRange(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := s.Location(userID)
+	hours := make([]int, 24)
+	weekdays := make([]int, 7) // Senin = 0
+	for _, task := range tasks {
+		completedAt := task.CompletedAt.In(loc)
+		hours[completedAt.Hour()]++
+		weekdays[mondayIndex(completedAt.Weekday())]++
+	}
+
+	response := &DistributionResponse{
+		TotalCompleted: len(tasks),
+		HourOfDay:      make([]WorkloadData, 24),
+		Weekday:        make([]WorkloadData, 7),
+	}
+
+	dayLabels := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+	peakHour, peakDay := -1, -1
+	for h, count := range hours {
+		response.HourOfDay[h] = WorkloadData{Label: time.Date(0, 1, 1, h, 0, 0, 0, time.UTC).Format("15"), Count: count}
+		if count > 0 && (peakHour < 0 || count > hours[peakHour]) {
+			peakHour = h
+		}
+	}
+	for d, count := range weekdays {
+		response.Weekday[d] = WorkloadData{Label: dayLabels[d], Count: count}
+		if count > 0 && (peakDay < 0 || count > weekdays[peakDay]) {
+			peakDay = d
+		}
+	}
+
+	if peakHour >= 0 {
+		response.PeakHour = &peakHour
+	}
+	if peakDay >= 0 {
+		response.PeakWeekday = dayLabels[peakDay]
+	}
+
+	return response, nil
+}
+
+// GetCompletionTimeByCategory rata-rata durasi CreatedAt -> CompletedAt per kategori
+func (s *AnalyticsService) GetCompletionTimeByCategory(userID string, start, end time.Time) (*CompletionTimeResponse, error) {
+	tasks, err := s.taskRepo.FindCompletedByUserIDAndDateRange(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type bucket struct {
+		categoryID *string
+		name       string
+		hours      []float64
+	}
+	buckets := map[string]*bucket{}
+	var allHours []float64
+
+	for _, task := range tasks {
+		if task.CompletedAt == nil || task.CompletedAt.Before(task.CreatedAt) {
+			continue
+		}
+
+		key, name := "", "Tanpa Kategori"
+		if task.Category != nil {
+			key, name = task.Category.ID, task.Category.Name
+		}
+		if _, ok := buckets[key]; !ok {
+			buckets[key] = &bucket{categoryID: task.CategoryID, name: name}
+		}
+
+		hours := task.CompletedAt.Sub(task.CreatedAt).Hours()
+		buckets[key].hours = append(buckets[key].hours, hours)
+		allHours = append(allHours, hours)
+	}
+
+	response := &CompletionTimeResponse{
+		OverallAverageHours: average(allHours),
+		Categories:          []CategoryCompletionTime{},
+	}
+	for _, b := range buckets {
+		response.Categories = append(response.Categories, CategoryCompletionTime{
+			CategoryID:   b.categoryID,
+			CategoryName: b.name,
+			Completed:    len(b.hours),
+			AverageHours: average(b.hours),
+			MedianHours:  median(b.hours),
+		})
+	}
+	sort.Slice(response.Categories, func(i, j int) bool {
+		return response.Categories[i].Completed > response.Categories[j].Completed
+	})
+
+	return response, nil
+}
+
+// GetLateCompletionShare porsi task yang diselesaikan setelah deadline
+func (s *AnalyticsService) GetLateCompletionShare(userID string, start, end time.Time) (*LatenessResponse, error) {
+	tasks, err := s.taskRepo.FindCompletedByUserIDAndDateRange(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LatenessResponse{}
+	var lateHours []float64
+	for _, task := range tasks {
+		if task.Deadline == nil || task.CompletedAt == nil {
+			continue
+		}
+		response.CompletedWithDeadline++
+		if task.CompletedAt.After(*task.Deadline) {
+			response.CompletedLate++
+			lateHours = append(lateHours, task.CompletedAt.Sub(*task.Deadline).Hours())
+		}
+	}
+
+	if response.CompletedWithDeadline > 0 {
+		response.LateShare = float64(response.CompletedLate) / float64(response.CompletedWithDeadline) * 100
+	}
+	response.AverageLatenessHours = average(lateHours)
+
+	return response, nil
+}
+
+// heatmapLevel membagi jumlah harian ke 5 level intensitas relatif terhadap hari tersibuk
+func heatmapLevel(count, max int) int {
+	if count == 0 || max == 0 {
+		return 0
+	}
+	level := (count*4 + max - 1) / max
+	if level > 4 {
+		level = 4
+	}
+	return level
+}
+
+// mondayIndex mengubah time.Weekday menjadi index dengan Senin = 0
+func mondayIndex(day time.Weekday) int {
+	if day == time.Sunday {
+		return 6
+	}
+	return int(day) - 1
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
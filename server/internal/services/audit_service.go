@@ -40,6 +40,13 @@ func NewAuditService(auditRepo *repository.AuditRepository) *AuditService {
 	}
 }
 
+// RequestMeta request info for audit entries written from services
+type RequestMeta struct {
+	IP        string
+	UserAgent string
+	Path      string
+}
+
 // ==================== AUDIT LOGGING ====================
 
 // LogCreate logs a CREATE operation
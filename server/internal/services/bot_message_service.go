@@ -9,11 +9,12 @@ import (
 )
 
 type BotMessageService struct {
-	repo *repository.BotMessageRepository
+	repo   *repository.BotMessageRepository
+	broker EventBroker
 }
 
-func NewBotMessageService(repo *repository.BotMessageRepository) *BotMessageService {
-	return &BotMessageService{repo: repo}
+func NewBotMessageService(repo *repository.BotMessageRepository, broker EventBroker) *BotMessageService {
+	return &BotMessageService{repo: repo, broker: broker}
 }
 
 // SendMessage creates and saves a new bot message
@@ -32,6 +33,15 @@ func (s *BotMessageService) SendMessage(userID string, msgType models.MessageTyp
 		return nil, err
 	}
 
+	// Push ke client yang terhubung (menggantikan polling unread count)
+	if s.broker != nil {
+		unread, _ := s.repo.CountUnread(userID)
+		s.broker.Publish(userID, RealtimeMessageCreated, map[string]interface{}{
+			"message":      message,
+			"unread_count": unread,
+		})
+	}
+
 	return message, nil
 }
 
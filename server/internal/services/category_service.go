@@ -9,17 +9,20 @@ import (
 )
 
 type CategoryService struct {
-	categoryRepo *repository.CategoryRepository
-	taskRepo     *repository.TaskRepository
+	categoryRepo     *repository.CategoryRepository
+	taskRepo         *repository.TaskRepository
+	workspaceService *WorkspaceService
 }
 
 func NewCategoryService(
 	categoryRepo *repository.CategoryRepository,
 	taskRepo *repository.TaskRepository,
+	workspaceService *WorkspaceService,
 ) *CategoryService {
 	return &CategoryService{
-		categoryRepo: categoryRepo,
-		taskRepo:     taskRepo,
+		categoryRepo:     categoryRepo,
+		taskRepo:         taskRepo,
+		workspaceService: workspaceService,
 	}
 }
 
@@ -28,6 +31,36 @@ func (s *CategoryService) GetCategories(userID string) ([]models.Category, error
 	return s.categoryRepo.FindByUserID(userID)
 }
 
+// GetWorkspaceCategories mendapatkan kategori bersama di workspace (member saja)
+func (s *CategoryService) GetWorkspaceCategories(userID, workspaceID string) ([]models.Category, error) {
+	if _, err := s.workspaceService.RequireMember(workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return s.categoryRepo.FindByWorkspaceID(workspaceID)
+}
+
+// categoriesInScope kategori yang namanya harus unik: pribadi milik user atau bersama di workspace
+func (s *CategoryService) categoriesInScope(userID string, workspaceID *string) []models.Category {
+	if workspaceID != nil {
+		categories, _ := s.categoryRepo.FindByWorkspaceID(*workspaceID)
+		return categories
+	}
+	categories, _ := s.categoryRepo.FindByUserID(userID)
+	return categories
+}
+
+// authorizeCategory kategori pribadi hanya untuk pemiliknya, kategori bersama untuk owner/admin workspace
+func (s *CategoryService) authorizeCategory(userID string, category *models.Category) error {
+	if category.WorkspaceID != nil {
+		_, err := s.workspaceService.RequireManager(*category.WorkspaceID, userID)
+		return err
+	}
+	if category.UserID != userID {
+		return errors.New("unauthorized")
+	}
+	return nil
+}
+
 // CreateCategory membuat kategori baru
 func (s *CategoryService) CreateCategory(userID string, data CreateCategoryDTO) (*models.Category, error) {
 	// Validasi
@@ -39,8 +72,18 @@ func (s *CategoryService) CreateCategory(userID string, data CreateCategoryDTO)
 		data.Color = "#6C5CE7" // Default purple
 	}
 
+	// Kategori bersama hanya bisa dibuat owner/admin workspace
+	if data.WorkspaceID != nil && *data.WorkspaceID == "" {
+		data.WorkspaceID = nil
+	}
+	if data.WorkspaceID != nil {
+		if _, err := s.workspaceService.RequireManager(*data.WorkspaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check duplicate name
-	categories, _ := s.categoryRepo.FindByUserID(userID)
+	categories := s.categoriesInScope(userID, data.WorkspaceID)
 	for _, cat := range categories {
 		if cat.Name == data.Name {
 			return nil, errors.New("category name already exists")
@@ -49,10 +92,11 @@ func (s *CategoryService) CreateCategory(userID string, data CreateCategoryDTO)
 
 	// Create category
 	category := &models.Category{
-		UserID:    userID,
-		Name:      data.Name,
-		Color:     data.Color,
-		IsDefault: false,
+		UserID:      userID,
+		WorkspaceID: data.WorkspaceID,
+		Name:        data.Name,
+		Color:       data.Color,
+		IsDefault:   false,
 	}
 
 	if err := s.categoryRepo.Create(category); err != nil {
@@ -74,8 +118,8 @@ func (s *CategoryService) UpdateCategory(userID, categoryID string, data UpdateC
 	}
 
 	// Verify ownership
-	if category.UserID != userID {
-		return nil, errors.New("unauthorized")
+	if err := s.authorizeCategory(userID, category); err != nil {
+		return nil, err
 	}
 
 	// Update fields
@@ -85,7 +129,7 @@ func (s *CategoryService) UpdateCategory(userID, categoryID string, data UpdateC
 		}
 
 		// Check duplicate (kecuali nama yang sama)
-		categories, _ := s.categoryRepo.FindByUserID(userID)
+		categories := s.categoriesInScope(category.UserID, category.WorkspaceID)
 		for _, cat := range categories {
 			if cat.Name == *data.Name && cat.ID != categoryID {
 				return nil, errors.New("category name already exists")
@@ -118,8 +162,8 @@ func (s *CategoryService) DeleteCategory(userID, categoryID string) error {
 	}
 
 	// Verify ownership
-	if category.UserID != userID {
-		return errors.New("unauthorized")
+	if err := s.authorizeCategory(userID, category); err != nil {
+		return err
 	}
 
 	// Cannot delete default categories
@@ -134,8 +178,9 @@ func (s *CategoryService) DeleteCategory(userID, categoryID string) error {
 // DTOs
 
 type CreateCategoryDTO struct {
-	Name  string `json:"name"`
-	Color string `json:"color"`
+	WorkspaceID *string `json:"workspace_id"` // kosong = kategori pribadi
+	Name        string  `json:"name"`
+	Color       string  `json:"color"`
 }
 
 type UpdateCategoryDTO struct {
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule jadwal cron standar 5 field: menit jam tanggal bulan hari-minggu.
+// Mendukung *, a-b, */n, a-b/n, daftar dengan koma, nama bulan/hari (jan, mon),
+// descriptor @hourly/@daily/@weekly/@monthly dan "@every <durasi>".
+type CronSchedule struct {
+	expr   string
+	every  time.Duration // > 0 untuk "@every"
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// Jika tanggal dan hari-minggu sama-sama dibatasi, salah satu cocok sudah cukup (perilaku cron)
+	domRestricted bool
+	dowRestricted bool
+	// Jam dibatasi: slot tidak diulang saat jam mundur (akhir DST)
+	hourRestricted bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{min: 0, max: 59}
+	cronHour   = cronField{min: 0, max: 23}
+	cronDom    = cronField{min: 1, max: 31}
+	cronMonth  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronDow = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCronSchedule mem-parse ekspresi cron
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("invalid cron expression: empty")
+	}
+
+	if strings.HasPrefix(expr, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil || every < time.Minute {
+			return nil, fmt.Errorf("invalid cron expression %q: @every needs a duration of at least 1m", expr)
+		}
+		return &CronSchedule{expr: expr, every: every}, nil
+	}
+
+	spec := expr
+	if descriptor, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields", expr)
+	}
+
+	schedule := &CronSchedule{expr: expr}
+	var err error
+	if schedule.minute, err = cronMinute.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: minute: %w", expr, err)
+	}
+	if schedule.hour, err = cronHour.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: hour: %w", expr, err)
+	}
+	if schedule.dom, err = cronDom.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: day of month: %w", expr, err)
+	}
+	if schedule.month, err = cronMonth.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: month: %w", expr, err)
+	}
+	if schedule.dow, err = cronDow.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: day of week: %w", expr, err)
+	}
+
+	// 7 = Minggu, sama dengan 0
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.domRestricted = fields[2] != "*" && fields[2] != "?"
+	schedule.dowRestricted = fields[4] != "*" && fields[4] != "?"
+	schedule.hourRestricted = fields[1] != "*"
+	return schedule, nil
+}
+
+// String ekspresi asli
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Next waktu eksekusi pertama setelah t (dihitung di zona waktu t); zero time jika tidak ada.
+// Pergantian DST mengikuti cron: slot yang jatuh di jam yang dilompati (awal DST) dijalankan
+// saat jam melompat, dan job dengan jam tertentu tidak diulang di jam yang terulang (akhir DST).
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	if c.every > 0 {
+		return t.Add(c.every).Truncate(time.Second)
+	}
+
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	var prev time.Time
+
+	for t.Before(limit) {
+		if !prev.IsZero() && c.skippedByGap(prev, t) {
+			return t
+		}
+		prev = t
+
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = cronTime(t.Year(), t.Month()+1, 1, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = cronTime(t.Year(), t.Month(), t.Day()+1, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = cronTime(t.Year(), t.Month(), t.Day(), t.Hour()+1, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = c.nextMinute(t)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// nextMinute menit berikutnya; saat jam mundur (akhir DST) job dengan jam tertentu
+// melewati jam yang terulang agar slot-nya tidak berjalan dua kali
+func (c *CronSchedule) nextMinute(t time.Time) time.Time {
+	next := t.Add(time.Minute)
+	if back := wallClock(t).Sub(wallClock(next)); c.hourRestricted && back >= 0 {
+		next = next.Add(back + time.Minute)
+	}
+	return next
+}
+
+// skippedByGap true jika jam dinding melompat maju antara prev dan next (awal DST)
+// dan ada slot yang cocok di rentang jam yang dilompati
+func (c *CronSchedule) skippedByGap(prev, next time.Time) bool {
+	from := wallClock(prev).Add(next.Sub(prev))
+	to := wallClock(next)
+	for wall := from; wall.Before(to); wall = wall.Add(time.Minute) {
+		if c.month&(1<<uint(wall.Month())) != 0 && c.dayMatches(wall) &&
+			c.hour&(1<<uint(wall.Hour())) != 0 && c.minute&(1<<uint(wall.Minute())) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// cronTime awal jam di loc. Jam yang dilompati DST dinormalkan Go ke jam sebelumnya
+// (02:00 yang tidak ada menjadi 01:00), sehingga iterasi bisa berhenti di tempat;
+// di sini hasilnya dimajukan ke saat jam melompat.
+func cronTime(year int, month time.Month, day, hour int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, loc)
+	if want := time.Date(year, month, day, hour, 0, 0, 0, time.UTC); wallClock(t).Before(want) {
+		t = t.Add(want.Sub(wallClock(t)))
+	}
+	return t
+}
+
+// wallClock jam dinding t sebagai waktu UTC, untuk membandingkan jam lokal lintas offset
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domRestricted && c.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// parse satu field menjadi bitmask nilai yang cocok
+func (f cronField) parse(field string) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty list item")
+		}
+
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			rangePart, step = part[:i], n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			lo, hi = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			value, err := f.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = value, value
+			// "5/15" berarti mulai 5 sampai batas atas, tiap 15
+			if strings.Contains(part, "/") {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
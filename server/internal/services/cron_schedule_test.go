@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseCronSchedule ekspresi tidak valid ditolak saat job didaftarkan
+func TestParseCronSchedule(t *testing.T) {
+	testCases := []struct {
+		name  string
+		expr  string
+		valid bool
+	}{
+		{"Every minute", "* * * * *", true},
+		{"Steps, ranges and lists", "*/15 8-17 1,15 * mon-fri", true},
+		{"Range with step", "0 9-17/2 * * *", true},
+		{"Start with step", "5/20 * * * *", true},
+		{"Month and day names", "0 0 1 jan,jul sun", true},
+		{"Sunday as 7", "0 0 * * 7", true},
+		{"Descriptor", "@daily", true},
+		{"Every duration", "@every 90m", true},
+		{"Empty", "", false},
+		{"Four fields", "* * * *", false},
+		{"Minute out of range", "60 * * * *", false},
+		{"Hour out of range", "0 24 * * *", false},
+		{"Day of month zero", "0 0 0 * *", false},
+		{"Reversed range", "5-1 * * * *", false},
+		{"Zero step", "*/0 * * * *", false},
+		{"Empty list item", "1,,2 * * * *", false},
+		{"Unknown name", "0 0 * * funday", false},
+		{"Every below one minute", "@every 30s", false},
+		{"Unknown descriptor", "@fortnightly", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCronSchedule(tc.expr)
+			if (err == nil) != tc.valid {
+				t.Errorf("Input: %q\nExpected valid: %v, Got error: %v", tc.expr, tc.valid, err)
+			}
+		})
+	}
+}
+
+// TestCronScheduleNext slot berikutnya, termasuk kombinasi tanggal/hari-minggu dan pergantian DST
+func TestCronScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+	utc := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatalf("invalid time %q: %v", value, err)
+		}
+		return parsed
+	}
+	// ny waktu New York dengan offset eksplisit, agar jam yang terulang tidak ambigu
+	ny := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04 -0700", value)
+		if err != nil {
+			t.Fatalf("invalid time %q: %v", value, err)
+		}
+		return parsed.In(newYork)
+	}
+
+	testCases := []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"Next quarter hour", "*/15 * * * *", utc("2026-10-19 10:07"), utc("2026-10-19 10:15")},
+		{"Strictly after from", "0 8 * * *", utc("2026-10-19 08:00"), utc("2026-10-20 08:00")},
+		{"Weekday skips weekend", "0 9 * * 1-5", utc("2026-10-16 10:00"), utc("2026-10-19 09:00")},
+		{"Sunday as 7", "0 0 * * 7", utc("2026-10-19 00:00"), utc("2026-10-25 00:00")},
+		{"Day of month only", "0 0 31 * *", utc("2026-04-01 00:00"), utc("2026-05-31 00:00")},
+		{"Leap day", "0 0 29 2 *", utc("2026-03-01 00:00"), utc("2028-02-29 00:00")},
+		{"Impossible date", "0 0 30 2 *", utc("2026-01-01 00:00"), time.Time{}},
+		{"Day of month or weekday: weekday first", "0 0 15 * 5", utc("2026-11-07 00:00"), utc("2026-11-13 00:00")},
+		{"Day of month or weekday: day first", "0 0 15 * 5", utc("2026-11-13 00:00"), utc("2026-11-15 00:00")},
+		{"Weekday within month (and)", "0 0 * 11 5", utc("2026-10-30 00:00"), utc("2026-11-06 00:00")},
+		{"Hourly descriptor", "@hourly", utc("2026-10-19 10:30"), utc("2026-10-19 11:00")},
+		{"Every duration", "@every 90m", utc("2026-10-19 10:30"), utc("2026-10-19 12:00")},
+		{"DST start: skipped slot runs at the jump", "30 2 * * *", ny("2026-03-08 00:00 -0500"), ny("2026-03-08 03:00 -0400")},
+		{"DST start: slot after the gap unchanged", "0 3 * * *", ny("2026-03-08 00:00 -0500"), ny("2026-03-08 03:00 -0400")},
+		{"DST start: interval job resumes after the gap", "*/30 * * * *", ny("2026-03-08 01:45 -0500"), ny("2026-03-08 03:00 -0400")},
+		{"DST start: next day back to normal", "30 2 * * *", ny("2026-03-08 03:00 -0400"), ny("2026-03-09 02:30 -0400")},
+		{"DST end: first occurrence of repeated hour", "30 1 * * *", ny("2026-11-01 00:00 -0400"), ny("2026-11-01 01:30 -0400")},
+		{"DST end: fixed hour runs once", "30 1 * * *", ny("2026-11-01 01:30 -0400"), ny("2026-11-02 01:30 -0500")},
+		{"DST end: interval job keeps running", "*/30 * * * *", ny("2026-11-01 01:30 -0400"), ny("2026-11-01 01:00 -0500")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tc.expr)
+			if err != nil {
+				t.Fatalf("Input: %q\nUnexpected error: %v", tc.expr, err)
+			}
+			got := schedule.Next(tc.from)
+			if !got.Equal(tc.expected) {
+				t.Errorf("Input: %q from %s\nExpected: %s, Got: %s", tc.expr, tc.from, tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// dailyBriefingListLimit jumlah judul task yang ditampilkan per bagian
+const dailyBriefingListLimit = 3
+
+// DailyBriefingService menyusun agenda pagi dan ringkasan akhir hari untuk satu user
+type DailyBriefingService struct {
+	taskRepo       *repository.TaskRepository
+	holidayService *HolidayService
+	leaveService   *LeaveService
+}
+
+func NewDailyBriefingService(
+	taskRepo *repository.TaskRepository,
+	holidayService *HolidayService,
+	leaveService *LeaveService,
+) *DailyBriefingService {
+	return &DailyBriefingService{
+		taskRepo:       taskRepo,
+		holidayService: holidayService,
+		leaveService:   leaveService,
+	}
+}
+
+// BuildAgenda rencana hari ini: task jatuh tempo hari ini, task terlambat, libur dan cuti.
+// day = 00:00 tanggal lokal user (lokasi = zona waktu user).
+func (s *DailyBriefingService) BuildAgenda(userID string, day time.Time) (*DailyBriefing, error) {
+	dayEnd := day.AddDate(0, 0, 1).Add(-time.Second)
+
+	dueToday, err := s.openTasksInRange(userID, day, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	overdue, err := s.taskRepo.FindOverdueWorkload(userID, day)
+	if err != nil {
+		return nil, err
+	}
+	holidays := s.holidayNames(userID, day, dayEnd)
+	onLeave := s.onLeave(userID, day)
+
+	var lines []string
+	if onLeave {
+		lines = append(lines, "🌴 Anda sedang cuti hari ini.")
+	}
+	if len(holidays) > 0 {
+		lines = append(lines, fmt.Sprintf("🎉 Libur: %s", strings.Join(holidays, ", ")))
+	}
+	if len(dueToday) > 0 {
+		lines = append(lines, fmt.Sprintf("📋 %d tugas hari ini: %s", len(dueToday), briefingTaskList(dueToday, day.Location(), true)))
+	}
+	if len(overdue) > 0 {
+		lines = append(lines, fmt.Sprintf("⚠️ %d tugas terlambat: %s", len(overdue), briefingTaskList(overdue, day.Location(), false)))
+	}
+
+	return &DailyBriefing{
+		Kind:  models.DailyBriefingAgenda,
+		Date:  day.Format("2006-01-02"),
+		Title: "☀️ Rencana Hari Ini",
+		Body:  strings.Join(lines, "\n"),
+		Data: map[string]string{
+			"date":      day.Format("2006-01-02"),
+			"due_today": fmt.Sprintf("%d", len(dueToday)),
+			"overdue":   fmt.Sprintf("%d", len(overdue)),
+			"holidays":  fmt.Sprintf("%d", len(holidays)),
+			"on_leave":  fmt.Sprintf("%t", onLeave),
+		},
+		Empty: len(lines) == 0,
+	}, nil
+}
+
+// BuildDigest ringkasan akhir hari: task selesai, task yang terlewat deadline hari ini,
+// dan gambaran besok. now = waktu pengiriman (batas "terlewat").
+func (s *DailyBriefingService) BuildDigest(userID string, day, now time.Time) (*DailyBriefing, error) {
+	tomorrow := day.AddDate(0, 0, 1)
+	tomorrowEnd := tomorrow.AddDate(0, 0, 1).Add(-time.Second)
+
+	completed, err := s.taskRepo.FindCompletedWorkload(userID, day, now)
+	if err != nil {
+		return nil, err
+	}
+	slipped, err := s.openTasksInRange(userID, day, now)
+	if err != nil {
+		return nil, err
+	}
+	dueTomorrow, err := s.openTasksInRange(userID, tomorrow, tomorrowEnd)
+	if err != nil {
+		return nil, err
+	}
+	holidaysTomorrow := s.holidayNames(userID, tomorrow, tomorrowEnd)
+	leaveTomorrow := s.onLeave(userID, tomorrow)
+
+	var lines []string
+	if len(completed) > 0 {
+		lines = append(lines, fmt.Sprintf("✅ %d tugas selesai hari ini. Kerja bagus!", len(completed)))
+	}
+	if len(slipped) > 0 {
+		lines = append(lines, fmt.Sprintf("⏳ %d tugas terlewat: %s", len(slipped), briefingTaskList(slipped, day.Location(), false)))
+	}
+
+	var tomorrowParts []string
+	if leaveTomorrow {
+		tomorrowParts = append(tomorrowParts, "Anda cuti")
+	}
+	if len(holidaysTomorrow) > 0 {
+		tomorrowParts = append(tomorrowParts, fmt.Sprintf("libur %s", strings.Join(holidaysTomorrow, ", ")))
+	}
+	if len(dueTomorrow) > 0 {
+		tomorrowParts = append(tomorrowParts, fmt.Sprintf("%d tugas (%s)", len(dueTomorrow), briefingTaskList(dueTomorrow, day.Location(), true)))
+	}
+	if len(tomorrowParts) > 0 {
+		lines = append(lines, "📅 Besok: "+strings.Join(tomorrowParts, "; "))
+	}
+
+	return &DailyBriefing{
+		Kind:  models.DailyBriefingDigest,
+		Date:  day.Format("2006-01-02"),
+		Title: "🌙 Ringkasan Hari Ini",
+		Body:  strings.Join(lines, "\n"),
+		Data: map[string]string{
+			"date":         day.Format("2006-01-02"),
+			"completed":    fmt.Sprintf("%d", len(completed)),
+			"slipped":      fmt.Sprintf("%d", len(slipped)),
+			"due_tomorrow": fmt.Sprintf("%d", len(dueTomorrow)),
+		},
+		Empty: len(lines) == 0,
+	}, nil
+}
+
+// openTasksInRange task beban kerja user yang belum selesai dengan deadline dalam [start, end]
+func (s *DailyBriefingService) openTasksInRange(userID string, start, end time.Time) ([]models.Task, error) {
+	tasks, err := s.taskRepo.FindWorkloadByUserIDAndDateRange(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	open := make([]models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if !task.IsCompleted {
+			open = append(open, task)
+		}
+	}
+	return open, nil
+}
+
+func (s *DailyBriefingService) holidayNames(userID string, start, end time.Time) []string {
+	if s.holidayService == nil {
+		return nil
+	}
+	holidays, err := s.holidayService.GetHolidaysByDateRange(userID, start, end)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(holidays))
+	for _, h := range holidays {
+		names = append(names, h.Name)
+	}
+	return names
+}
+
+func (s *DailyBriefingService) onLeave(userID string, day time.Time) bool {
+	if s.leaveService == nil {
+		return false
+	}
+	onLeave, err := s.leaveService.IsOnLeave(userID, day)
+	return err == nil && onLeave
+}
+
+// briefingTaskList "A (09:00), B (14:00) dan 2 lainnya"
+func briefingTaskList(tasks []models.Task, loc *time.Location, withTime bool) string {
+	items := make([]string, 0, dailyBriefingListLimit)
+	for i, task := range tasks {
+		if i == dailyBriefingListLimit {
+			break
+		}
+		item := task.Title
+		if withTime && task.Deadline != nil {
+			item = fmt.Sprintf("%s (%s)", task.Title, task.Deadline.In(loc).Format("15:04"))
+		}
+		items = append(items, item)
+	}
+
+	list := strings.Join(items, ", ")
+	if len(tasks) > dailyBriefingListLimit {
+		list = fmt.Sprintf("%s dan %d lainnya", list, len(tasks)-dailyBriefingListLimit)
+	}
+	return list
+}
+
+// DTOs
+
+// DailyBriefing isi agenda / digest yang siap dikirim
+type DailyBriefing struct {
+	Kind  models.DailyBriefingKind
+	Date  string // YYYY-MM-DD di zona waktu user
+	Title string
+	Body  string
+	Data  map[string]string
+	Empty bool // tidak ada yang perlu dilaporkan
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"time"
 
 	"github.com/resend/resend-go/v2"
 	"github.com/workradar/server/internal/config"
@@ -423,13 +424,182 @@ func (s *EmailService) SendVIPUpgradeEmail(toEmail, userName, plan string) error
 	return s.sendViaResend(toEmail, subject, body.String())
 }
 
+// SendProductivityReport sends a rendered productivity report with the PDF attached
+func (s *EmailService) SendProductivityReport(toEmail, subject, htmlBody, pdfFilename string, pdf []byte) error {
+	if !s.IsConfigured() {
+		log.Println("⚠️ Resend API not configured, skipping productivity report email")
+		return nil
+	}
+
+	var attachments []*resend.Attachment
+	if len(pdf) > 0 {
+		attachments = append(attachments, &resend.Attachment{
+			Content:  pdf,
+			Filename: pdfFilename,
+		})
+	}
+
+	return s.sendViaResendWithAttachments(toEmail, subject, htmlBody, attachments)
+}
+
+// SendWorkspaceInvitation sends a workspace invitation email
+func (s *EmailService) SendWorkspaceInvitation(toEmail, inviterName, workspaceName string, role string, expiresAt time.Time) error {
+	if !s.IsConfigured() {
+		log.Println("⚠️ Resend API not configured, skipping workspace invitation email")
+		return nil
+	}
+
+	subject := fmt.Sprintf("Undangan bergabung ke workspace %s - Workradar", workspaceName)
+
+	htmlTemplate := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; background-color: #f5f5f5;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="background-color: #f5f5f5; padding: 40px 0;">
+        <tr>
+            <td align="center">
+                <table width="600" cellpadding="0" cellspacing="0" style="background-color: #ffffff; border-radius: 16px; box-shadow: 0 4px 20px rgba(0,0,0,0.1);">
+                    <tr>
+                        <td style="background: linear-gradient(135deg, #6366F1 0%, #8B5CF6 100%); padding: 40px; border-radius: 16px 16px 0 0; text-align: center;">
+                            <h1 style="color: #ffffff; margin: 0; font-size: 28px;">👥 Undangan Workspace</h1>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding: 40px;">
+                            <p style="color: #6b7280; line-height: 1.6;">
+                                <strong>{{.InviterName}}</strong> mengundang Anda bergabung ke workspace
+                                <strong>{{.WorkspaceName}}</strong> sebagai <strong>{{.Role}}</strong>.
+                            </p>
+                            <p style="color: #6b7280; line-height: 1.6;">
+                                Buka aplikasi Workradar dengan email ini, lalu terima undangan di menu Workspace.
+                                Anda akan dapat melihat dan mengerjakan task bersama tim.
+                            </p>
+                            <p style="color: #9ca3af; font-size: 14px; margin-top: 30px;">
+                                Undangan berlaku sampai {{.ExpiresAt}}. Abaikan email ini jika Anda tidak mengenal pengirimnya.
+                            </p>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="background-color: #f9fafb; padding: 30px; border-radius: 0 0 16px 16px; text-align: center;">
+                            <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                                © 2026 Workradar. All rights reserved.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`
+
+	tmpl, err := template.New("workspace_invitation").Parse(htmlTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse workspace invitation template: %w", err)
+	}
+
+	var body bytes.Buffer
+	data := struct {
+		InviterName   string
+		WorkspaceName string
+		Role          string
+		ExpiresAt     string
+	}{
+		InviterName:   inviterName,
+		WorkspaceName: workspaceName,
+		Role:          role,
+		ExpiresAt:     expiresAt.Format("02 Jan 2006"),
+	}
+	if err := tmpl.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute workspace invitation template: %w", err)
+	}
+
+	return s.sendViaResend(toEmail, subject, body.String())
+}
+
+// SendNotificationEmail sends a generic notification (email channel of notification preferences)
+func (s *EmailService) SendNotificationEmail(toEmail, title, message string) error {
+	if !s.IsConfigured() {
+		log.Println("⚠️ Resend API not configured, skipping notification email")
+		return nil
+	}
+
+	htmlTemplate := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; background-color: #f5f5f5;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="background-color: #f5f5f5; padding: 40px 0;">
+        <tr>
+            <td align="center">
+                <table width="600" cellpadding="0" cellspacing="0" style="background-color: #ffffff; border-radius: 16px; box-shadow: 0 4px 20px rgba(0,0,0,0.1);">
+                    <tr>
+                        <td style="background: linear-gradient(135deg, #6366F1 0%, #8B5CF6 100%); padding: 40px; border-radius: 16px 16px 0 0; text-align: center;">
+                            <h1 style="color: #ffffff; margin: 0; font-size: 24px;">{{.Title}}</h1>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding: 40px;">
+                            <p style="color: #6b7280; line-height: 1.6; white-space: pre-line;">{{.Message}}</p>
+                            <p style="color: #9ca3af; font-size: 14px; margin-top: 30px;">
+                                Atur jenis dan channel notifikasi di menu Pengaturan Notifikasi aplikasi Workradar.
+                            </p>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="background-color: #f9fafb; padding: 30px; border-radius: 0 0 16px 16px; text-align: center;">
+                            <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                                © 2026 Workradar. All rights reserved.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`
+
+	tmpl, err := template.New("notification").Parse(htmlTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse notification template: %w", err)
+	}
+
+	var body bytes.Buffer
+	data := struct {
+		Title   string
+		Message string
+	}{
+		Title:   title,
+		Message: message,
+	}
+	if err := tmpl.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute notification template: %w", err)
+	}
+
+	return s.sendViaResend(toEmail, title+" - Workradar", body.String())
+}
+
 // sendViaResend sends an HTML email using Resend API
 func (s *EmailService) sendViaResend(to, subject, htmlBody string) error {
+	return s.sendViaResendWithAttachments(to, subject, htmlBody, nil)
+}
+
+// sendViaResendWithAttachments sends an HTML email with optional attachments
+func (s *EmailService) sendViaResendWithAttachments(to, subject, htmlBody string, attachments []*resend.Attachment) error {
 	req := &resend.SendEmailRequest{
-		From:    fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail),
-		To:      []string{to},
-		Subject: subject,
-		Html:    htmlBody,
+		From:        fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail),
+		To:          []string{to},
+		Subject:     subject,
+		Html:        htmlBody,
+		Attachments: attachments,
 	}
 
 	sent, err := s.resendClient.Emails.Send(req)
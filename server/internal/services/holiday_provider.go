@@ -0,0 +1,333 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+)
+
+// HolidayProvider sumber data hari libur (file bawaan, ICS, dll)
+type HolidayProvider interface {
+	// Name identitas provider, disimpan di kolom source
+	Name() string
+	// Fetch mengambil holiday publik untuk satu tahun
+	Fetch(year int) ([]models.Holiday, error)
+}
+
+//go:embed holidaydata/*.json
+var bundledHolidayFiles embed.FS
+
+// HolidayRegions daftar region yang dikenal (ISO 3166-2)
+var HolidayRegions = map[string]string{
+	"ID-AC": "Aceh",
+	"ID-BA": "Bali",
+	"ID-JK": "DKI Jakarta",
+	"ID-JB": "Jawa Barat",
+	"ID-JT": "Jawa Tengah",
+	"ID-JI": "Jawa Timur",
+	"ID-YO": "DI Yogyakarta",
+	"ID-PA": "Papua",
+	"ID-PB": "Papua Barat",
+	"ID-SU": "Sumatera Utara",
+	"ID-SN": "Sulawesi Selatan",
+	"ID-KT": "Kalimantan Timur",
+}
+
+// holidayDataFile format file JSON di holidaydata/
+type holidayDataFile struct {
+	Country  string `json:"country"`
+	Year     int    `json:"year"`
+	Source   string `json:"source"`
+	Holidays []struct {
+		Date        string             `json:"date"`
+		Name        string             `json:"name"`
+		Type        models.HolidayType `json:"type"`
+		Region      string             `json:"region"`
+		Description string             `json:"description"`
+	} `json:"holidays"`
+}
+
+// ==================== BUNDLED PROVIDER ====================
+
+// BundledHolidayProvider membaca dataset holiday yang di-embed di binary
+type BundledHolidayProvider struct {
+	country string
+}
+
+func NewBundledHolidayProvider(country string) *BundledHolidayProvider {
+	return &BundledHolidayProvider{country: country}
+}
+
+func (p *BundledHolidayProvider) Name() string {
+	return "bundled"
+}
+
+// Years tahun-tahun yang tersedia di dataset bawaan
+func (p *BundledHolidayProvider) Years() []int {
+	entries, err := bundledHolidayFiles.ReadDir("holidaydata")
+	if err != nil {
+		return nil
+	}
+
+	var years []int
+	for _, entry := range entries {
+		var year int
+		if _, err := fmt.Sscanf(entry.Name(), p.country+"-%d.json", &year); err == nil {
+			years = append(years, year)
+		}
+	}
+	return years
+}
+
+func (p *BundledHolidayProvider) Fetch(year int) ([]models.Holiday, error) {
+	data, err := bundledHolidayFiles.ReadFile(fmt.Sprintf("holidaydata/%s-%d.json", p.country, year))
+	if err != nil {
+		return nil, fmt.Errorf("no bundled holiday data for %s %d", p.country, year)
+	}
+
+	var file holidayDataFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid bundled holiday data: %w", err)
+	}
+
+	holidays := make([]models.Holiday, 0, len(file.Holidays))
+	for _, item := range file.Holidays {
+		date, err := time.ParseInLocation("2006-01-02", item.Date, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q in bundled holiday data", item.Date)
+		}
+
+		holiday := models.Holiday{
+			Name:   item.Name,
+			Date:   date,
+			Type:   item.Type,
+			Source: p.Name(),
+		}
+		if item.Region != "" {
+			region := item.Region
+			holiday.Region = &region
+		}
+		if item.Description != "" {
+			description := item.Description
+			holiday.Description = &description
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, nil
+}
+
+// ==================== ICS PROVIDER ====================
+
+// ICSHolidayProvider mengambil holiday dari feed iCalendar (mis. Google Calendar publik)
+type ICSHolidayProvider struct {
+	url         string
+	region      *string
+	holidayType models.HolidayType
+	client      *http.Client
+}
+
+// NewICSHolidayProvider membuat provider ICS; region kosong berarti berlaku nasional
+func NewICSHolidayProvider(feedURL, region string, holidayType models.HolidayType) *ICSHolidayProvider {
+	if holidayType == "" {
+		holidayType = models.HolidayTypeNational
+	}
+
+	// URL feed bisa diisi admin: hanya alamat publik, redirect hanya ke host yang sama
+	client := newOutboundHTTPClient(30 * time.Second)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 5 {
+			return errors.New("too many redirects")
+		}
+		if req.URL.Scheme != "https" || req.URL.Host != via[0].URL.Host {
+			return fmt.Errorf("redirect to %s is not allowed", req.URL.Host)
+		}
+		return nil
+	}
+
+	p := &ICSHolidayProvider{
+		url:         feedURL,
+		holidayType: holidayType,
+		client:      client,
+	}
+	if region != "" {
+		p.region = &region
+	}
+	return p
+}
+
+// Name "ics:<host>/<hash>": hash URL feed + region, agar feed lain di host yang sama
+// (mis. kalender nasional dan daerah Google) tidak saling menghapus data
+func (p *ICSHolidayProvider) Name() string {
+	region := ""
+	if p.region != nil {
+		region = *p.region
+	}
+	sum := sha256.Sum256([]byte(p.url + "|" + region))
+	return p.hostSource() + "/" + hex.EncodeToString(sum[:4])
+}
+
+// hostSource source lama "ics:<host>", dipakai sebelum source dibedakan per feed
+func (p *ICSHolidayProvider) hostSource() string {
+	if u, err := url.Parse(p.url); err == nil && u.Host != "" {
+		return "ics:" + u.Host
+	}
+	return "ics"
+}
+
+func (p *ICSHolidayProvider) Fetch(year int) ([]models.Holiday, error) {
+	if u, err := url.Parse(p.url); err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, errors.New("ICS feed must be an https URL")
+	}
+
+	resp, err := p.client.Get(p.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ICS feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ICS feed returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := ParseICSEvents(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []models.Holiday
+	for _, event := range events {
+		holidayType := p.holidayType
+		if strings.Contains(strings.ToLower(event.Summary), "cuti bersama") {
+			holidayType = models.HolidayTypeCollectiveLeave
+		}
+
+		// Event all-day multi hari di-expand per tanggal (DTEND eksklusif)
+		for day := event.Start; day.Before(event.End); day = day.AddDate(0, 0, 1) {
+			if day.Year() != year {
+				continue
+			}
+			holiday := models.Holiday{
+				Name:   event.Summary,
+				Date:   day,
+				Type:   holidayType,
+				Region: p.region,
+				Source: p.Name(),
+			}
+			if event.Description != "" {
+				description := event.Description
+				holiday.Description = &description
+			}
+			holidays = append(holidays, holiday)
+		}
+	}
+
+	return holidays, nil
+}
+
+// ICSEvent event hasil parsing VEVENT
+type ICSEvent struct {
+	Summary     string
+	Description string
+	Start       time.Time // tanggal lokal, jam 00:00
+	End         time.Time // eksklusif
+}
+
+// ParseICSEvents parsing VEVENT dari data iCalendar (RFC 5545), hanya tanggal yang dipakai
+func ParseICSEvents(data []byte) ([]ICSEvent, error) {
+	// Unfold: baris yang diawali spasi/tab adalah lanjutan baris sebelumnya
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var events []ICSEvent
+	var current *ICSEvent
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			current = &ICSEvent{}
+			continue
+		case line == "END:VEVENT":
+			if current != nil && !current.Start.IsZero() && current.Summary != "" {
+				if current.End.IsZero() || !current.End.After(current.Start) {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+				events = append(events, *current)
+			}
+			current = nil
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Buang parameter, mis. DTSTART;VALUE=DATE
+		name, _, _ = strings.Cut(name, ";")
+
+		switch strings.ToUpper(name) {
+		case "SUMMARY":
+			current.Summary = unescapeICSText(value)
+		case "DESCRIPTION":
+			current.Description = unescapeICSText(value)
+		case "DTSTART":
+			current.Start = parseICSDate(value)
+		case "DTEND":
+			current.End = parseICSDate(value)
+		}
+	}
+
+	if len(events) == 0 && !bytes.Contains(data, []byte("BEGIN:VCALENDAR")) {
+		return nil, errors.New("invalid ICS data")
+	}
+
+	return events, nil
+}
+
+// parseICSDate mengambil bagian tanggal (YYYYMMDD) dari DATE atau DATE-TIME
+func parseICSDate(value string) time.Time {
+	if len(value) < 8 {
+		return time.Time{}
+	}
+	date, err := time.ParseInLocation("20060102", value[:8], time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+func unescapeICSText(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return strings.TrimSpace(replacer.Replace(value))
+}
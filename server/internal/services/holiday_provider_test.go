@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/workradar/server/internal/models"
+)
+
+// icsCalendar membungkus baris VEVENT menjadi data iCalendar dengan akhir baris CRLF
+func icsCalendar(lines ...string) []byte {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...)
+	all = append(all, "END:VCALENDAR")
+	return []byte(strings.Join(all, "\r\n") + "\r\n")
+}
+
+// TestParseICSEvents tanggal, unfolding dan escaping mengikuti RFC 5545
+func TestParseICSEvents(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		expected []string // "summary|start|end"
+		wantErr  bool
+	}{
+		{
+			name:     "All-day event",
+			data:     icsCalendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20261225", "DTEND;VALUE=DATE:20261226", "SUMMARY:Hari Raya Natal", "END:VEVENT"),
+			expected: []string{"Hari Raya Natal|2026-12-25|2026-12-26"},
+		},
+		{
+			name:     "Multi-day event keeps exclusive end",
+			data:     icsCalendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260320", "DTEND;VALUE=DATE:20260323", "SUMMARY:Cuti Bersama Idul Fitri", "END:VEVENT"),
+			expected: []string{"Cuti Bersama Idul Fitri|2026-03-20|2026-03-23"},
+		},
+		{
+			name:     "Missing end is one day",
+			data:     icsCalendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260817", "SUMMARY:Hari Kemerdekaan", "END:VEVENT"),
+			expected: []string{"Hari Kemerdekaan|2026-08-17|2026-08-18"},
+		},
+		{
+			name:     "Date-time value uses the date",
+			data:     icsCalendar("BEGIN:VEVENT", "DTSTART:20260501T000000Z", "DTEND:20260502T000000Z", "SUMMARY:Hari Buruh", "END:VEVENT"),
+			expected: []string{"Hari Buruh|2026-05-01|2026-05-02"},
+		},
+		{
+			name:     "Folded and escaped summary",
+			data:     icsCalendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260101", "SUMMARY:Tahun Baru\\, Masehi", "  dan Libur", "END:VEVENT"),
+			expected: []string{"Tahun Baru, Masehi dan Libur|2026-01-01|2026-01-02"},
+		},
+		{
+			name: "Events without summary or date are skipped",
+			data: icsCalendar(
+				"BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260101", "END:VEVENT",
+				"BEGIN:VEVENT", "SUMMARY:Tanpa tanggal", "END:VEVENT",
+				"BEGIN:VEVENT", "DTSTART;VALUE=DATE:20260214", "SUMMARY:Valentine", "END:VEVENT",
+			),
+			expected: []string{"Valentine|2026-02-14|2026-02-15"},
+		},
+		{
+			name:     "Empty calendar",
+			data:     icsCalendar(),
+			expected: nil,
+		},
+		{
+			name:    "Not a calendar",
+			data:    []byte("<html>Not found</html>"),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := ParseICSEvents(tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Expected error: %v, Got: %v", tc.wantErr, err)
+			}
+
+			var got []string
+			for _, event := range events {
+				got = append(got, event.Summary+"|"+event.Start.Format("2006-01-02")+"|"+event.End.Format("2006-01-02"))
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected events: %v, Got: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestICSHolidayProviderEgress feed ICS hanya lewat https ke alamat publik
+func TestICSHolidayProviderEgress(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(icsCalendar())
+	}))
+	defer server.Close()
+
+	if _, err := NewICSHolidayProvider(strings.Replace(server.URL, "https://", "http://", 1), "", models.HolidayTypeNational).Fetch(2026); err == nil {
+		t.Errorf("Expected plain http feed to be rejected")
+	}
+	if _, err := NewICSHolidayProvider(server.URL, "", models.HolidayTypeNational).Fetch(2026); !errors.Is(err, errEgressBlocked) {
+		t.Errorf("Expected feed on internal address to be blocked, Got: %v", err)
+	}
+}
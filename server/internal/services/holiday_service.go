@@ -1,9 +1,15 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/workradar/server/internal/config"
 	"github.com/workradar/server/internal/models"
 	"github.com/workradar/server/internal/repository"
 	"gorm.io/gorm"
@@ -11,17 +17,33 @@ import (
 
 type HolidayService struct {
 	holidayRepo *repository.HolidayRepository
+	userRepo    *repository.UserRepository
+	bundled     *BundledHolidayProvider
 }
 
-func NewHolidayService(holidayRepo *repository.HolidayRepository) *HolidayService {
+func NewHolidayService(holidayRepo *repository.HolidayRepository, userRepo *repository.UserRepository) *HolidayService {
 	return &HolidayService{
 		holidayRepo: holidayRepo,
+		userRepo:    userRepo,
+		bundled:     NewBundledHolidayProvider("ID"),
 	}
 }
 
+// userRegion region libur daerah yang dipilih user ("" jika belum memilih)
+func (s *HolidayService) userRegion(userID string) string {
+	if s.userRepo == nil {
+		return ""
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.HolidayRegion == nil {
+		return ""
+	}
+	return *user.HolidayRegion
+}
+
 // GetAllHolidays mendapatkan semua holidays (national + user's personal)
 func (s *HolidayService) GetAllHolidays(userID string) ([]models.HolidayResponse, error) {
-	holidays, err := s.holidayRepo.FindAll(&userID)
+	holidays, err := s.holidayRepo.FindAll(&userID, s.userRegion(userID))
 	if err != nil {
 		return nil, err
 	}
@@ -34,9 +56,10 @@ func (s *HolidayService) GetAllHolidays(userID string) ([]models.HolidayResponse
 	return responses, nil
 }
 
-// GetHolidaysByDateRange mendapatkan holidays dalam rentang tanggal
+// GetHolidaysByDateRange mendapatkan holidays dalam rentang tanggal,
+// personal holiday recurring di-expand ke setiap tahun dalam rentang
 func (s *HolidayService) GetHolidaysByDateRange(userID string, startDate, endDate time.Time) ([]models.HolidayResponse, error) {
-	holidays, err := s.holidayRepo.FindByDateRange(&userID, startDate, endDate)
+	holidays, err := s.holidayRepo.FindByDateRange(&userID, s.userRegion(userID), startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -46,17 +69,41 @@ func (s *HolidayService) GetHolidaysByDateRange(userID string, startDate, endDat
 		responses[i] = holiday.ToResponse()
 	}
 
+	recurring, err := s.holidayRepo.FindRecurringByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recurring) > 0 {
+		for i := range recurring {
+			for _, occurrence := range recurringOccurrences(&recurring[i], startDate, endDate) {
+				responses = append(responses, recurring[i].ToOccurrenceResponse(occurrence))
+			}
+		}
+		sort.SliceStable(responses, func(i, j int) bool {
+			return responses[i].Date.Before(responses[j].Date)
+		})
+	}
+
 	return responses, nil
 }
 
 // CreatePersonalHoliday membuat personal holiday baru
-func (s *HolidayService) CreatePersonalHoliday(userID, name string, date time.Time, description *string) (*models.HolidayResponse, error) {
+func (s *HolidayService) CreatePersonalHoliday(userID, name string, date time.Time, description *string, isRecurring bool, reminderDaysBefore *int) (*models.HolidayResponse, error) {
+	if err := validateReminderDays(reminderDaysBefore); err != nil {
+		return nil, err
+	}
+
 	holiday := &models.Holiday{
-		UserID:      &userID,
-		Name:        name,
-		Date:        date,
-		IsNational:  false,
-		Description: description,
+		UserID:             &userID,
+		Name:               name,
+		Date:               date,
+		IsNational:         false,
+		Type:               models.HolidayTypePersonal,
+		Source:             "manual",
+		Description:        description,
+		IsRecurring:        isRecurring,
+		ReminderDaysBefore: reminderDaysBefore,
 	}
 
 	if err := s.holidayRepo.Create(holiday); err != nil {
@@ -67,6 +114,58 @@ func (s *HolidayService) CreatePersonalHoliday(userID, name string, date time.Ti
 	return &response, nil
 }
 
+// UpdatePersonalHoliday memperbarui personal holiday (termasuk recurrence & reminder)
+func (s *HolidayService) UpdatePersonalHoliday(holidayID, userID string, dto UpdatePersonalHolidayDTO) (*models.HolidayResponse, error) {
+	holiday, err := s.holidayRepo.FindByID(holidayID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("holiday not found")
+		}
+		return nil, err
+	}
+
+	if holiday.UserID == nil || *holiday.UserID != userID {
+		return nil, errors.New("unauthorized to update this holiday")
+	}
+
+	if dto.Name != nil {
+		if *dto.Name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		holiday.Name = *dto.Name
+	}
+	if dto.Date != nil {
+		date, err := time.Parse("2006-01-02", *dto.Date)
+		if err != nil {
+			return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+		}
+		holiday.Date = date
+		holiday.LastRemindedFor = nil
+	}
+	if dto.Description != nil {
+		holiday.Description = dto.Description
+	}
+	if dto.IsRecurring != nil {
+		holiday.IsRecurring = *dto.IsRecurring
+	}
+	if dto.ClearReminder {
+		holiday.ReminderDaysBefore = nil
+	} else if dto.ReminderDaysBefore != nil {
+		if err := validateReminderDays(dto.ReminderDaysBefore); err != nil {
+			return nil, err
+		}
+		holiday.ReminderDaysBefore = dto.ReminderDaysBefore
+		holiday.LastRemindedFor = nil
+	}
+
+	if err := s.holidayRepo.Update(holiday); err != nil {
+		return nil, err
+	}
+
+	response := holiday.ToResponse()
+	return &response, nil
+}
+
 // DeletePersonalHoliday menghapus personal holiday
 func (s *HolidayService) DeletePersonalHoliday(holidayID, userID string) error {
 	// Verify holiday exists and belongs to user
@@ -93,5 +192,419 @@ func (s *HolidayService) DeletePersonalHoliday(holidayID, userID string) error {
 
 // IsHolidayOnDate mengecek apakah tanggal tertentu adalah holiday
 func (s *HolidayService) IsHolidayOnDate(userID string, date time.Time) (bool, error) {
-	return s.holidayRepo.IsHolidayOnDate(&userID, date)
+	isHoliday, err := s.holidayRepo.IsHolidayOnDate(&userID, s.userRegion(userID), date)
+	if err != nil || isHoliday {
+		return isHoliday, err
+	}
+
+	recurring, err := s.holidayRepo.FindRecurringByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	for i := range recurring {
+		if len(recurringOccurrences(&recurring[i], day, day)) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ==================== RECURRING & REMINDER ====================
+
+// recurringOccurrences occurrence holiday recurring dalam rentang [start, end] (per tanggal).
+// Occurrence sebelum tanggal asli tidak dihitung.
+func recurringOccurrences(holiday *models.Holiday, start, end time.Time) []time.Time {
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+
+	var occurrences []time.Time
+	for year := start.Year(); year <= end.Year(); year++ {
+		if year < holiday.Date.Year() {
+			continue
+		}
+		occurrence := holiday.OccurrenceIn(year)
+		day := time.Date(occurrence.Year(), occurrence.Month(), occurrence.Day(), 0, 0, 0, 0, time.UTC)
+		if day.Before(startDay) || day.After(endDay) {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
+
+// nextOccurrence occurrence berikutnya pada/atau setelah tanggal `from`
+func nextOccurrence(holiday *models.Holiday, from time.Time) (time.Time, bool) {
+	if !holiday.IsRecurring {
+		return holiday.Date, true
+	}
+	for year := from.Year(); year <= from.Year()+1; year++ {
+		if occurrences := recurringOccurrences(holiday, from, time.Date(year, 12, 31, 0, 0, 0, 0, from.Location())); len(occurrences) > 0 {
+			return occurrences[0], true
+		}
+	}
+	return time.Time{}, false
+}
+
+// DueHolidayReminders personal holiday yang reminder-nya jatuh pada `today` dan belum dikirim
+func (s *HolidayService) DueHolidayReminders(today time.Time) ([]HolidayReminder, error) {
+	holidays, err := s.holidayRepo.FindWithReminder()
+	if err != nil {
+		return nil, err
+	}
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	var due []HolidayReminder
+	for i := range holidays {
+		holiday := holidays[i]
+		daysBefore := *holiday.ReminderDaysBefore
+
+		occurrence, ok := nextOccurrence(&holiday, today)
+		if !ok {
+			continue
+		}
+		occurrenceDay := time.Date(occurrence.Year(), occurrence.Month(), occurrence.Day(), 0, 0, 0, 0, time.UTC)
+		if !occurrenceDay.AddDate(0, 0, -daysBefore).Equal(today) {
+			continue
+		}
+
+		if holiday.LastRemindedFor != nil && holiday.LastRemindedFor.Format("2006-01-02") == occurrence.Format("2006-01-02") {
+			continue
+		}
+
+		due = append(due, HolidayReminder{
+			Holiday:    holiday,
+			Occurrence: occurrence,
+			DaysBefore: daysBefore,
+			YearsSince: occurrence.Year() - holiday.Date.Year(),
+		})
+	}
+
+	return due, nil
+}
+
+// MarkReminderSent menandai reminder occurrence sudah dikirim
+func (s *HolidayService) MarkReminderSent(reminder HolidayReminder) error {
+	return s.holidayRepo.MarkReminded(reminder.Holiday.ID, reminder.Occurrence)
+}
+
+func validateReminderDays(days *int) error {
+	if days != nil && (*days < 0 || *days > 60) {
+		return errors.New("reminder_days_before must be between 0 and 60")
+	}
+	return nil
+}
+
+// ==================== REGION ====================
+
+// GetRegions mendapatkan daftar region yang tersedia + region user saat ini
+func (s *HolidayService) GetRegions(userID string) (*HolidayRegionsResponse, error) {
+	codes := map[string]bool{}
+	for code := range HolidayRegions {
+		codes[code] = true
+	}
+	if fromDB, err := s.holidayRepo.ListPublicRegions(); err == nil {
+		for _, code := range fromDB {
+			codes[code] = true
+		}
+	}
+
+	response := &HolidayRegionsResponse{Regions: []HolidayRegion{}}
+	for code := range codes {
+		name := HolidayRegions[code]
+		if name == "" {
+			name = code
+		}
+		response.Regions = append(response.Regions, HolidayRegion{Code: code, Name: name})
+	}
+	sort.Slice(response.Regions, func(i, j int) bool {
+		return response.Regions[i].Name < response.Regions[j].Name
+	})
+
+	if region := s.userRegion(userID); region != "" {
+		response.Current = &region
+	}
+
+	return response, nil
+}
+
+// UpdateUserRegion memilih region libur daerah user ("" untuk hanya libur nasional)
+func (s *HolidayService) UpdateUserRegion(userID, region string) error {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region == "" {
+		return s.userRepo.UpdateHolidayRegion(userID, nil)
+	}
+
+	if _, ok := HolidayRegions[region]; !ok {
+		known, err := s.holidayRepo.ListPublicRegions()
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, code := range known {
+			if code == region {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("unknown holiday region")
+		}
+	}
+
+	return s.userRepo.UpdateHolidayRegion(userID, &region)
+}
+
+// ==================== IMPORT ====================
+
+// ImportHolidays menyimpan holiday dari provider untuk satu tahun.
+// Data dari source yang sama di-replace: yang hilang dari provider dihapus.
+func (s *HolidayService) ImportHolidays(provider HolidayProvider, year int) (*HolidayImportResult, error) {
+	fetched, err := provider.Fetch(year)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.holidayRepo.FindPublicByYear(year)
+	if err != nil {
+		return nil, err
+	}
+
+	existingByKey := make(map[string]*models.Holiday, len(existing))
+	for i := range existing {
+		existingByKey[holidayKey(&existing[i])] = &existing[i]
+	}
+
+	result := &HolidayImportResult{Source: provider.Name(), Year: year}
+	seen := map[string]bool{}
+
+	for i := range fetched {
+		holiday := fetched[i]
+		if holiday.Date.Year() != year {
+			continue
+		}
+		if holiday.Type == "" {
+			holiday.Type = models.HolidayTypeNational
+		}
+		holiday.IsNational = holiday.Region == nil && holiday.Type != models.HolidayTypeRegional
+
+		key := holidayKey(&holiday)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		current, ok := existingByKey[key]
+		if !ok {
+			if err := s.holidayRepo.Create(&holiday); err != nil {
+				return nil, err
+			}
+			result.Created++
+			continue
+		}
+
+		if current.Type == holiday.Type && current.IsNational == holiday.IsNational &&
+			stringPtrEqual(current.Description, holiday.Description) && current.Source == holiday.Source {
+			result.Unchanged++
+			continue
+		}
+
+		current.Type = holiday.Type
+		current.IsNational = holiday.IsNational
+		current.Description = holiday.Description
+		current.Source = holiday.Source
+		if err := s.holidayRepo.Update(current); err != nil {
+			return nil, err
+		}
+		result.Updated++
+	}
+
+	// Hapus data lama dari source yang sama yang sudah tidak ada di provider
+	var stale []string
+	for key, holiday := range existingByKey {
+		if seen[key] {
+			continue
+		}
+		if importedBy(provider, holiday) {
+			stale = append(stale, holiday.ID)
+		}
+	}
+	if err := s.holidayRepo.DeletePublicByIDs(stale); err != nil {
+		return nil, err
+	}
+	result.Removed = len(stale)
+
+	return result, nil
+}
+
+// ImportFromSource import holiday satu tahun dari bundled data atau URL ICS (endpoint admin)
+func (s *HolidayService) ImportFromSource(dto ImportHolidaysDTO) (*HolidayImportResult, error) {
+	if dto.Year < 2000 || dto.Year > 2100 {
+		return nil, errors.New("invalid year")
+	}
+
+	var provider HolidayProvider
+	switch dto.Source {
+	case "", "bundled":
+		provider = s.bundled
+	case "ics":
+		if dto.URL == "" {
+			return nil, errors.New("url is required for ics source")
+		}
+		if err := validateOutboundURL(context.Background(), dto.URL); err != nil {
+			return nil, fmt.Errorf("invalid url: %v", err)
+		}
+		holidayType := models.HolidayType(dto.Type)
+		switch holidayType {
+		case "", models.HolidayTypeNational, models.HolidayTypeRegional, models.HolidayTypeCollectiveLeave:
+		default:
+			return nil, errors.New("invalid holiday type")
+		}
+		if holidayType == models.HolidayTypeRegional && dto.Region == "" {
+			return nil, errors.New("region is required for regional holidays")
+		}
+		provider = NewICSHolidayProvider(dto.URL, strings.ToUpper(dto.Region), holidayType)
+	default:
+		return nil, errors.New("invalid source. Use 'bundled' or 'ics'")
+	}
+
+	return s.ImportHolidays(provider, dto.Year)
+}
+
+// RefreshHolidays dijalankan scheduler: sinkron tahun ini & tahun depan dari semua provider
+func (s *HolidayService) RefreshHolidays(ctx context.Context, now time.Time) []HolidayImportResult {
+	providers := []HolidayProvider{s.bundled}
+	providers = append(providers, configuredICSProviders()...)
+
+	var results []HolidayImportResult
+	for _, year := range []int{now.Year(), now.Year() + 1} {
+		for _, provider := range providers {
+			if ctx.Err() != nil {
+				return results
+			}
+			if bundled, ok := provider.(*BundledHolidayProvider); ok && !containsInt(bundled.Years(), year) {
+				continue
+			}
+
+			result, err := s.ImportHolidays(provider, year)
+			if err != nil {
+				log.Printf("⚠️ Holiday refresh %s %d failed: %v", provider.Name(), year, err)
+				continue
+			}
+			results = append(results, *result)
+		}
+	}
+
+	return results
+}
+
+// configuredICSProviders membaca HOLIDAY_ICS_URLS: "url" (nasional) atau "ID-BA=url" (daerah)
+func configuredICSProviders() []HolidayProvider {
+	if config.AppConfig == nil || config.AppConfig.HolidayICSURLs == "" {
+		return nil
+	}
+
+	var providers []HolidayProvider
+	for _, entry := range strings.Split(config.AppConfig.HolidayICSURLs, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if region, feedURL, ok := strings.Cut(entry, "="); ok && !strings.Contains(region, "://") {
+			providers = append(providers, NewICSHolidayProvider(feedURL, strings.ToUpper(region), models.HolidayTypeRegional))
+			continue
+		}
+		providers = append(providers, NewICSHolidayProvider(entry, "", models.HolidayTypeNational))
+	}
+	return providers
+}
+
+// importedBy true jika holiday publik berasal dari provider. Baris lama dari seed_holidays.go
+// (source kosong, sebagian tanggalnya salah) dianggap milik data bundled; baris ICS lama
+// bersource "ics:<host>" hanya dianggap milik feed dengan region dan tipe yang sama.
+func importedBy(provider HolidayProvider, holiday *models.Holiday) bool {
+	if holiday.Source == provider.Name() {
+		return true
+	}
+	switch p := provider.(type) {
+	case *BundledHolidayProvider:
+		return holiday.Source == ""
+	case *ICSHolidayProvider:
+		return holiday.Source == p.hostSource() && stringPtrEqual(holiday.Region, p.region) &&
+			(holiday.Type == p.holidayType || holiday.Type == models.HolidayTypeCollectiveLeave)
+	}
+	return false
+}
+
+// holidayKey identitas holiday publik: tanggal + nama + region
+func holidayKey(h *models.Holiday) string {
+	region := ""
+	if h.Region != nil {
+		region = *h.Region
+	}
+	return fmt.Sprintf("%s|%s|%s", h.Date.Format("2006-01-02"), strings.ToLower(strings.TrimSpace(h.Name)), region)
+}
+
+func stringPtrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func containsInt(values []int, target int) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// DTOs
+
+type UpdatePersonalHolidayDTO struct {
+	Name               *string `json:"name"`
+	Date               *string `json:"date"` // Format: YYYY-MM-DD
+	Description        *string `json:"description"`
+	IsRecurring        *bool   `json:"is_recurring"`
+	ReminderDaysBefore *int    `json:"reminder_days_before"`
+	ClearReminder      bool    `json:"clear_reminder"`
+}
+
+type HolidayReminder struct {
+	Holiday    models.Holiday
+	Occurrence time.Time
+	DaysBefore int
+	YearsSince int
+}
+
+type ImportHolidaysDTO struct {
+	Year   int    `json:"year"`
+	Source string `json:"source"` // bundled | ics
+	URL    string `json:"url"`    // wajib untuk ics
+	Region string `json:"region"` // opsional, ISO 3166-2
+	Type   string `json:"type"`   // national | regional | collective_leave
+}
+
+type HolidayImportResult struct {
+	Source    string `json:"source"`
+	Year      int    `json:"year"`
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Unchanged int    `json:"unchanged"`
+	Removed   int    `json:"removed"`
+}
+
+type HolidayRegion struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type HolidayRegionsResponse struct {
+	Current *string         `json:"current,omitempty"`
+	Regions []HolidayRegion `json:"regions"`
 }
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+)
+
+// TestRecurringOccurrences occurrence tahunan dalam rentang, termasuk 29 Februari
+func TestRecurringOccurrences(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", value)
+		return parsed
+	}
+
+	testCases := []struct {
+		name     string
+		holiday  string
+		start    string
+		end      string
+		expected []string
+	}{
+		{"Occurrence within range", "1990-10-19", "2026-10-01", "2026-10-31", []string{"2026-10-19"}},
+		{"Single day range", "1990-10-19", "2026-10-19", "2026-10-19", []string{"2026-10-19"}},
+		{"Outside range", "1990-10-19", "2026-10-20", "2026-12-31", nil},
+		{"Range across years", "1990-01-05", "2026-12-01", "2027-01-31", []string{"2027-01-05"}},
+		{"Several years", "2020-06-01", "2024-01-01", "2026-12-31", []string{"2024-06-01", "2025-06-01", "2026-06-01"}},
+		{"Not before original date", "2026-10-19", "2025-01-01", "2026-12-31", []string{"2026-10-19"}},
+		{"Leap day in leap year", "2000-02-29", "2028-02-01", "2028-03-01", []string{"2028-02-29"}},
+		{"Leap day falls back to 28 February", "2000-02-29", "2026-02-01", "2026-03-01", []string{"2026-02-28"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			holiday := &models.Holiday{Date: date(tc.holiday), IsRecurring: true}
+
+			var got []string
+			for _, occurrence := range recurringOccurrences(holiday, date(tc.start), date(tc.end)) {
+				got = append(got, occurrence.Format("2006-01-02"))
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Holiday: %s, range: %s - %s\nExpected: %v, Got: %v", tc.holiday, tc.start, tc.end, tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestImportedBy import satu feed ICS tidak menghapus holiday feed lain di host yang sama
+func TestImportedBy(t *testing.T) {
+	national := NewICSHolidayProvider("https://calendar.google.com/calendar/ical/id.indonesian%23holiday/public/basic.ics", "", models.HolidayTypeNational)
+	regional := NewICSHolidayProvider("https://calendar.google.com/calendar/ical/bali/public/basic.ics", "ID-BA", models.HolidayTypeRegional)
+	if national.Name() == regional.Name() {
+		t.Fatalf("Expected distinct sources for feeds on the same host, Got: %s", national.Name())
+	}
+
+	bali := "ID-BA"
+	testCases := []struct {
+		name     string
+		provider HolidayProvider
+		holiday  models.Holiday
+		expected bool
+	}{
+		{"Own feed", national, models.Holiday{Source: national.Name(), Type: models.HolidayTypeNational}, true},
+		{"Other feed on same host", national, models.Holiday{Source: regional.Name(), Region: &bali, Type: models.HolidayTypeRegional}, false},
+		{"Other feed from regional provider", regional, models.Holiday{Source: national.Name(), Type: models.HolidayTypeNational}, false},
+		{"Legacy host source with matching region", regional, models.Holiday{Source: "ics:calendar.google.com", Region: &bali, Type: models.HolidayTypeRegional}, true},
+		{"Legacy host source of another region", national, models.Holiday{Source: "ics:calendar.google.com", Region: &bali, Type: models.HolidayTypeRegional}, false},
+		{"Bundled provider", national, models.Holiday{Source: "bundled", Type: models.HolidayTypeNational}, false},
+		{"Legacy seed belongs to bundled", NewBundledHolidayProvider("ID"), models.Holiday{Source: "", Type: models.HolidayTypeNational}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := importedBy(tc.provider, &tc.holiday); got != tc.expected {
+				t.Errorf("Provider: %s, holiday source: %s\nExpected: %v, Got: %v", tc.provider.Name(), tc.holiday.Source, tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,649 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+const (
+	defaultJobTimeout = 30 * time.Minute
+	// leaderCheckInterval seberapa cepat job catch-up dijadwalkan ulang setelah menjadi leader
+	leaderCheckInterval = 10 * time.Second
+)
+
+// JobFunc fungsi sebuah job; ctx dibatalkan saat timeout atau shutdown
+type JobFunc func(ctx context.Context) (string, error)
+
+type jobScheduledAtKey struct{}
+
+// JobScheduledAt waktu slot yang sedang dijalankan (bisa di masa lalu untuk run catch-up);
+// waktu sekarang untuk run manual atau di luar registry
+func JobScheduledAt(ctx context.Context) time.Time {
+	if scheduledAt, ok := ctx.Value(jobScheduledAtKey{}).(time.Time); ok {
+		return scheduledAt
+	}
+	return time.Now()
+}
+
+// JobDefinition konfigurasi default sebuah job. Setiap field bisa di-override per job
+// lewat env JOB_<NAME>_SCHEDULE, _JITTER, _TIMEOUT, _MAX_CONCURRENCY dan _ENABLED
+// (NAME = nama job huruf besar, mis. JOB_TASK_REMINDER_SCHEDULE="*/2 * * * *").
+type JobDefinition struct {
+	Name           string // unik di semua scheduler, dipakai di API monitoring
+	Scheduler      string // pengelompokan di riwayat job run (notifications, security)
+	Description    string
+	Schedule       string        // ekspresi cron, lihat ParseCronSchedule
+	Jitter         time.Duration // penundaan acak [0, Jitter) setiap eksekusi terjadwal
+	Timeout        time.Duration // default 30 menit
+	MaxConcurrency int           // eksekusi paralel maksimum per instance, default 1
+	RunOnStart     bool          // jalankan sekali saat scheduler start
+	CatchUp        bool          // lanjutkan jadwal dari riwayat: slot yang terlewat saat down dijalankan sekali
+	Disabled       bool          // tidak dijadwalkan (masih bisa dijalankan manual)
+	Run            JobFunc
+}
+
+type registeredJob struct {
+	def      JobDefinition
+	schedule *CronSchedule
+	slots    chan struct{} // semaphore concurrency
+	wake     chan struct{} // hitung ulang jadwal (mis. baru menjadi leader)
+	stop     chan struct{}
+
+	// dilindungi JobRegistry.mu
+	paused  bool
+	nextRun *time.Time
+	lastRun *models.JobRun
+}
+
+// JobRegistry menjalankan semua job terjadwal dengan jadwal cron, jitter, timeout,
+// batas concurrency per job dan panic recovery. Job terjadwal hanya berjalan di leader;
+// status pause disimpan di database sehingga berlaku untuk semua replica.
+type JobRegistry struct {
+	repo     *repository.SchedulerRepository // nil = pause hanya di memori, tanpa catch-up
+	leader   *LeaderElector                  // nil = single instance
+	jobRuns  *JobRunService                  // nil = riwayat tidak disimpan
+	location *time.Location
+
+	mu      sync.RWMutex
+	jobs    map[string]*registeredJob
+	order   []string
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	loops  sync.WaitGroup
+	runs   sync.WaitGroup
+}
+
+func NewJobRegistry(repo *repository.SchedulerRepository, leader *LeaderElector, jobRuns *JobRunService, location *time.Location) *JobRegistry {
+	if location == nil {
+		location = time.Local
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobRegistry{
+		repo:     repo,
+		leader:   leader,
+		jobRuns:  jobRuns,
+		location: location,
+		jobs:     make(map[string]*registeredJob),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// JobRuns riwayat eksekusi yang dipakai registry
+func (r *JobRegistry) JobRuns() *JobRunService {
+	return r.jobRuns
+}
+
+// Register mendaftarkan job; jika registry sudah berjalan job langsung dijadwalkan
+func (r *JobRegistry) Register(def JobDefinition) error {
+	if def.Name == "" || def.Run == nil {
+		return errors.New("invalid job definition: name and run function are required")
+	}
+	schedule, err := ParseCronSchedule(def.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", def.Name, err)
+	}
+	schedule = applyJobEnvOverrides(&def, schedule)
+	if def.MaxConcurrency <= 0 {
+		def.MaxConcurrency = 1
+	}
+	if def.Timeout <= 0 {
+		def.Timeout = defaultJobTimeout
+	}
+	if def.Jitter < 0 {
+		def.Jitter = 0
+	}
+
+	job := &registeredJob{
+		def:      def,
+		schedule: schedule,
+		slots:    make(chan struct{}, def.MaxConcurrency),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.jobs[def.Name]; exists {
+		return fmt.Errorf("job already registered: %s", def.Name)
+	}
+	r.jobs[def.Name] = job
+	r.order = append(r.order, def.Name)
+	if r.started {
+		r.startLoop(job)
+	}
+	return nil
+}
+
+// Remove menghentikan dan menghapus semua job milik scheduler
+func (r *JobRegistry) Remove(scheduler string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order := r.order[:0]
+	for _, name := range r.order {
+		job := r.jobs[name]
+		if job.def.Scheduler != scheduler {
+			order = append(order, name)
+			continue
+		}
+		close(job.stop)
+		delete(r.jobs, name)
+	}
+	r.order = order
+}
+
+// Start menjadwalkan semua job yang terdaftar
+func (r *JobRegistry) Start() {
+	r.mu.Lock()
+	if r.started {
+		r.mu.Unlock()
+		return
+	}
+	r.started = true
+	for _, name := range r.order {
+		r.startLoop(r.jobs[name])
+	}
+	r.mu.Unlock()
+
+	r.refreshPaused()
+
+	r.loops.Add(1)
+	go r.watchLeadership()
+
+	log.Printf("⏰ Job registry started (%d jobs, timezone %s)", len(r.order), r.location)
+}
+
+// Stop menghentikan penjadwalan, membatalkan job yang berjalan dan menunggu semuanya selesai
+func (r *JobRegistry) Stop() {
+	r.cancel()
+	r.loops.Wait()
+	r.runs.Wait()
+	log.Println("⏰ Job registry stopped")
+}
+
+// RunNow menjalankan job sekarang di instance ini (tanpa cek leader, pause maupun jitter)
+func (r *JobRegistry) RunNow(name string) error {
+	job := r.job(name)
+	if job == nil {
+		return fmt.Errorf("job not found: %s", name)
+	}
+	if !job.acquire() {
+		return fmt.Errorf("job is already running: %s", name)
+	}
+	r.runs.Add(1)
+	go r.execute(job, "manual", time.Now())
+	return nil
+}
+
+// Pause menghentikan eksekusi terjadwal job di semua replica sampai Resume
+func (r *JobRegistry) Pause(name string) error {
+	return r.setPaused(name, true)
+}
+
+// Resume melanjutkan eksekusi terjadwal job yang di-pause
+func (r *JobRegistry) Resume(name string) error {
+	return r.setPaused(name, false)
+}
+
+func (r *JobRegistry) setPaused(name string, paused bool) error {
+	if r.job(name) == nil {
+		return fmt.Errorf("job not found: %s", name)
+	}
+	if r.repo != nil {
+		if err := r.repo.SetJobPaused(name, paused); err != nil {
+			return fmt.Errorf("failed to save job state: %w", err)
+		}
+	}
+
+	r.mu.Lock()
+	if job, ok := r.jobs[name]; ok {
+		job.paused = paused
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// List status semua job (filter scheduler opsional), urut sesuai pendaftaran
+func (r *JobRegistry) List(scheduler string) []JobInfo {
+	r.refreshPaused()
+
+	r.mu.RLock()
+	jobs := make([]*registeredJob, 0, len(r.order))
+	schedulers := map[string]bool{}
+	for _, name := range r.order {
+		job := r.jobs[name]
+		if scheduler != "" && job.def.Scheduler != scheduler {
+			continue
+		}
+		jobs = append(jobs, job)
+		schedulers[job.def.Scheduler] = true
+	}
+	r.mu.RUnlock()
+
+	// Run terakhir dari riwayat bersama, karena job bisa dijalankan replica lain
+	persisted := map[string]map[string]models.JobRun{}
+	if r.jobRuns != nil {
+		for name := range schedulers {
+			runs, err := r.jobRuns.LastRuns(name)
+			if err != nil {
+				log.Printf("⚠️ Failed to fetch last job runs for %s: %v", name, err)
+				continue
+			}
+			persisted[name] = runs
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		def := job.def
+		info := JobInfo{
+			Name:           def.Name,
+			Scheduler:      def.Scheduler,
+			Description:    def.Description,
+			Schedule:       job.schedule.String(),
+			Timeout:        def.Timeout.String(),
+			MaxConcurrency: def.MaxConcurrency,
+			Enabled:        !def.Disabled,
+			Paused:         job.paused,
+			Running:        len(job.slots),
+			NextRun:        job.nextRun,
+			LastRun:        job.lastRun,
+		}
+		if def.Jitter > 0 {
+			info.Jitter = def.Jitter.String()
+		}
+		if run, ok := persisted[def.Scheduler][def.Name]; ok && (info.LastRun == nil || run.StartedAt.After(info.LastRun.StartedAt)) {
+			info.LastRun = &run
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Status ringkasan registry untuk monitoring
+func (r *JobRegistry) Status(scheduler string) map[string]interface{} {
+	jobs := r.List(scheduler)
+
+	enabled, paused, running := 0, 0, 0
+	for _, job := range jobs {
+		if job.Enabled && !job.Paused {
+			enabled++
+		}
+		if job.Paused {
+			paused++
+		}
+		running += job.Running
+	}
+
+	r.mu.RLock()
+	started := r.started
+	r.mu.RUnlock()
+
+	return map[string]interface{}{
+		"is_running":   started && r.ctx.Err() == nil,
+		"timezone":     r.location.String(),
+		"jobs":         jobs,
+		"total_jobs":   len(jobs),
+		"enabled_jobs": enabled,
+		"paused_jobs":  paused,
+		"running_jobs": running,
+		"cluster":      r.leader.Status(),
+	}
+}
+
+func (r *JobRegistry) job(name string) *registeredJob {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.jobs[name]
+}
+
+// startLoop memulai goroutine penjadwalan job (dipanggil dengan r.mu terkunci)
+func (r *JobRegistry) startLoop(job *registeredJob) {
+	if job.def.Disabled {
+		log.Printf("⏭️ Job %s is disabled", job.def.Name)
+		return
+	}
+	r.loops.Add(1)
+	go r.loop(job)
+}
+
+// loop menunggu slot cron berikutnya (+ jitter) lalu men-dispatch job
+func (r *JobRegistry) loop(job *registeredJob) {
+	defer r.loops.Done()
+
+	now := time.Now().In(r.location)
+	if job.def.RunOnStart {
+		r.dispatch(job, now)
+	}
+	next := r.planNext(job, now)
+
+	for {
+		r.mu.Lock()
+		if next.IsZero() {
+			job.nextRun = nil
+		} else {
+			scheduled := next
+			job.nextRun = &scheduled
+		}
+		r.mu.Unlock()
+
+		var fire <-chan time.Time
+		var timer *time.Timer
+		if !next.IsZero() {
+			wait := time.Until(next)
+			if job.def.Jitter > 0 {
+				wait += time.Duration(rand.Int63n(int64(job.def.Jitter)))
+			}
+			timer = time.NewTimer(wait)
+			fire = timer.C
+		}
+
+		select {
+		case <-fire:
+			r.dispatch(job, next)
+			now = time.Now().In(r.location)
+			if next = job.schedule.Next(next); !next.IsZero() && next.Before(now) {
+				next = job.schedule.Next(now) // slot yang terlewat (mis. jitter panjang) tidak dikejar
+			}
+		case <-job.wake:
+			stopTimer(timer)
+			next = r.planNext(job, time.Now().In(r.location))
+		case <-job.stop:
+			stopTimer(timer)
+			return
+		case <-r.ctx.Done():
+			stopTimer(timer)
+			return
+		}
+	}
+}
+
+// planNext slot berikutnya setelah now; job CatchUp melanjutkan dari run terakhir di riwayat,
+// sehingga slot yang terlewat saat semua instance mati dijalankan sekali (segera)
+func (r *JobRegistry) planNext(job *registeredJob, now time.Time) time.Time {
+	next := job.schedule.Next(now)
+	if !job.def.CatchUp || r.repo == nil {
+		return next
+	}
+
+	last, err := r.repo.FindLastRun(job.def.Scheduler, job.def.Name)
+	if err != nil {
+		log.Printf("⚠️ Failed to load last run of job %s: %v", job.def.Name, err)
+		return next
+	}
+	if last == nil {
+		return next
+	}
+	if due := job.schedule.Next(last.StartedAt.In(r.location)); !due.IsZero() && due.Before(next) {
+		return due
+	}
+	return next
+}
+
+// dispatch menjalankan eksekusi terjadwal jika instance ini leader, job tidak di-pause,
+// slot belum dijalankan instance lain dan batas concurrency belum tercapai
+func (r *JobRegistry) dispatch(job *registeredJob, scheduledAt time.Time) {
+	if !r.leader.IsLeader() {
+		return
+	}
+	name := job.def.Name
+
+	if r.isPaused(name) {
+		return
+	}
+	if r.repo != nil {
+		if last, err := r.repo.FindLastRun(job.def.Scheduler, name); err == nil && last != nil && !last.StartedAt.Before(scheduledAt) {
+			return // slot ini sudah dijalankan (mis. oleh leader sebelumnya)
+		}
+	}
+	if !job.acquire() {
+		log.Printf("⏭️ Job %s skipped: %d run(s) still in progress", name, job.def.MaxConcurrency)
+		return
+	}
+
+	r.runs.Add(1)
+	go r.execute(job, "scheduled", scheduledAt)
+}
+
+// execute menjalankan job yang slot concurrency-nya sudah diambil dan mencatat hasilnya
+func (r *JobRegistry) execute(job *registeredJob, trigger string, scheduledAt time.Time) {
+	defer r.runs.Done()
+
+	run := &models.JobRun{
+		Scheduler: job.def.Scheduler,
+		Job:       job.def.Name,
+		Instance:  r.leader.InstanceID(),
+		StartedAt: time.Now(),
+	}
+	result, err := r.jobRuns.Track(job.def.Scheduler, job.def.Name, func() (string, error) {
+		return r.invoke(job, scheduledAt)
+	})
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Status = models.JobRunCompleted
+	if result != "" {
+		run.Result = &result
+	}
+	if err != nil {
+		message := err.Error()
+		run.Status = models.JobRunFailed
+		run.Error = &message
+		log.Printf("❌ Job %s (%s) failed: %v", job.def.Name, trigger, err)
+	}
+
+	r.mu.Lock()
+	job.lastRun = run
+	r.mu.Unlock()
+}
+
+// invoke memanggil fungsi job dengan timeout dan panic recovery. Slot concurrency baru
+// dilepas saat fungsi job benar-benar selesai, juga jika job melewati timeout.
+func (r *JobRegistry) invoke(job *registeredJob, scheduledAt time.Time) (string, error) {
+	ctx, cancel := context.WithTimeout(context.WithValue(r.ctx, jobScheduledAtKey{}, scheduledAt), job.def.Timeout)
+	defer cancel()
+
+	type outcome struct {
+		result string
+		err    error
+	}
+	done := make(chan outcome, 1)
+
+	go func() {
+		defer job.release()
+		defer func() {
+			if p := recover(); p != nil {
+				log.Printf("❌ Job %s panicked: %v\n%s", job.def.Name, p, debug.Stack())
+				done <- outcome{err: fmt.Errorf("panic: %v", p)}
+			}
+		}()
+		result, err := job.def.Run(ctx)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.result, out.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("timed out after %s", job.def.Timeout)
+		}
+		return "", fmt.Errorf("cancelled: %w", ctx.Err())
+	}
+}
+
+// isPaused status pause terbaru dari database (fallback ke status di memori)
+func (r *JobRegistry) isPaused(name string) bool {
+	r.refreshPaused()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	job, ok := r.jobs[name]
+	return ok && job.paused
+}
+
+func (r *JobRegistry) refreshPaused() {
+	if r.repo == nil {
+		return
+	}
+	paused, err := r.repo.FindPausedJobs()
+	if err != nil {
+		log.Printf("⚠️ Failed to load paused jobs: %v", err)
+		return
+	}
+
+	r.mu.Lock()
+	for name, job := range r.jobs {
+		job.paused = paused[name]
+	}
+	r.mu.Unlock()
+}
+
+// watchLeadership menjadwalkan ulang job CatchUp saat instance ini baru menjadi leader,
+// agar slot yang terlewat saat failover tetap dijalankan
+func (r *JobRegistry) watchLeadership() {
+	defer r.loops.Done()
+
+	ticker := time.NewTicker(leaderCheckInterval)
+	defer ticker.Stop()
+
+	wasLeader := r.leader.IsLeader()
+	for {
+		select {
+		case <-ticker.C:
+			isLeader := r.leader.IsLeader()
+			if isLeader && !wasLeader {
+				r.mu.RLock()
+				for _, job := range r.jobs {
+					if job.def.CatchUp {
+						select {
+						case job.wake <- struct{}{}:
+						default:
+						}
+					}
+				}
+				r.mu.RUnlock()
+			}
+			wasLeader = isLeader
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+func (j *registeredJob) acquire() bool {
+	select {
+	case j.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (j *registeredJob) release() {
+	<-j.slots
+}
+
+// applyJobEnvOverrides menerapkan konfigurasi JOB_<NAME>_* dari env; nilai tidak valid diabaikan
+func applyJobEnvOverrides(def *JobDefinition, schedule *CronSchedule) *CronSchedule {
+	prefix := "JOB_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(def.Name)) + "_"
+
+	if value := os.Getenv(prefix + "SCHEDULE"); value != "" {
+		if override, err := ParseCronSchedule(value); err != nil {
+			log.Printf("⚠️ Ignoring %sSCHEDULE: %v", prefix, err)
+		} else {
+			schedule = override
+		}
+	}
+	if value := os.Getenv(prefix + "JITTER"); value != "" {
+		if jitter, err := time.ParseDuration(value); err != nil {
+			log.Printf("⚠️ Ignoring %sJITTER: %v", prefix, err)
+		} else {
+			def.Jitter = jitter
+		}
+	}
+	if value := os.Getenv(prefix + "TIMEOUT"); value != "" {
+		if timeout, err := time.ParseDuration(value); err != nil {
+			log.Printf("⚠️ Ignoring %sTIMEOUT: %v", prefix, err)
+		} else {
+			def.Timeout = timeout
+		}
+	}
+	if value := os.Getenv(prefix + "MAX_CONCURRENCY"); value != "" {
+		if limit, err := strconv.Atoi(value); err != nil {
+			log.Printf("⚠️ Ignoring %sMAX_CONCURRENCY: %v", prefix, err)
+		} else {
+			def.MaxConcurrency = limit
+		}
+	}
+	if value := os.Getenv(prefix + "ENABLED"); value != "" {
+		if enabled, err := strconv.ParseBool(value); err != nil {
+			log.Printf("⚠️ Ignoring %sENABLED: %v", prefix, err)
+		} else {
+			def.Disabled = !enabled
+		}
+	}
+	return schedule
+}
+
+// DTOs
+
+// JobInfo status satu job untuk API monitoring
+type JobInfo struct {
+	Name           string         `json:"name"`
+	Scheduler      string         `json:"scheduler"`
+	Description    string         `json:"description,omitempty"`
+	Schedule       string         `json:"schedule"`
+	Jitter         string         `json:"jitter,omitempty"`
+	Timeout        string         `json:"timeout"`
+	MaxConcurrency int            `json:"max_concurrency"`
+	Enabled        bool           `json:"enabled"`
+	Paused         bool           `json:"paused"`
+	Running        int            `json:"running"`
+	NextRun        *time.Time     `json:"next_run,omitempty"`
+	LastRun        *models.JobRun `json:"last_run,omitempty"`
+}
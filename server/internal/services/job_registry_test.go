@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+)
+
+// newTestJobRegistry registry tanpa database dan election (single instance, pause di memori)
+func newTestJobRegistry(t *testing.T, def JobDefinition) (*JobRegistry, *registeredJob) {
+	t.Helper()
+	registry := NewJobRegistry(nil, nil, nil, time.UTC)
+	def.Scheduler = "test"
+	if def.Schedule == "" {
+		def.Schedule = "0 0 1 1 *"
+	}
+	if err := registry.Register(def); err != nil {
+		t.Fatalf("Failed to register job: %v", err)
+	}
+	return registry, registry.job(def.Name)
+}
+
+// TestJobRegistryConcurrencyLimit slot terjadwal dilewati selama run sebelumnya belum selesai
+func TestJobRegistryConcurrencyLimit(t *testing.T) {
+	testCases := []struct {
+		name           string
+		maxConcurrency int
+		dispatches     int
+		expectedRuns   int32
+	}{
+		{"Default limit of one", 0, 3, 1},
+		{"Limit of two", 2, 3, 2},
+		{"Below limit", 3, 2, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var started int32
+			block := make(chan struct{})
+			registry, job := newTestJobRegistry(t, JobDefinition{
+				Name:           "concurrency_job",
+				MaxConcurrency: tc.maxConcurrency,
+				Run: func(ctx context.Context) (string, error) {
+					atomic.AddInt32(&started, 1)
+					<-block
+					return "done", nil
+				},
+			})
+
+			for i := 0; i < tc.dispatches; i++ {
+				registry.dispatch(job, time.Now())
+			}
+			if tc.expectedRuns < int32(tc.dispatches) {
+				if err := registry.RunNow(job.def.Name); err == nil {
+					t.Errorf("Expected RunNow to be rejected while %d run(s) in progress", tc.expectedRuns)
+				}
+			}
+			close(block)
+			registry.runs.Wait()
+
+			if got := atomic.LoadInt32(&started); got != tc.expectedRuns {
+				t.Errorf("Dispatches: %d, limit: %d\nExpected runs: %d, Got: %d", tc.dispatches, tc.maxConcurrency, tc.expectedRuns, got)
+			}
+			if len(job.slots) != 0 {
+				t.Errorf("Expected all concurrency slots released, Got: %d in use", len(job.slots))
+			}
+		})
+	}
+}
+
+// TestJobRegistryFailures panic dan timeout dicatat sebagai run gagal dan slot-nya dilepas
+func TestJobRegistryFailures(t *testing.T) {
+	testCases := []struct {
+		name          string
+		timeout       time.Duration
+		run           JobFunc
+		expectedError string
+	}{
+		{
+			name: "Panic is recovered",
+			run: func(ctx context.Context) (string, error) {
+				panic("boom")
+			},
+			expectedError: "panic: boom",
+		},
+		{
+			name:    "Timeout cancels context",
+			timeout: 20 * time.Millisecond,
+			run: func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+			expectedError: "timed out after 20ms",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry, job := newTestJobRegistry(t, JobDefinition{
+				Name:    "failing_job",
+				Timeout: tc.timeout,
+				Run:     tc.run,
+			})
+
+			if err := registry.RunNow(job.def.Name); err != nil {
+				t.Fatalf("Unexpected RunNow error: %v", err)
+			}
+			registry.runs.Wait()
+
+			registry.mu.RLock()
+			lastRun := job.lastRun
+			registry.mu.RUnlock()
+			if lastRun == nil || lastRun.Status != models.JobRunFailed || lastRun.Error == nil ||
+				!strings.Contains(*lastRun.Error, tc.expectedError) {
+				t.Errorf("Expected failed run with error %q, Got: %+v", tc.expectedError, lastRun)
+			}
+
+			// Slot dilepas setelah fungsi job benar-benar selesai
+			deadline := time.Now().Add(time.Second)
+			for len(job.slots) != 0 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if len(job.slots) != 0 {
+				t.Errorf("Expected concurrency slot released after failure")
+			}
+		})
+	}
+}
+
+// TestJobRegistryPausedDispatch job yang di-pause tidak dijalankan terjadwal, tapi tetap bisa manual
+func TestJobRegistryPausedDispatch(t *testing.T) {
+	var runs int32
+	registry, job := newTestJobRegistry(t, JobDefinition{
+		Name: "pausable_job",
+		Run: func(ctx context.Context) (string, error) {
+			atomic.AddInt32(&runs, 1)
+			return "", nil
+		},
+	})
+
+	steps := []struct {
+		name         string
+		action       func() error
+		expectedRuns int32
+	}{
+		{"Scheduled while active", func() error { registry.dispatch(job, time.Now()); return nil }, 1},
+		{"Pause", func() error { return registry.Pause(job.def.Name) }, 1},
+		{"Scheduled while paused", func() error { registry.dispatch(job, time.Now()); return nil }, 1},
+		{"Manual while paused", func() error { return registry.RunNow(job.def.Name) }, 2},
+		{"Resume", func() error { return registry.Resume(job.def.Name) }, 2},
+		{"Scheduled after resume", func() error { registry.dispatch(job, time.Now()); return nil }, 3},
+	}
+
+	for _, step := range steps {
+		if err := step.action(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		registry.runs.Wait()
+		if got := atomic.LoadInt32(&runs); got != step.expectedRuns {
+			t.Errorf("%s\nExpected runs: %d, Got: %d", step.name, step.expectedRuns, got)
+		}
+	}
+
+	if err := registry.Pause("unknown_job"); err == nil {
+		t.Errorf("Expected error pausing an unknown job")
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// JobRunService menyimpan riwayat eksekusi job terjadwal (durasi & hasil) di database
+type JobRunService struct {
+	repo       *repository.SchedulerRepository
+	instanceID string
+}
+
+func NewJobRunService(repo *repository.SchedulerRepository, instanceID string) *JobRunService {
+	if instanceID == "" {
+		instanceID = defaultInstanceID()
+	}
+	return &JobRunService{repo: repo, instanceID: instanceID}
+}
+
+// Track menjalankan fn dan mencatat run-nya. Kegagalan menyimpan riwayat tidak
+// menghentikan job.
+func (s *JobRunService) Track(scheduler, job string, fn func() (string, error)) (string, error) {
+	if s == nil {
+		return fn()
+	}
+
+	run := &models.JobRun{
+		Scheduler: scheduler,
+		Job:       job,
+		Instance:  s.instanceID,
+		Status:    models.JobRunRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.repo.CreateRun(run); err != nil {
+		log.Printf("⚠️ Failed to record job run %s/%s: %v", scheduler, job, err)
+		return fn()
+	}
+
+	result, err := fn()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Status = models.JobRunCompleted
+	if result != "" {
+		run.Result = &result
+	}
+	if err != nil {
+		message := err.Error()
+		run.Status = models.JobRunFailed
+		run.Error = &message
+	}
+	if saveErr := s.repo.FinishRun(run); saveErr != nil {
+		log.Printf("⚠️ Failed to save job run %s/%s: %v", scheduler, job, saveErr)
+	}
+	return result, err
+}
+
+// GetRuns riwayat job run terbaru (filter scheduler/job opsional)
+func (s *JobRunService) GetRuns(scheduler, job string, limit, offset int) ([]models.JobRun, int64, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.FindRuns(scheduler, job, limit, offset)
+}
+
+// LastRuns eksekusi selesai terakhir per job (untuk melanjutkan jadwal setelah restart/failover)
+func (s *JobRunService) LastRuns(scheduler string) (map[string]models.JobRun, error) {
+	return s.repo.FindLastFinishedRuns(scheduler)
+}
+
+// Prune menghapus riwayat yang lebih lama dari retentionDays
+func (s *JobRunService) Prune(retentionDays int, now time.Time) (int64, error) {
+	if retentionDays <= 0 {
+		retentionDays = 14
+	}
+	return s.repo.DeleteRunsBefore(now.AddDate(0, 0, -retentionDays))
+}
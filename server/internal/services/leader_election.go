@@ -0,0 +1,166 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/workradar/server/internal/repository"
+)
+
+// SchedulerLeaseName lease bersama semua scheduler: satu leader menjalankan semua job
+const SchedulerLeaseName = "scheduler"
+
+// LeaderElector leader election berbasis lease di database. Setiap instance mencoba
+// mengambil/memperpanjang lease tiap ttl/3; hanya leader yang menjalankan job terjadwal.
+// Jika leader mati, instance lain mengambil alih setelah lease kedaluwarsa (maks. ttl).
+type LeaderElector struct {
+	repo       *repository.SchedulerRepository
+	name       string
+	instanceID string
+	ttl        time.Duration
+
+	mu        sync.RWMutex
+	leader    bool
+	expiresAt time.Time
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+func NewLeaderElector(repo *repository.SchedulerRepository, name, instanceID string, ttl time.Duration) *LeaderElector {
+	if instanceID == "" {
+		instanceID = defaultInstanceID()
+	}
+	if ttl < 3*time.Second {
+		ttl = 30 * time.Second
+	}
+	return &LeaderElector{
+		repo:       repo,
+		name:       name,
+		instanceID: instanceID,
+		ttl:        ttl,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start mencoba menjadi leader sekarang lalu memperpanjang lease secara berkala
+func (e *LeaderElector) Start() {
+	e.tryAcquire()
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(e.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.tryAcquire()
+			case <-e.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop berhenti memperpanjang lease dan melepasnya agar instance lain segera mengambil alih
+func (e *LeaderElector) Stop() {
+	close(e.stopChan)
+	e.wg.Wait()
+
+	e.mu.Lock()
+	wasLeader := e.leader
+	e.leader = false
+	e.mu.Unlock()
+
+	if wasLeader {
+		if err := e.repo.ReleaseLease(e.name, e.instanceID); err != nil {
+			log.Printf("⚠️ Failed to release scheduler lease: %v", err)
+		}
+	}
+}
+
+// IsLeader true jika instance ini memegang lease yang belum kedaluwarsa
+func (e *LeaderElector) IsLeader() bool {
+	if e == nil {
+		return true // tanpa election (single instance): selalu menjalankan job
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader && time.Now().Before(e.expiresAt)
+}
+
+// InstanceID identitas instance ini di lease dan riwayat job run
+func (e *LeaderElector) InstanceID() string {
+	if e == nil {
+		return defaultInstanceID()
+	}
+	return e.instanceID
+}
+
+// Status ringkasan leader election untuk monitoring
+func (e *LeaderElector) Status() map[string]interface{} {
+	status := map[string]interface{}{
+		"instance_id": e.InstanceID(),
+		"is_leader":   e.IsLeader(),
+	}
+	if e == nil {
+		return status
+	}
+
+	status["lease_ttl"] = e.ttl.String()
+	if lease, err := e.repo.FindLease(e.name); err == nil && lease != nil {
+		status["leader"] = lease.Holder
+		status["lease_expires_at"] = lease.ExpiresAt
+		status["leader_since"] = lease.AcquiredAt
+	}
+	return status
+}
+
+func (e *LeaderElector) tryAcquire() {
+	// Batas lokal dihitung dari waktu sebelum query: tidak pernah melewati expires_at di database
+	now := time.Now()
+	acquired, err := e.repo.AcquireLease(e.name, e.instanceID, e.ttl)
+	if err != nil {
+		// Gagal menghubungi DB: tetap leader hanya sampai lease lokal kedaluwarsa
+		log.Printf("⚠️ Failed to renew scheduler lease: %v", err)
+		return
+	}
+
+	e.mu.Lock()
+	wasLeader := e.leader
+	e.leader = acquired
+	if acquired {
+		e.expiresAt = now.Add(e.ttl)
+	}
+	e.mu.Unlock()
+
+	if acquired && !wasLeader {
+		log.Printf("👑 Instance %s is now the scheduler leader", e.instanceID)
+	} else if !acquired && wasLeader {
+		log.Printf("⚠️ Instance %s lost the scheduler lease", e.instanceID)
+	}
+}
+
+var (
+	processInstanceID     string
+	processInstanceIDOnce sync.Once
+)
+
+// defaultInstanceID hostname-pid-acak, unik per proses
+func defaultInstanceID() string {
+	processInstanceIDOnce.Do(func() {
+		host, err := os.Hostname()
+		if err != nil || host == "" {
+			host = "workradar"
+		}
+		processInstanceID = fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.New().String()[:8])
+	})
+	return processInstanceID
+}
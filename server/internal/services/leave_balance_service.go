@@ -0,0 +1,624 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+	"gorm.io/gorm"
+)
+
+// DefaultAnnualLeaveDays jatah cuti tahunan default (UU Ketenagakerjaan: 12 hari kerja)
+const DefaultAnnualLeaveDays = 12
+
+// LeaveBalanceService mengelola ledger saldo cuti: jatah tahunan, accrual bulanan,
+// carry-over dan penyesuaian manual
+type LeaveBalanceService struct {
+	balanceRepo    *repository.LeaveBalanceRepository
+	leaveRepo      *repository.LeaveRepository
+	userRepo       *repository.UserRepository
+	workspaceRepo  *repository.WorkspaceRepository
+	holidayService *HolidayService
+	accessControl  *AccessControlService
+}
+
+func NewLeaveBalanceService(
+	balanceRepo *repository.LeaveBalanceRepository,
+	leaveRepo *repository.LeaveRepository,
+	userRepo *repository.UserRepository,
+	workspaceRepo *repository.WorkspaceRepository,
+	holidayService *HolidayService,
+	accessControl *AccessControlService,
+) *LeaveBalanceService {
+	return &LeaveBalanceService{
+		balanceRepo:    balanceRepo,
+		leaveRepo:      leaveRepo,
+		userRepo:       userRepo,
+		workspaceRepo:  workspaceRepo,
+		holidayService: holidayService,
+		accessControl:  accessControl,
+	}
+}
+
+// policyFor policy user untuk tipe cuti; nil berarti tidak dibatasi
+func (s *LeaveBalanceService) policyFor(userID string, leaveType models.LeaveType) (*models.LeavePolicy, error) {
+	policy, err := s.balanceRepo.FindPolicy(userID, leaveType)
+	if err == nil {
+		return policy, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if leaveType == models.LeaveTypeAnnual {
+		return &models.LeavePolicy{
+			UserID:          userID,
+			Type:            models.LeaveTypeAnnual,
+			YearlyAllotment: DefaultAnnualLeaveDays,
+			AccrualMode:     models.LeaveAccrualYearly,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// materialize mencatat entri otomatis (allotment/accrual/carry-over) yang sudah jatuh tempo
+// dan menyesuaikan entri jatah yang sudah tercatat dengan policy saat ini
+func (s *LeaveBalanceService) materialize(userID string, policy *models.LeavePolicy, year int, now time.Time) error {
+	if year > now.Year() {
+		return nil
+	}
+
+	if err := s.materializeCarryOver(userID, policy, year); err != nil {
+		return err
+	}
+
+	recorded, err := s.balanceRepo.FindEntitlementEntries(userID, policy.Type, year)
+	if err != nil {
+		return err
+	}
+
+	plan := planLeaveEntitlements(policy, year, now, recorded)
+	kind := leaveEntitlementKind(policy.AccrualMode)
+	// Entri yang tidak berlaku dihapus dulu agar saldo tidak pernah terhitung ganda
+	if err := s.balanceRepo.DeleteEntries(plan.remove); err != nil {
+		return err
+	}
+	for id, days := range plan.update {
+		if err := s.balanceRepo.UpdateEntryDays(id, days); err != nil {
+			return err
+		}
+	}
+	for _, entitlement := range plan.create {
+		period := entitlement.Period
+		entry := &models.LeaveLedgerEntry{
+			UserID: userID,
+			Type:   policy.Type,
+			Year:   year,
+			Kind:   kind,
+			Period: &period,
+			Days:   entitlement.Days,
+		}
+		if _, err := s.balanceRepo.CreateAutoEntry(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// materializeCarryOver membawa sisa saldo tahun sebelumnya (dibatasi CarryOverCap)
+func (s *LeaveBalanceService) materializeCarryOver(userID string, policy *models.LeavePolicy, year int) error {
+	if policy.CarryOverCap <= 0 {
+		return nil
+	}
+
+	period := fmt.Sprintf("%d", year)
+	recorded, err := s.balanceRepo.FindPeriods(userID, policy.Type, year, models.LeaveLedgerCarryOver)
+	if err != nil || recorded[period] {
+		return err
+	}
+
+	// Tahun sebelumnya tanpa ledger berarti user belum memakai fitur saldo
+	previousEntries, err := s.balanceRepo.CountEntries(userID, policy.Type, year-1)
+	if err != nil || previousEntries == 0 {
+		return err
+	}
+
+	credited, err := s.balanceRepo.SumEntries(userID, policy.Type, year-1)
+	if err != nil {
+		return err
+	}
+	taken, err := s.usedDays(userID, policy.Type, year-1, []models.LeaveStatus{models.LeaveStatusApproved}, "")
+	if err != nil {
+		return err
+	}
+
+	carry := math.Min(policy.CarryOverCap, credited-taken)
+	if carry <= 0 {
+		return nil
+	}
+
+	note := fmt.Sprintf("Sisa cuti %d (maks. %.1f hari)", year-1, policy.CarryOverCap)
+	_, err = s.balanceRepo.CreateAutoEntry(&models.LeaveLedgerEntry{
+		UserID: userID,
+		Type:   policy.Type,
+		Year:   year,
+		Kind:   models.LeaveLedgerCarryOver,
+		Period: &period,
+		Days:   roundLeaveDays(carry),
+		Note:   &note,
+	})
+	return err
+}
+
+// GetBalance saldo cuti user untuk satu tipe & tahun
+func (s *LeaveBalanceService) GetBalance(userID string, leaveType models.LeaveType, year int) (*LeaveBalance, error) {
+	if !leaveType.IsValid() {
+		return nil, errors.New("invalid leave type")
+	}
+
+	policy, err := s.policyFor(userID, leaveType)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &LeaveBalance{Type: leaveType, Year: year, Unlimited: policy == nil}
+
+	balance.Taken, err = s.usedDays(userID, leaveType, year, []models.LeaveStatus{models.LeaveStatusApproved}, "")
+	if err != nil {
+		return nil, err
+	}
+	balance.Pending, err = s.usedDays(userID, leaveType, year, []models.LeaveStatus{models.LeaveStatusPending}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		return balance, nil
+	}
+
+	if err := s.materialize(userID, policy, year, time.Now()); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.balanceRepo.FindEntries(userID, leaveType, year)
+	if err != nil {
+		return nil, err
+	}
+
+	balance.YearlyAllotment = policy.YearlyAllotment
+	balance.AccrualMode = policy.AccrualMode
+	balance.CarryOverCap = policy.CarryOverCap
+	for _, entry := range entries {
+		switch entry.Kind {
+		case models.LeaveLedgerCarryOver:
+			balance.CarriedOver += entry.Days
+		case models.LeaveLedgerAdjustment:
+			balance.Adjustments += entry.Days
+		default:
+			balance.Credited += entry.Days
+		}
+	}
+
+	remaining := roundLeaveDays(balance.Credited + balance.CarriedOver + balance.Adjustments - balance.Taken - balance.Pending)
+	balance.Remaining = &remaining
+
+	return balance, nil
+}
+
+// GetBalances saldo semua tipe cuti yang dibatasi (annual + tipe dengan policy)
+func (s *LeaveBalanceService) GetBalances(userID string, year int) ([]LeaveBalance, error) {
+	types := []models.LeaveType{models.LeaveTypeAnnual}
+
+	policies, err := s.balanceRepo.FindPolicies(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if policy.Type != models.LeaveTypeAnnual {
+			types = append(types, policy.Type)
+		}
+	}
+
+	balances := make([]LeaveBalance, 0, len(types))
+	for _, leaveType := range types {
+		balance, err := s.GetBalance(userID, leaveType, year)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, *balance)
+	}
+
+	return balances, nil
+}
+
+// GetLedger entri ledger saldo cuti
+func (s *LeaveBalanceService) GetLedger(userID string, leaveType models.LeaveType, year int) ([]models.LeaveLedgerEntry, error) {
+	if !leaveType.IsValid() {
+		return nil, errors.New("invalid leave type")
+	}
+
+	policy, err := s.policyFor(userID, leaveType)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		if err := s.materialize(userID, policy, year, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.balanceRepo.FindEntries(userID, leaveType, year)
+}
+
+// CheckRequest menolak pengajuan yang melebihi saldo. Cuti yang melewati pergantian tahun
+// dibagi per tahun dan dicek terhadap saldo tahun masing-masing. Untuk tanggal di masa depan,
+// accrual yang belum jatuh tempo sampai tanggal cuti ikut diperhitungkan.
+func (s *LeaveBalanceService) CheckRequest(leave *models.Leave) error {
+	policy, err := s.policyFor(leave.UserID, leave.Type)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	now := time.Now()
+	for year := leave.Date.Year(); year <= leave.LastDate().Year(); year++ {
+		requested, err := s.leaveDaysInYear(leave, year)
+		if err != nil {
+			return err
+		}
+		if requested == 0 {
+			continue
+		}
+		if err := s.checkYear(leave, policy, year, requested, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkYear saldo satu tahun cukup untuk requested hari dari pengajuan leave
+func (s *LeaveBalanceService) checkYear(leave *models.Leave, policy *models.LeavePolicy, year int, requested float64, now time.Time) error {
+	if err := s.materialize(leave.UserID, policy, year, now); err != nil {
+		return err
+	}
+
+	credited, err := s.balanceRepo.SumEntries(leave.UserID, leave.Type, year)
+	if err != nil {
+		return err
+	}
+
+	// Proyeksi accrual yang belum tercatat sampai tanggal cuti (tahun yang lewat sudah final)
+	if year >= now.Year() {
+		recorded, err := s.balanceRepo.FindPeriods(leave.UserID, leave.Type, year, leaveEntitlementKind(policy.AccrualMode))
+		if err != nil {
+			return err
+		}
+		through := leave.LastDate()
+		if through.Year() > year {
+			through = time.Date(year, 12, 31, 0, 0, 0, 0, through.Location())
+		}
+		for _, entitlement := range leaveEntitlements(policy, year, through) {
+			if !recorded[entitlement.Period] {
+				credited += entitlement.Days
+			}
+		}
+	}
+
+	used, err := s.usedDays(leave.UserID, leave.Type, year,
+		[]models.LeaveStatus{models.LeaveStatusPending, models.LeaveStatusApproved}, leave.ID)
+	if err != nil {
+		return err
+	}
+
+	remaining := roundLeaveDays(credited - used)
+	if requested > remaining {
+		return fmt.Errorf("insufficient %s leave balance for %d: %.1f day(s) remaining, %.1f requested",
+			leave.Type, year, math.Max(remaining, 0), requested)
+	}
+
+	return nil
+}
+
+// usedDays hari kerja cuti yang terpakai di satu tahun; cuti yang melewati pergantian
+// tahun hanya dihitung bagian yang jatuh di tahun itu
+func (s *LeaveBalanceService) usedDays(userID string, leaveType models.LeaveType, year int, statuses []models.LeaveStatus, excludeID string) (float64, error) {
+	used, err := s.leaveRepo.SumWorkingDays(userID, leaveType, year, statuses, excludeID)
+	if err != nil {
+		return 0, err
+	}
+
+	spanning, err := s.leaveRepo.FindSpanningYear(userID, leaveType, year, statuses, excludeID)
+	if err != nil {
+		return 0, err
+	}
+	for i := range spanning {
+		days, err := s.leaveDaysInYear(&spanning[i], year)
+		if err != nil {
+			return 0, err
+		}
+		used += days
+	}
+
+	return used, nil
+}
+
+// leaveDaysInYear hari kerja cuti yang jatuh di tahun tertentu
+func (s *LeaveBalanceService) leaveDaysInYear(leave *models.Leave, year int) (float64, error) {
+	start, end, ok := leaveYearSegment(leave.Date, leave.LastDate(), year)
+	if !ok {
+		return 0, nil
+	}
+	if start.Equal(leave.Date) && end.Equal(leave.LastDate()) {
+		return leave.WorkingDays, nil
+	}
+	return countUserWorkingDays(s.userRepo, s.holidayService, leave.UserID, start, end, leave.HalfDay)
+}
+
+// SetPolicy membuat / mengubah policy saldo cuti
+func (s *LeaveBalanceService) SetPolicy(actorID, userID string, dto LeavePolicyDTO) (*models.LeavePolicy, error) {
+	if err := s.authorizeManage(actorID, userID); err != nil {
+		return nil, err
+	}
+
+	if !dto.Type.IsValid() {
+		return nil, errors.New("invalid leave type")
+	}
+	if dto.YearlyAllotment < 0 || dto.YearlyAllotment > 365 {
+		return nil, errors.New("yearly_allotment must be between 0 and 365")
+	}
+	if dto.CarryOverCap < 0 || dto.CarryOverCap > 365 {
+		return nil, errors.New("carry_over_cap must be between 0 and 365")
+	}
+	if dto.AccrualMode == "" {
+		dto.AccrualMode = models.LeaveAccrualYearly
+	}
+	if !dto.AccrualMode.IsValid() {
+		return nil, errors.New("invalid accrual_mode, use yearly or monthly")
+	}
+
+	policy, err := s.balanceRepo.FindPolicy(userID, dto.Type)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		policy = &models.LeavePolicy{UserID: userID, Type: dto.Type}
+	}
+
+	policy.YearlyAllotment = dto.YearlyAllotment
+	policy.AccrualMode = dto.AccrualMode
+	policy.CarryOverCap = dto.CarryOverCap
+	policy.UpdatedBy = &actorID
+
+	if err := s.balanceRepo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// AddAdjustment penyesuaian manual saldo (positif/negatif)
+func (s *LeaveBalanceService) AddAdjustment(actorID, userID string, dto LeaveAdjustmentDTO) (*models.LeaveLedgerEntry, error) {
+	if err := s.authorizeManage(actorID, userID); err != nil {
+		return nil, err
+	}
+
+	if !dto.Type.IsValid() {
+		return nil, errors.New("invalid leave type")
+	}
+	if dto.Days == 0 || math.Abs(dto.Days) > 365 {
+		return nil, errors.New("days must be non-zero and at most 365")
+	}
+	note := strings.TrimSpace(dto.Note)
+	if note == "" {
+		return nil, errors.New("note is required for adjustments")
+	}
+	if dto.Year == 0 {
+		dto.Year = time.Now().Year()
+	}
+
+	entry := &models.LeaveLedgerEntry{
+		UserID:    userID,
+		Type:      dto.Type,
+		Year:      dto.Year,
+		Kind:      models.LeaveLedgerAdjustment,
+		Days:      roundLeaveDays(dto.Days),
+		Note:      &note,
+		CreatedBy: &actorID,
+	}
+	if err := s.balanceRepo.CreateEntry(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// authorizeManage policy & adjustment hanya diatur admin atau owner/admin workspace
+// tempat user menjadi member; user sendiri hanya bisa melihat saldonya
+func (s *LeaveBalanceService) authorizeManage(actorID, userID string) error {
+	canManage, err := s.CanManageLeave(actorID, userID)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return errors.New("unauthorized: leave balance is managed by an admin or workspace manager")
+	}
+	return nil
+}
+
+// CanManageLeave apakah actor boleh mengelola cuti user: admin global atau
+// manager (owner/admin) workspace yang sama. Tidak pernah berlaku untuk diri sendiri.
+func (s *LeaveBalanceService) CanManageLeave(actorID, userID string) (bool, error) {
+	if actorID == "" || actorID == userID {
+		return false, nil
+	}
+
+	isAdmin, err := s.accessControl.CheckAccess(actorID, PermissionLeaveManage)
+	if err != nil {
+		return false, err
+	}
+	if isAdmin {
+		return true, nil
+	}
+
+	roles, err := s.workspaceRepo.FindSharedRoles(actorID, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if s.accessControl.HasWorkspacePermission(role, PermissionLeaveManage) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// leaveEntitlement jatah yang jatuh tempo pada satu periode
+type leaveEntitlement struct {
+	Period string
+	Days   float64
+}
+
+func leaveEntitlementKind(mode models.LeaveAccrualMode) models.LeaveLedgerKind {
+	if mode == models.LeaveAccrualMonthly {
+		return models.LeaveLedgerAccrual
+	}
+	return models.LeaveLedgerAllotment
+}
+
+// leaveEntitlementPlan perubahan entri jatah agar ledger sesuai policy saat ini
+type leaveEntitlementPlan struct {
+	create []leaveEntitlement
+	update map[string]float64 // id entri -> jumlah hari baru
+	remove []string           // id entri
+}
+
+// planLeaveEntitlements membandingkan entri jatah yang tercatat dengan policy. Tahun berjalan
+// disesuaikan penuh: entri mode accrual lain dihapus dan jumlah hari mengikuti jatah baru.
+// Tahun yang sudah lewat tidak diubah lagi (saldo dan carry-over-nya sudah final); periode
+// yang belum tercatat hanya dilengkapi jika tahun itu belum punya entri mode lain.
+func planLeaveEntitlements(policy *models.LeavePolicy, year int, now time.Time, recorded []models.LeaveLedgerEntry) leaveEntitlementPlan {
+	plan := leaveEntitlementPlan{update: map[string]float64{}}
+	kind := leaveEntitlementKind(policy.AccrualMode)
+	closed := year < now.Year()
+
+	current := make(map[string]models.LeaveLedgerEntry, len(recorded))
+	for _, entry := range recorded {
+		if entry.Period == nil {
+			continue
+		}
+		if entry.Kind != kind {
+			if closed {
+				return plan
+			}
+			plan.remove = append(plan.remove, entry.ID)
+			continue
+		}
+		current[*entry.Period] = entry
+	}
+
+	due := map[string]bool{}
+	for _, entitlement := range leaveEntitlements(policy, year, now) {
+		due[entitlement.Period] = true
+		entry, ok := current[entitlement.Period]
+		switch {
+		case !ok:
+			plan.create = append(plan.create, entitlement)
+		case !closed && entry.Days != entitlement.Days:
+			plan.update[entry.ID] = entitlement.Days
+		}
+	}
+
+	// Jatah dihapus dari policy (mis. allotment 0): entri tahun berjalan ikut dihapus
+	if !closed {
+		for period, entry := range current {
+			if !due[period] {
+				plan.remove = append(plan.remove, entry.ID)
+			}
+		}
+	}
+
+	return plan
+}
+
+// leaveEntitlements jatah yang jatuh tempo di tahun tertentu sampai tanggal through.
+// Accrual bulanan dibulatkan kumulatif supaya total setahun tepat sama dengan jatah.
+func leaveEntitlements(policy *models.LeavePolicy, year int, through time.Time) []leaveEntitlement {
+	if year > through.Year() || policy.YearlyAllotment <= 0 {
+		return nil
+	}
+
+	if policy.AccrualMode != models.LeaveAccrualMonthly {
+		return []leaveEntitlement{{Period: fmt.Sprintf("%d", year), Days: policy.YearlyAllotment}}
+	}
+
+	lastMonth := 12
+	if year == through.Year() {
+		lastMonth = int(through.Month())
+	}
+
+	entitlements := make([]leaveEntitlement, 0, lastMonth)
+	for month := 1; month <= lastMonth; month++ {
+		days := roundLeaveDays(policy.YearlyAllotment*float64(month)/12) -
+			roundLeaveDays(policy.YearlyAllotment*float64(month-1)/12)
+		entitlements = append(entitlements, leaveEntitlement{
+			Period: fmt.Sprintf("%d-%02d", year, month),
+			Days:   roundLeaveDays(days),
+		})
+	}
+
+	return entitlements
+}
+
+// leaveYearSegment bagian rentang [start, end] yang jatuh di tahun tertentu
+func leaveYearSegment(start, end time.Time, year int) (time.Time, time.Time, bool) {
+	if start.Year() > year || end.Year() < year {
+		return time.Time{}, time.Time{}, false
+	}
+	if start.Year() < year {
+		start = time.Date(year, 1, 1, 0, 0, 0, 0, start.Location())
+	}
+	if end.Year() > year {
+		end = time.Date(year, 12, 31, 0, 0, 0, 0, end.Location())
+	}
+	return start, end, true
+}
+
+func roundLeaveDays(days float64) float64 {
+	return math.Round(days*100) / 100
+}
+
+// DTOs
+
+type LeaveBalance struct {
+	Type            models.LeaveType        `json:"type"`
+	Year            int                     `json:"year"`
+	Unlimited       bool                    `json:"unlimited"`
+	YearlyAllotment float64                 `json:"yearly_allotment"`
+	AccrualMode     models.LeaveAccrualMode `json:"accrual_mode,omitempty"`
+	CarryOverCap    float64                 `json:"carry_over_cap"`
+	Credited        float64                 `json:"credited"` // allotment/accrual yang sudah jatuh tempo
+	CarriedOver     float64                 `json:"carried_over"`
+	Adjustments     float64                 `json:"adjustments"`
+	Taken           float64                 `json:"taken"`
+	Pending         float64                 `json:"pending"`
+	Remaining       *float64                `json:"remaining"` // nil jika tidak dibatasi
+}
+
+type LeavePolicyDTO struct {
+	Type            models.LeaveType        `json:"type"`
+	YearlyAllotment float64                 `json:"yearly_allotment"`
+	AccrualMode     models.LeaveAccrualMode `json:"accrual_mode"`
+	CarryOverCap    float64                 `json:"carry_over_cap"`
+}
+
+type LeaveAdjustmentDTO struct {
+	Type models.LeaveType `json:"type"`
+	Year int              `json:"year"`
+	Days float64          `json:"days"`
+	Note string           `json:"note"`
+}
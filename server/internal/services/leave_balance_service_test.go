@@ -0,0 +1,164 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+)
+
+// TestLeaveEntitlements jatah yang jatuh tempo per periode sampai tanggal tertentu
+func TestLeaveEntitlements(t *testing.T) {
+	through := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		policy        models.LeavePolicy
+		year          int
+		firstPeriod   string
+		lastPeriod    string
+		expectedCount int
+		expectedTotal float64
+	}{
+		{"Yearly allotment", models.LeavePolicy{YearlyAllotment: 12, AccrualMode: models.LeaveAccrualYearly}, 2026, "2026", "2026", 1, 12},
+		{"Default mode is yearly", models.LeavePolicy{YearlyAllotment: 12}, 2026, "2026", "2026", 1, 12},
+		{"Monthly accrual up to current month", models.LeavePolicy{YearlyAllotment: 12, AccrualMode: models.LeaveAccrualMonthly}, 2026, "2026-01", "2026-10", 10, 10},
+		{"Monthly accrual of past year sums to allotment", models.LeavePolicy{YearlyAllotment: 10, AccrualMode: models.LeaveAccrualMonthly}, 2025, "2025-01", "2025-12", 12, 10},
+		{"Future year", models.LeavePolicy{YearlyAllotment: 12}, 2027, "", "", 0, 0},
+		{"Unlimited leave type", models.LeavePolicy{YearlyAllotment: 0, AccrualMode: models.LeaveAccrualMonthly}, 2026, "", "", 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entitlements := leaveEntitlements(&tc.policy, tc.year, through)
+			if len(entitlements) != tc.expectedCount {
+				t.Fatalf("Expected %d entitlements, Got: %+v", tc.expectedCount, entitlements)
+			}
+
+			var total float64
+			for _, entitlement := range entitlements {
+				total += entitlement.Days
+			}
+			if math.Abs(total-tc.expectedTotal) > 1e-9 {
+				t.Errorf("Expected total: %v, Got: %v (%+v)", tc.expectedTotal, total, entitlements)
+			}
+			if tc.expectedCount > 0 &&
+				(entitlements[0].Period != tc.firstPeriod || entitlements[len(entitlements)-1].Period != tc.lastPeriod) {
+				t.Errorf("Expected periods %s..%s, Got: %s..%s", tc.firstPeriod, tc.lastPeriod,
+					entitlements[0].Period, entitlements[len(entitlements)-1].Period)
+			}
+		})
+	}
+}
+
+// TestPlanLeaveEntitlements entri jatah yang tercatat disesuaikan saat mode atau jatah policy berubah
+func TestPlanLeaveEntitlements(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	yearly := &models.LeavePolicy{YearlyAllotment: 12, AccrualMode: models.LeaveAccrualYearly}
+	monthly := &models.LeavePolicy{YearlyAllotment: 12, AccrualMode: models.LeaveAccrualMonthly}
+
+	entry := func(id string, kind models.LeaveLedgerKind, period string, days float64) models.LeaveLedgerEntry {
+		return models.LeaveLedgerEntry{ID: id, Kind: kind, Period: &period, Days: days}
+	}
+	accruals := func(year, months int, days float64) []models.LeaveLedgerEntry {
+		entries := make([]models.LeaveLedgerEntry, 0, months)
+		for month := 1; month <= months; month++ {
+			period := fmt.Sprintf("%d-%02d", year, month)
+			entries = append(entries, entry("accrual-"+period, models.LeaveLedgerAccrual, period, days))
+		}
+		return entries
+	}
+
+	testCases := []struct {
+		name           string
+		policy         *models.LeavePolicy
+		year           int
+		recorded       []models.LeaveLedgerEntry
+		expectedTotal  float64 // total jatah setelah plan dijalankan
+		expectedCreate int
+		expectedUpdate int
+		expectedRemove int
+	}{
+		{"Nothing recorded yet", yearly, 2026, nil, 12, 1, 0, 0},
+		{"Already up to date", yearly, 2026, []models.LeaveLedgerEntry{entry("a", models.LeaveLedgerAllotment, "2026", 12)}, 12, 0, 0, 0},
+		{"Yearly to monthly replaces allotment", monthly, 2026, []models.LeaveLedgerEntry{entry("a", models.LeaveLedgerAllotment, "2026", 12)}, 10, 10, 0, 1},
+		{"Monthly to yearly replaces accruals", yearly, 2026, accruals(2026, 10, 1), 12, 1, 0, 10},
+		{"Yearly allotment raised", &models.LeavePolicy{YearlyAllotment: 15}, 2026, []models.LeaveLedgerEntry{entry("a", models.LeaveLedgerAllotment, "2026", 12)}, 15, 0, 1, 0},
+		{"Monthly allotment raised", &models.LeavePolicy{YearlyAllotment: 24, AccrualMode: models.LeaveAccrualMonthly}, 2026, accruals(2026, 10, 1), 20, 0, 10, 0},
+		{"Allotment removed", &models.LeavePolicy{YearlyAllotment: 0}, 2026, []models.LeaveLedgerEntry{entry("a", models.LeaveLedgerAllotment, "2026", 12)}, 0, 0, 0, 1},
+		{"Past year of other mode is final", monthly, 2025, []models.LeaveLedgerEntry{entry("a", models.LeaveLedgerAllotment, "2025", 12)}, 12, 0, 0, 0},
+		{"Past year keeps recorded days", &models.LeavePolicy{YearlyAllotment: 15}, 2025, []models.LeaveLedgerEntry{entry("a", models.LeaveLedgerAllotment, "2025", 12)}, 12, 0, 0, 0},
+		{"Past year missing accruals completed", monthly, 2025, accruals(2025, 10, 1), 12, 2, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := planLeaveEntitlements(tc.policy, tc.year, now, tc.recorded)
+			if len(plan.create) != tc.expectedCreate || len(plan.update) != tc.expectedUpdate || len(plan.remove) != tc.expectedRemove {
+				t.Fatalf("Expected create/update/remove: %d/%d/%d, Got: %d/%d/%d",
+					tc.expectedCreate, tc.expectedUpdate, tc.expectedRemove, len(plan.create), len(plan.update), len(plan.remove))
+			}
+
+			removed := map[string]bool{}
+			for _, id := range plan.remove {
+				removed[id] = true
+			}
+			var total float64
+			for _, recorded := range tc.recorded {
+				if removed[recorded.ID] {
+					continue
+				}
+				if days, ok := plan.update[recorded.ID]; ok {
+					total += days
+					continue
+				}
+				total += recorded.Days
+			}
+			for _, entitlement := range plan.create {
+				total += entitlement.Days
+			}
+			if math.Abs(total-tc.expectedTotal) > 1e-9 {
+				t.Errorf("Policy: %+v, year: %d\nExpected total: %v, Got: %v", *tc.policy, tc.year, tc.expectedTotal, total)
+			}
+		})
+	}
+}
+
+// TestLeaveYearSegment cuti yang melewati pergantian tahun dibagi per tahun kalender
+func TestLeaveYearSegment(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", value)
+		return parsed
+	}
+
+	testCases := []struct {
+		name          string
+		start         string
+		end           string
+		year          int
+		expectedStart string
+		expectedEnd   string
+		expectedOK    bool
+	}{
+		{"Within year", "2026-10-19", "2026-10-23", 2026, "2026-10-19", "2026-10-23", true},
+		{"Year end part", "2026-12-31", "2027-01-02", 2026, "2026-12-31", "2026-12-31", true},
+		{"New year part", "2026-12-31", "2027-01-02", 2027, "2027-01-01", "2027-01-02", true},
+		{"Year before leave", "2026-12-31", "2027-01-02", 2025, "", "", false},
+		{"Year after leave", "2026-12-31", "2027-01-02", 2028, "", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, ok := leaveYearSegment(date(tc.start), date(tc.end), tc.year)
+			if ok != tc.expectedOK {
+				t.Fatalf("Input: %s - %s in %d\nExpected ok: %v, Got: %v", tc.start, tc.end, tc.year, tc.expectedOK, ok)
+			}
+			if ok && (start.Format("2006-01-02") != tc.expectedStart || end.Format("2006-01-02") != tc.expectedEnd) {
+				t.Errorf("Input: %s - %s in %d\nExpected: %s - %s, Got: %s - %s", tc.start, tc.end, tc.year,
+					tc.expectedStart, tc.expectedEnd, start.Format("2006-01-02"), end.Format("2006-01-02"))
+			}
+		})
+	}
+}
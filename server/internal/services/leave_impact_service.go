@@ -0,0 +1,323 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+	"gorm.io/gorm"
+)
+
+// leaveReminderLookahead task dengan deadline sampai sekian setelah cuti masih
+// dicek, karena reminder-nya bisa jatuh di tengah cuti
+const leaveReminderLookahead = 7 * 24 * time.Hour
+
+const (
+	LeaveImpactDeadline = "deadline_during_leave"
+	LeaveImpactReminder = "reminder_during_leave"
+
+	LeaveImpactActionReschedule = "reschedule"
+	LeaveImpactActionMute       = "mute"
+	LeaveImpactActionKeep       = "keep"
+)
+
+// LeaveImpactService menganalisis task & reminder yang terdampak cuti
+// dan menerapkan reschedule / mute yang dikonfirmasi user
+type LeaveImpactService struct {
+	leaveRepo         *repository.LeaveRepository
+	taskRepo          *repository.TaskRepository
+	workCalendar      *WorkCalendarService
+	activityService   *TaskActivityService
+	botMessageService *BotMessageService
+	broker            EventBroker
+}
+
+func NewLeaveImpactService(
+	leaveRepo *repository.LeaveRepository,
+	taskRepo *repository.TaskRepository,
+	workCalendar *WorkCalendarService,
+	activityService *TaskActivityService,
+	botMessageService *BotMessageService,
+	broker EventBroker,
+) *LeaveImpactService {
+	return &LeaveImpactService{
+		leaveRepo:         leaveRepo,
+		taskRepo:          taskRepo,
+		workCalendar:      workCalendar,
+		activityService:   activityService,
+		botMessageService: botMessageService,
+		broker:            broker,
+	}
+}
+
+// Analyze preview task yang deadline atau reminder-nya jatuh saat cuti
+func (s *LeaveImpactService) Analyze(leaveID, userID string) (*LeaveImpact, error) {
+	leave, err := s.findLeave(leaveID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyze(leave)
+}
+
+func (s *LeaveImpactService) analyze(leave *models.Leave) (*LeaveImpact, error) {
+	start, end := leaveWindow(leave)
+
+	tasks, err := s.taskRepo.FindIncompleteByUserIDAndDateRange(leave.UserID, start, end.Add(leaveReminderLookahead))
+	if err != nil {
+		return nil, err
+	}
+
+	impact := &LeaveImpact{
+		Leave:       leave.ToResponse(),
+		WindowStart: start,
+		WindowEnd:   end,
+		Tasks:       []LeaveTaskImpact{},
+	}
+
+	for _, task := range tasks {
+		if task.Deadline == nil {
+			continue
+		}
+
+		item := LeaveTaskImpact{
+			TaskID:   task.ID,
+			Title:    task.Title,
+			Deadline: *task.Deadline,
+			Muted:    task.ReminderMutedUntil != nil && !task.ReminderMutedUntil.Before(end),
+		}
+		// Reminder yang jatuh saat cuti diutamakan; jika tidak ada, reminder paling awal
+		for _, offset := range task.ReminderSchedule() {
+			reminderAt := task.Deadline.Add(-time.Duration(offset) * time.Minute)
+			if item.ReminderAt == nil || inWindow(reminderAt, start, end) {
+				item.ReminderAt = &reminderAt
+			}
+			if inWindow(reminderAt, start, end) {
+				break
+			}
+		}
+
+		switch {
+		case inWindow(*task.Deadline, start, end):
+			item.Reason = LeaveImpactDeadline
+			if suggested, ok := s.suggestDeadline(leave, *task.Deadline, end); ok {
+				item.SuggestedDeadline = &suggested
+			}
+		case item.ReminderAt != nil && inWindow(*item.ReminderAt, start, end):
+			item.Reason = LeaveImpactReminder
+		default:
+			continue
+		}
+
+		impact.Tasks = append(impact.Tasks, item)
+	}
+
+	return impact, nil
+}
+
+// suggestDeadline hari kerja pertama setelah cuti, jam deadline dipertahankan.
+// Cuti setengah hari pagi cukup digeser ke siang di hari yang sama.
+func (s *LeaveImpactService) suggestDeadline(leave *models.Leave, deadline, windowEnd time.Time) (time.Time, bool) {
+	if leave.HalfDay == models.LeaveHalfMorning {
+		return windowEnd.Add(time.Hour), true
+	}
+
+	last := leave.LastDate()
+	base := time.Date(last.Year(), last.Month(), last.Day(),
+		deadline.Hour(), deadline.Minute(), deadline.Second(), 0, deadline.Location())
+
+	if s.workCalendar == nil {
+		return base.AddDate(0, 0, 1), true
+	}
+	return s.workCalendar.NextWorkDay(leave.UserID, base)
+}
+
+// Apply menerapkan aksi yang dikonfirmasi user. Semua aksi divalidasi dulu
+// supaya tidak ada perubahan setengah jalan.
+func (s *LeaveImpactService) Apply(leaveID, userID string, actions []LeaveImpactActionDTO, meta RequestMeta) ([]LeaveImpactResult, error) {
+	leave, err := s.findLeave(leaveID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	impact, err := s.analyze(leave)
+	if err != nil {
+		return nil, err
+	}
+
+	affected := make(map[string]LeaveTaskImpact, len(impact.Tasks))
+	for _, item := range impact.Tasks {
+		affected[item.TaskID] = item
+	}
+
+	for _, action := range actions {
+		item, ok := affected[action.TaskID]
+		if !ok {
+			return nil, fmt.Errorf("task %s is not affected by this leave", action.TaskID)
+		}
+
+		switch action.Action {
+		case LeaveImpactActionReschedule:
+			if item.Reason != LeaveImpactDeadline {
+				return nil, fmt.Errorf("task %s only has a reminder during leave, use mute", action.TaskID)
+			}
+			if action.Deadline == nil && item.SuggestedDeadline == nil {
+				return nil, fmt.Errorf("no work day found to reschedule task %s", action.TaskID)
+			}
+			if action.Deadline != nil && inWindow(*action.Deadline, impact.WindowStart, impact.WindowEnd) {
+				return nil, fmt.Errorf("new deadline for task %s is still during leave", action.TaskID)
+			}
+		case LeaveImpactActionMute, LeaveImpactActionKeep:
+		default:
+			return nil, errors.New("invalid action, use reschedule, mute or keep")
+		}
+	}
+
+	results := make([]LeaveImpactResult, 0, len(actions))
+	for _, action := range actions {
+		result := LeaveImpactResult{TaskID: action.TaskID, Action: action.Action}
+		if action.Action == LeaveImpactActionKeep {
+			results = append(results, result)
+			continue
+		}
+
+		task, err := s.taskRepo.FindByID(action.TaskID)
+		if err != nil {
+			return results, err
+		}
+
+		var before TaskSnapshot
+		if s.activityService != nil {
+			before = s.activityService.Snapshot(task)
+		}
+
+		switch action.Action {
+		case LeaveImpactActionReschedule:
+			newDeadline := affected[action.TaskID].SuggestedDeadline
+			if action.Deadline != nil {
+				newDeadline = action.Deadline
+			}
+			if task.OriginalDeadline == nil {
+				task.OriginalDeadline = task.Deadline
+			}
+			task.Deadline = newDeadline
+			result.Deadline = newDeadline
+		case LeaveImpactActionMute:
+			mutedUntil := impact.WindowEnd
+			task.ReminderMutedUntil = &mutedUntil
+			result.MutedUntil = &mutedUntil
+		}
+
+		if err := s.taskRepo.Update(task); err != nil {
+			return results, err
+		}
+		if s.activityService != nil {
+			s.activityService.Record(userID, models.TaskActivityRescheduled, task.ID, before, s.activityService.Snapshot(task), meta)
+		}
+		publishTaskEvent(s.broker, RealtimeTaskUpdated, task)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// NotifyApproved memberi tahu pengaju jumlah task terdampak setelah cuti disetujui
+func (s *LeaveImpactService) NotifyApproved(leaveID string) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil || leave.Status != models.LeaveStatusApproved || s.botMessageService == nil {
+		return
+	}
+
+	impact, err := s.analyze(leave)
+	if err != nil {
+		log.Printf("⚠️ Failed to analyze leave impact %s: %v", leaveID, err)
+		return
+	}
+	if len(impact.Tasks) == 0 {
+		return
+	}
+
+	content := fmt.Sprintf("Ada %d task dengan deadline atau pengingat selama cuti %s. Buka preview untuk memindahkan deadline atau membisukan pengingat.",
+		len(impact.Tasks), formatLeavePeriod(leave))
+	metadata := map[string]interface{}{
+		"leave_id":       leave.ID,
+		"affected_tasks": len(impact.Tasks),
+	}
+	if _, err := s.botMessageService.SendMessage(leave.UserID, models.MessageTypeLeave, "📋 Task Terdampak Cuti", content, metadata); err != nil {
+		log.Printf("⚠️ Failed to send leave impact message to %s: %v", leave.UserID, err)
+	}
+}
+
+func (s *LeaveImpactService) findLeave(leaveID, userID string) (*models.Leave, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("leave not found")
+		}
+		return nil, err
+	}
+
+	if leave.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	if leave.Status != models.LeaveStatusPending && leave.Status != models.LeaveStatusApproved {
+		return nil, fmt.Errorf("leave is %s", leave.Status)
+	}
+
+	return leave, nil
+}
+
+// leaveWindow rentang waktu cuti [start, end) dalam zona lokal
+func leaveWindow(leave *models.Leave) (time.Time, time.Time) {
+	last := leave.LastDate()
+	start := time.Date(leave.Date.Year(), leave.Date.Month(), leave.Date.Day(), 0, 0, 0, 0, time.Local)
+	end := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+
+	switch leave.HalfDay {
+	case models.LeaveHalfMorning:
+		end = start.Add(12 * time.Hour)
+	case models.LeaveHalfAfternoon:
+		start = start.Add(12 * time.Hour)
+	}
+
+	return start, end
+}
+
+func inWindow(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
+
+// DTOs
+
+type LeaveImpact struct {
+	Leave       models.LeaveResponse `json:"leave"`
+	WindowStart time.Time            `json:"window_start"`
+	WindowEnd   time.Time            `json:"window_end"`
+	Tasks       []LeaveTaskImpact    `json:"tasks"`
+}
+
+type LeaveTaskImpact struct {
+	TaskID            string     `json:"task_id"`
+	Title             string     `json:"title"`
+	Deadline          time.Time  `json:"deadline"`
+	ReminderAt        *time.Time `json:"reminder_at,omitempty"`
+	Reason            string     `json:"reason"` // deadline_during_leave | reminder_during_leave
+	SuggestedDeadline *time.Time `json:"suggested_deadline,omitempty"`
+	Muted             bool       `json:"muted"`
+}
+
+type LeaveImpactActionDTO struct {
+	TaskID   string     `json:"task_id"`
+	Action   string     `json:"action"`             // reschedule | mute | keep
+	Deadline *time.Time `json:"deadline,omitempty"` // override saran reschedule
+}
+
+type LeaveImpactResult struct {
+	TaskID     string     `json:"task_id"`
+	Action     string     `json:"action"`
+	Deadline   *time.Time `json:"deadline,omitempty"`
+	MutedUntil *time.Time `json:"muted_until,omitempty"`
+}
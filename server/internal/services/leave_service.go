@@ -2,6 +2,9 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/workradar/server/internal/models"
@@ -9,13 +12,45 @@ import (
 	"gorm.io/gorm"
 )
 
+// maxLeaveRangeDays batas panjang satu pengajuan cuti (hari kalender)
+const maxLeaveRangeDays = 180
+
 type LeaveService struct {
-	leaveRepo *repository.LeaveRepository
+	leaveRepo           *repository.LeaveRepository
+	userRepo            *repository.UserRepository
+	workspaceRepo       *repository.WorkspaceRepository
+	holidayService      *HolidayService
+	balanceService      *LeaveBalanceService
+	notificationService *NotificationService
+	preferenceService   *NotificationPreferenceService
+	botMessageService   *BotMessageService
+	auditService        *AuditService
+	broker              EventBroker
 }
 
-func NewLeaveService(leaveRepo *repository.LeaveRepository) *LeaveService {
+func NewLeaveService(
+	leaveRepo *repository.LeaveRepository,
+	userRepo *repository.UserRepository,
+	workspaceRepo *repository.WorkspaceRepository,
+	holidayService *HolidayService,
+	balanceService *LeaveBalanceService,
+	notificationService *NotificationService,
+	preferenceService *NotificationPreferenceService,
+	botMessageService *BotMessageService,
+	auditService *AuditService,
+	broker EventBroker,
+) *LeaveService {
 	return &LeaveService{
-		leaveRepo: leaveRepo,
+		leaveRepo:           leaveRepo,
+		userRepo:            userRepo,
+		workspaceRepo:       workspaceRepo,
+		holidayService:      holidayService,
+		balanceService:      balanceService,
+		notificationService: notificationService,
+		preferenceService:   preferenceService,
+		botMessageService:   botMessageService,
+		auditService:        auditService,
+		broker:              broker,
 	}
 }
 
@@ -79,34 +114,48 @@ func (s *LeaveService) GetLeavesByMonth(userID string, year int, month time.Mont
 	return responses, nil
 }
 
-// CreateLeave membuat leave baru
-func (s *LeaveService) CreateLeave(userID string, date time.Time, reason string) (*models.LeaveResponse, error) {
-	// Check if leave already exists on this date
-	exists, err := s.leaveRepo.IsLeaveOnDate(userID, date)
+// CreateLeave membuat leave baru dengan status pending. Cuti diputuskan approver yang
+// ditunjuk, atau owner/admin workspace user jika belum ada approver.
+func (s *LeaveService) CreateLeave(userID string, req LeaveRequestDTO, meta RequestMeta) (*models.LeaveResponse, error) {
+	leave := &models.Leave{
+		UserID: userID,
+		Status: models.LeaveStatusPending,
+	}
+	if err := s.applyLeaveRequest(leave, req); err != nil {
+		return nil, err
+	}
+
+	reviewers, err := s.reviewerIDs(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	if exists {
-		return nil, errors.New("leave already exists on this date")
+	if err := s.leaveRepo.Create(leave); err != nil {
+		return nil, err
 	}
 
-	leave := &models.Leave{
-		UserID: userID,
-		Date:   date,
-		Reason: reason,
+	s.recordTransition(leave, userID, "", nil)
+	if s.auditService != nil {
+		s.auditService.LogCreate(&userID, "leaves", leave.ID, leave.ToResponse(), meta.IP, meta.UserAgent, meta.Path, 201, 0)
 	}
 
-	if err := s.leaveRepo.Create(leave); err != nil {
-		return nil, err
+	if len(reviewers) == 0 {
+		log.Printf("⚠️ Leave %s has no approver or workspace manager, waiting for an admin", leave.ID)
+	}
+	requester := s.displayName(userID)
+	for _, reviewerID := range reviewers {
+		s.notify(reviewerID, leave,
+			"📝 Pengajuan Cuti Baru",
+			fmt.Sprintf("%s mengajukan cuti pada %s: %s", requester, formatLeavePeriod(leave), leave.Reason),
+		)
 	}
 
 	response := leave.ToResponse()
 	return &response, nil
 }
 
-// UpdateLeave mengupdate leave
-func (s *LeaveService) UpdateLeave(leaveID, userID string, date time.Time, reason string) (*models.LeaveResponse, error) {
+// UpdateLeave mengupdate leave. Cuti yang sudah disetujui kembali ke pending.
+func (s *LeaveService) UpdateLeave(leaveID, userID string, req LeaveRequestDTO, meta RequestMeta) (*models.LeaveResponse, error) {
 	// Find existing leave
 	leave, err := s.leaveRepo.FindByID(leaveID)
 	if err != nil {
@@ -121,19 +170,52 @@ func (s *LeaveService) UpdateLeave(leaveID, userID string, date time.Time, reaso
 		return nil, errors.New("unauthorized to update this leave")
 	}
 
+	if leave.Status == models.LeaveStatusRejected || leave.Status == models.LeaveStatusCancelled {
+		return nil, fmt.Errorf("cannot update a %s leave", leave.Status)
+	}
+
+	old := leave.ToResponse()
+
 	// Update fields
-	leave.Date = date
-	leave.Reason = reason
+	if err := s.applyLeaveRequest(leave, req); err != nil {
+		return nil, err
+	}
+
+	resubmitted := leave.Status == models.LeaveStatusApproved
+	if resubmitted {
+		comment := "resubmitted after edit"
+		leave.Status = models.LeaveStatusPending
+		leave.IsApproved = false
+		leave.ApprovedBy = nil
+		leave.ApprovedAt = nil
+		leave.StatusComment = &comment
+	}
 
 	if err := s.leaveRepo.Update(leave); err != nil {
 		return nil, err
 	}
 
+	if resubmitted {
+		s.recordTransition(leave, userID, models.LeaveStatusApproved, leave.StatusComment)
+		reviewers, _ := s.reviewerIDs(userID)
+		requester := s.displayName(userID)
+		for _, reviewerID := range reviewers {
+			s.notify(reviewerID, leave,
+				"📝 Pengajuan Cuti Diubah",
+				fmt.Sprintf("%s mengubah cuti menjadi %s: %s", requester, formatLeavePeriod(leave), leave.Reason),
+			)
+		}
+	}
+	if s.auditService != nil {
+		s.auditService.LogUpdate(&userID, "leaves", leave.ID, old, leave.ToResponse(), meta.IP, meta.UserAgent, meta.Path, 200, 0)
+	}
+
 	response := leave.ToResponse()
 	return &response, nil
 }
 
-// DeleteLeave menghapus leave
+// DeleteLeave menghapus leave yang masih pending; cuti yang sudah diputuskan
+// harus lewat CancelLeave supaya riwayat & saldo tetap konsisten
 func (s *LeaveService) DeleteLeave(leaveID, userID string) error {
 	// Verify leave exists and belongs to user
 	leave, err := s.leaveRepo.FindByID(leaveID)
@@ -148,10 +230,587 @@ func (s *LeaveService) DeleteLeave(leaveID, userID string) error {
 		return errors.New("unauthorized to delete this leave")
 	}
 
+	if leave.Status != models.LeaveStatusPending {
+		return fmt.Errorf("cannot delete a %s leave, cancel it instead", leave.Status)
+	}
+
 	return s.leaveRepo.Delete(leaveID, userID)
 }
 
+// IsOnLeave mengecek apakah user cuti (approved) pada tanggal tertentu
+func (s *LeaveService) IsOnLeave(userID string, date time.Time) (bool, error) {
+	return s.leaveRepo.IsApprovedLeaveOnDate(userID, date)
+}
+
 // GetUpcomingCount mendapatkan jumlah leaves yang akan datang
 func (s *LeaveService) GetUpcomingCount(userID string) (int64, error) {
 	return s.leaveRepo.GetUpcomingCount(userID)
 }
+
+// applyLeaveRequest validasi rentang, tipe dan half day lalu menghitung hari kerja
+func (s *LeaveService) applyLeaveRequest(leave *models.Leave, req LeaveRequestDTO) error {
+	start := truncateToDate(req.StartDate)
+	end := start
+	if req.EndDate != nil {
+		end = truncateToDate(*req.EndDate)
+	}
+
+	if end.Before(start) {
+		return errors.New("end date must not be before start date")
+	}
+	if end.Sub(start).Hours()/24 >= maxLeaveRangeDays {
+		return fmt.Errorf("leave cannot exceed %d days", maxLeaveRangeDays)
+	}
+
+	if !req.HalfDay.IsValid() {
+		return errors.New("invalid half_day, use morning or afternoon")
+	}
+	if req.HalfDay != models.LeaveFullDay && !end.Equal(start) {
+		return errors.New("half-day leave must be a single day")
+	}
+
+	leaveType := req.Type
+	if leaveType == "" {
+		leaveType = models.LeaveTypeAnnual
+	}
+	if !leaveType.IsValid() {
+		return errors.New("invalid leave type")
+	}
+
+	var customType *string
+	if leaveType == models.LeaveTypeCustom {
+		if req.CustomType == nil || strings.TrimSpace(*req.CustomType) == "" {
+			return errors.New("custom_type is required for custom leave")
+		}
+		trimmed := strings.TrimSpace(*req.CustomType)
+		customType = &trimmed
+	}
+
+	// Cek bentrok dengan cuti aktif lain; dua setengah hari yang berbeda di tanggal sama boleh
+	overlapping, err := s.leaveRepo.FindOverlapping(leave.UserID, start, end, leave.ID)
+	if err != nil {
+		return err
+	}
+	for _, other := range overlapping {
+		if req.HalfDay != models.LeaveFullDay && other.IsHalfDay() && other.HalfDay != req.HalfDay {
+			continue
+		}
+		return errors.New("leave already exists on this date")
+	}
+
+	workingDays, err := s.CountWorkingDays(leave.UserID, start, end, req.HalfDay)
+	if err != nil {
+		return err
+	}
+	if workingDays == 0 {
+		return errors.New("leave range contains no working days")
+	}
+
+	leave.Date = start
+	leave.EndDate = nil
+	if end.After(start) {
+		leave.EndDate = &end
+	}
+	leave.HalfDay = req.HalfDay
+	leave.Type = leaveType
+	leave.CustomType = customType
+	leave.WorkingDays = workingDays
+	leave.Reason = req.Reason
+
+	if s.balanceService != nil {
+		if err := s.balanceService.CheckRequest(leave); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CountWorkingDays jumlah hari kerja dalam rentang (inklusif), tidak termasuk
+// hari non-kerja sesuai WorkDays user dan hari libur. Setengah hari dihitung 0.5.
+func (s *LeaveService) CountWorkingDays(userID string, start, end time.Time, halfDay models.LeaveHalfDay) (float64, error) {
+	return countUserWorkingDays(s.userRepo, s.holidayService, userID, start, end, halfDay)
+}
+
+// countUserWorkingDays hari kerja user dalam rentang, dipakai juga untuk membagi cuti per tahun
+func countUserWorkingDays(userRepo *repository.UserRepository, holidayService *HolidayService, userID string, start, end time.Time, halfDay models.LeaveHalfDay) (float64, error) {
+	user, err := userRepo.FindByID(userID)
+	if err != nil {
+		return 0, err
+	}
+	workDaysConfig := ParseWorkDaysConfig(user.WorkDays)
+
+	holidayDates := map[string]bool{}
+	if holidayService != nil {
+		holidays, err := holidayService.GetHolidaysByDateRange(userID, start, end)
+		if err != nil {
+			return 0, err
+		}
+		for _, holiday := range holidays {
+			holidayDates[holiday.Date.Format("2006-01-02")] = true
+		}
+	}
+
+	return countWorkingDays(workDaysConfig, holidayDates, start, end, halfDay), nil
+}
+
+// countWorkingDays hari kerja dalam rentang (inklusif) untuk konfigurasi hari kerja
+// dan tanggal libur (key "2006-01-02") tertentu
+func countWorkingDays(workDaysConfig map[string]interface{}, holidayDates map[string]bool, start, end time.Time, halfDay models.LeaveHalfDay) float64 {
+	var days float64
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if !IsConfiguredWorkDay(workDaysConfig, day) || holidayDates[day.Format("2006-01-02")] {
+			continue
+		}
+		days++
+	}
+
+	if halfDay != models.LeaveFullDay && days > 0 {
+		days = 0.5
+	}
+
+	return days
+}
+
+// location zona waktu notifikasi user (default Asia/Jakarta)
+func (s *LeaveService) location(userID string) *time.Location {
+	if s.preferenceService == nil {
+		return notificationLocation(models.DefaultNotificationTimeZone)
+	}
+	return s.preferenceService.Location(userID)
+}
+
+// truncateToDate membuang komponen jam
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// formatLeavePeriod label periode cuti untuk notifikasi
+func formatLeavePeriod(leave *models.Leave) string {
+	label := leave.Date.Format("02 Jan 2006")
+	if last := leave.LastDate(); last.After(leave.Date) {
+		label = leave.Date.Format("02 Jan") + " - " + last.Format("02 Jan 2006")
+	}
+
+	switch leave.HalfDay {
+	case models.LeaveHalfMorning:
+		label += " (pagi)"
+	case models.LeaveHalfAfternoon:
+		label += " (siang)"
+	}
+
+	return label
+}
+
+// ==================== APPROVAL WORKFLOW ====================
+
+// ApproveLeave menyetujui cuti (approver yang ditunjuk, atau manager jika belum ada approver)
+func (s *LeaveService) ApproveLeave(leaveID, approverID string, comment *string, meta RequestMeta) (*models.LeaveResponse, error) {
+	return s.decide(leaveID, approverID, models.LeaveStatusApproved, comment, meta)
+}
+
+// RejectLeave menolak cuti (approver yang ditunjuk, atau manager jika belum ada approver; komentar wajib)
+func (s *LeaveService) RejectLeave(leaveID, approverID string, comment *string, meta RequestMeta) (*models.LeaveResponse, error) {
+	if comment == nil || strings.TrimSpace(*comment) == "" {
+		return nil, errors.New("comment is required when rejecting a leave")
+	}
+	return s.decide(leaveID, approverID, models.LeaveStatusRejected, comment, meta)
+}
+
+// decide transisi pending -> approved/rejected oleh approver
+func (s *LeaveService) decide(leaveID, approverID string, to models.LeaveStatus, comment *string, meta RequestMeta) (*models.LeaveResponse, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("leave not found")
+		}
+		return nil, err
+	}
+
+	canReview, err := s.canReview(approverID, leave.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReview {
+		return nil, errors.New("unauthorized")
+	}
+
+	if leave.Status != models.LeaveStatusPending {
+		return nil, fmt.Errorf("leave is already %s", leave.Status)
+	}
+
+	if err := s.transition(leave, approverID, to, comment, meta); err != nil {
+		return nil, err
+	}
+
+	approver := s.displayName(approverID)
+	title, verb := "✅ Cuti Disetujui", "menyetujui"
+	if to == models.LeaveStatusRejected {
+		title, verb = "❌ Cuti Ditolak", "menolak"
+	}
+	body := fmt.Sprintf("%s %s cuti Anda pada %s", approver, verb, formatLeavePeriod(leave))
+	if comment != nil && *comment != "" {
+		body += ": " + *comment
+	}
+	s.notify(leave.UserID, leave, title, body)
+
+	response := leave.ToResponse()
+	return &response, nil
+}
+
+// CancelLeave membatalkan cuti oleh pengaju (pending atau approved yang belum lewat)
+func (s *LeaveService) CancelLeave(leaveID, userID string, comment *string, meta RequestMeta) (*models.LeaveResponse, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("leave not found")
+		}
+		return nil, err
+	}
+
+	if leave.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	if leave.Status != models.LeaveStatusPending && leave.Status != models.LeaveStatusApproved {
+		return nil, fmt.Errorf("leave is already %s", leave.Status)
+	}
+
+	// Tanggal cuti adalah tanggal kalender user: bandingkan dengan hari ini di zona waktunya
+	today := time.Now().In(s.location(userID)).Format("2006-01-02")
+	if leave.Status == models.LeaveStatusApproved && leave.LastDate().Format("2006-01-02") < today {
+		return nil, errors.New("cannot cancel a leave that has already passed")
+	}
+
+	previous := leave.Status
+	if err := s.transition(leave, userID, models.LeaveStatusCancelled, comment, meta); err != nil {
+		return nil, err
+	}
+
+	// Approver hanya perlu tahu jika pengajuan sempat menunggu / sudah disetujui olehnya
+	if previous == models.LeaveStatusPending || leave.ApprovedBy != nil {
+		reviewers, _ := s.reviewerIDs(userID)
+		requester := s.displayName(userID)
+		for _, reviewerID := range reviewers {
+			s.notify(reviewerID, leave,
+				"🚫 Cuti Dibatalkan",
+				fmt.Sprintf("%s membatalkan cuti pada %s", requester, formatLeavePeriod(leave)),
+			)
+		}
+	}
+
+	response := leave.ToResponse()
+	return &response, nil
+}
+
+// transition mengubah status, menyimpan riwayat dan audit log
+func (s *LeaveService) transition(leave *models.Leave, actorID string, to models.LeaveStatus, comment *string, meta RequestMeta) error {
+	old := leave.ToResponse()
+	from := leave.Status
+
+	leave.Status = to
+	leave.StatusComment = comment
+	if to == models.LeaveStatusApproved {
+		now := time.Now()
+		leave.IsApproved = true
+		leave.ApprovedBy = &actorID
+		leave.ApprovedAt = &now
+	} else {
+		leave.IsApproved = false
+	}
+
+	if err := s.leaveRepo.Update(leave); err != nil {
+		return err
+	}
+
+	s.recordTransition(leave, actorID, from, comment)
+	if s.auditService != nil {
+		s.auditService.LogUpdate(&actorID, "leaves", leave.ID, old, leave.ToResponse(), meta.IP, meta.UserAgent, meta.Path, 200, 0)
+	}
+	if s.broker != nil {
+		s.broker.Publish(leave.UserID, RealtimeLeaveUpdated, leave.ToResponse())
+	}
+
+	return nil
+}
+
+func (s *LeaveService) recordTransition(leave *models.Leave, actorID string, from models.LeaveStatus, comment *string) {
+	transition := &models.LeaveTransition{
+		LeaveID:    leave.ID,
+		ActorID:    actorID,
+		FromStatus: from,
+		ToStatus:   leave.Status,
+		Comment:    comment,
+	}
+	if err := s.leaveRepo.CreateTransition(transition); err != nil {
+		log.Printf("⚠️ Failed to record leave transition %s: %v", leave.ID, err)
+	}
+}
+
+// notify kirim push notification + bot message
+func (s *LeaveService) notify(userID string, leave *models.Leave, title, body string) {
+	if s.notificationService != nil {
+		data := map[string]string{
+			"leave_id": leave.ID,
+			"status":   string(leave.Status),
+			"date":     leave.Date.Format("2006-01-02"),
+			"end_date": leave.LastDate().Format("2006-01-02"),
+		}
+		if err := s.notificationService.SendLeaveUpdate(userID, title, body, data); err != nil {
+			log.Printf("⚠️ Leave push notification to %s skipped: %v", userID, err)
+		}
+	}
+
+	if s.botMessageService != nil {
+		metadata := map[string]interface{}{
+			"leave_id": leave.ID,
+			"status":   leave.Status,
+			"date":     leave.Date.Format("2006-01-02"),
+			"end_date": leave.LastDate().Format("2006-01-02"),
+			"type":     leave.Type,
+		}
+		if _, err := s.botMessageService.SendMessage(userID, models.MessageTypeLeave, title, body, metadata); err != nil {
+			log.Printf("⚠️ Failed to send leave bot message to %s: %v", userID, err)
+		}
+	}
+}
+
+// reviewerIDs approver yang ditunjuk; tanpa approver, owner/admin workspace user
+func (s *LeaveService) reviewerIDs(userID string) ([]string, error) {
+	approvers, err := s.leaveRepo.FindApprovers(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) > 0 {
+		ids := make([]string, len(approvers))
+		for i, approver := range approvers {
+			ids[i] = approver.ApproverID
+		}
+		return ids, nil
+	}
+
+	return s.workspaceRepo.FindManagerIDs(userID)
+}
+
+// canReview approver yang ditunjuk boleh memutuskan; jika user belum punya approver,
+// admin atau owner/admin workspace user yang memutuskan. Pengaju tidak pernah boleh.
+func (s *LeaveService) canReview(actorID, userID string) (bool, error) {
+	if actorID == userID {
+		return false, nil
+	}
+
+	approvers, err := s.leaveRepo.FindApprovers(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, approver := range approvers {
+		if approver.ApproverID == actorID {
+			return true, nil
+		}
+	}
+	if len(approvers) > 0 {
+		return false, nil
+	}
+
+	return s.canManage(actorID, userID)
+}
+
+// canManage admin atau owner/admin workspace user
+func (s *LeaveService) canManage(actorID, userID string) (bool, error) {
+	if s.balanceService == nil {
+		return false, nil
+	}
+	return s.balanceService.CanManageLeave(actorID, userID)
+}
+
+func (s *LeaveService) displayName(userID string) string {
+	if user, err := s.userRepo.FindByID(userID); err == nil {
+		return user.Username
+	}
+	return "User"
+}
+
+// GetApprovalInbox cuti yang bisa diputuskan approver ini (default: pending)
+func (s *LeaveService) GetApprovalInbox(approverID string, status *models.LeaveStatus) ([]LeaveApprovalItem, error) {
+	leaves, err := s.leaveRepo.FindForApprover(approverID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]*models.User{}
+	items := make([]LeaveApprovalItem, 0, len(leaves))
+	for _, leave := range leaves {
+		requester, ok := names[leave.UserID]
+		if !ok {
+			requester, _ = s.userRepo.FindByID(leave.UserID)
+			names[leave.UserID] = requester
+		}
+
+		item := LeaveApprovalItem{LeaveResponse: leave.ToResponse()}
+		if requester != nil {
+			item.RequesterName = requester.Username
+			item.RequesterEmail = requester.Email
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetLeaveHistory riwayat status cuti (pengaju, approver atau manager-nya)
+func (s *LeaveService) GetLeaveHistory(leaveID, userID string) ([]models.LeaveTransition, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("leave not found")
+		}
+		return nil, err
+	}
+
+	if leave.UserID != userID {
+		canView, err := s.canReview(userID, leave.UserID)
+		if err == nil && !canView {
+			canView, err = s.canManage(userID, leave.UserID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			return nil, errors.New("unauthorized")
+		}
+	}
+
+	return s.leaveRepo.FindTransitions(leaveID)
+}
+
+// GetApprovers approver cuti user; selain user sendiri hanya admin / manager workspace
+func (s *LeaveService) GetApprovers(actorID, userID string) ([]models.LeaveApprover, error) {
+	if actorID != userID {
+		canManage, err := s.canManage(actorID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !canManage {
+			return nil, errors.New("unauthorized")
+		}
+	}
+	return s.leaveRepo.FindApprovers(userID)
+}
+
+// AddApprover menunjuk approver cuti user berdasarkan email. Hanya admin / manager
+// workspace yang boleh menunjuk, dan approver harus satu workspace dengan user (atau admin).
+func (s *LeaveService) AddApprover(actorID, userID, approverEmail string, meta RequestMeta) (*models.LeaveApprover, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	approverEmail = strings.TrimSpace(strings.ToLower(approverEmail))
+	if approverEmail == "" {
+		return nil, errors.New("approver email is required")
+	}
+
+	canManage, err := s.canManage(actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.New("unauthorized")
+	}
+
+	approverUser, err := s.userRepo.FindByEmail(approverEmail)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approver not found")
+		}
+		return nil, err
+	}
+
+	if approverUser.ID == userID {
+		return nil, errors.New("user cannot approve their own leave")
+	}
+
+	roles, err := s.workspaceRepo.FindSharedRoles(approverUser.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		isAdmin, err := s.canManage(approverUser.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdmin {
+			return nil, errors.New("approver must be a member of the user's workspace")
+		}
+	}
+
+	exists, err := s.leaveRepo.IsApproverOf(approverUser.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("approver already assigned")
+	}
+
+	approver := &models.LeaveApprover{
+		UserID:     userID,
+		ApproverID: approverUser.ID,
+		AssignedBy: &actorID,
+	}
+	if err := s.leaveRepo.CreateApprover(approver); err != nil {
+		return nil, err
+	}
+	if s.auditService != nil {
+		s.auditService.LogCreate(&actorID, "leave_approvers", approver.ID, approver, meta.IP, meta.UserAgent, meta.Path, 201, 0)
+	}
+	approver.Approver = approverUser
+
+	return approver, nil
+}
+
+// RemoveApprover menghapus penunjukan approver (admin / manager workspace user)
+func (s *LeaveService) RemoveApprover(actorID, approverRecordID string, meta RequestMeta) error {
+	approver, err := s.leaveRepo.FindApproverByID(approverRecordID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("approver not found")
+		}
+		return err
+	}
+
+	canManage, err := s.canManage(actorID, approver.UserID)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return errors.New("unauthorized")
+	}
+
+	affected, err := s.leaveRepo.DeleteApprover(approver.ID, approver.UserID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("approver not found")
+	}
+	if s.auditService != nil {
+		s.auditService.LogDelete(&actorID, "leave_approvers", approver.ID, approver, meta.IP, meta.UserAgent, meta.Path, 200, 0)
+	}
+	return nil
+}
+
+// DTOs
+
+// LeaveRequestDTO data pengajuan / perubahan cuti
+type LeaveRequestDTO struct {
+	StartDate  time.Time
+	EndDate    *time.Time // nil = satu hari
+	HalfDay    models.LeaveHalfDay
+	Type       models.LeaveType
+	CustomType *string
+	Reason     string
+}
+
+type LeaveApprovalItem struct {
+	models.LeaveResponse
+	RequesterName  string `json:"requester_name"`
+	RequesterEmail string `json:"requester_email"`
+}
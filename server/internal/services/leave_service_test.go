@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+)
+
+// TestCountWorkingDays hari kerja cuti: hari non-kerja user dan hari libur tidak dihitung
+func TestCountWorkingDays(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", value)
+		return parsed
+	}
+	// Senin & Sabtu saja (index 0 = Senin)
+	mondaySaturday := map[string]interface{}{
+		"0": map[string]interface{}{"is_work_day": true},
+		"5": map[string]interface{}{"is_work_day": true},
+	}
+
+	testCases := []struct {
+		name     string
+		config   map[string]interface{}
+		holidays map[string]bool
+		start    string
+		end      string
+		halfDay  models.LeaveHalfDay
+		expected float64
+	}{
+		{"Single working day", nil, nil, "2026-10-19", "2026-10-19", models.LeaveFullDay, 1},
+		{"Monday to Friday", nil, nil, "2026-10-19", "2026-10-23", models.LeaveFullDay, 5},
+		{"Full week skips weekend", nil, nil, "2026-10-19", "2026-10-25", models.LeaveFullDay, 5},
+		{"Weekend only", nil, nil, "2026-10-24", "2026-10-25", models.LeaveFullDay, 0},
+		{"Holiday excluded", nil, map[string]bool{"2026-10-21": true}, "2026-10-19", "2026-10-23", models.LeaveFullDay, 4},
+		{"Holiday on weekend", nil, map[string]bool{"2026-10-24": true}, "2026-10-19", "2026-10-25", models.LeaveFullDay, 5},
+		{"Half day", nil, nil, "2026-10-19", "2026-10-19", models.LeaveHalfMorning, 0.5},
+		{"Half day on weekend", nil, nil, "2026-10-24", "2026-10-24", models.LeaveHalfAfternoon, 0},
+		{"Custom work days", mondaySaturday, nil, "2026-10-19", "2026-10-25", models.LeaveFullDay, 2},
+		{"End before start", nil, nil, "2026-10-23", "2026-10-19", models.LeaveFullDay, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := countWorkingDays(tc.config, tc.holidays, date(tc.start), date(tc.end), tc.halfDay)
+			if got != tc.expected {
+				t.Errorf("Range: %s - %s\nExpected working days: %v, Got: %v", tc.start, tc.end, tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+)
+
+const (
+	outboxPollInterval = 5 * time.Second
+	outboxLease        = 2 * time.Minute // worker dianggap mati jika belum selesai dalam lease
+	outboxMaxAttempts  = 6
+	outboxBaseBackoff  = 30 * time.Second
+	outboxMaxBackoff   = 1 * time.Hour
+)
+
+// enqueue menyimpan notifikasi ke outbox; key kosong = tanpa idempotency.
+// Dispatcher menentukan channel sesuai preferensi user, dan notifikasi saat jam tenang
+// ditunda sampai jam tenang berakhir.
+func (s *NotificationService) enqueue(entry *models.NotificationOutbox, key string) error {
+	entry.NextAttemptAt = time.Now()
+
+	route, err := s.dispatcher.Route(entry.UserID, entry.Type, entry.NextAttemptAt)
+	if err != nil {
+		return err
+	}
+	if !route.Enabled {
+		log.Printf("⏭️  Notification %s disabled by user %s, skipping", entry.Type, entry.UserID)
+		return nil
+	}
+	if len(route.Channels) == 0 {
+		return ErrNoNotificationChannel
+	}
+	entry.Channels = route.Channels
+	if route.DeferUntil != nil {
+		entry.NextAttemptAt = *route.DeferUntil
+	}
+
+	if key != "" {
+		entry.IdempotencyKey = &key
+	}
+	entry.Status = models.NotificationStatusQueued
+
+	created, err := s.outboxRepo.Enqueue(entry)
+	if err != nil {
+		return err
+	}
+	if !created {
+		log.Printf("⏭️  Notification %s for user %s already queued, skipping", entry.Type, entry.UserID)
+		return nil
+	}
+
+	// Bangunkan worker tanpa menunggu poll berikutnya
+	select {
+	case s.outboxWake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// CanDeliver true jika notifikasi jenis ini aktif untuk user dan minimal satu channel
+// bisa menjangkaunya. Dipakai job yang memindai banyak user sebelum menghitung isi notifikasi.
+func (s *NotificationService) CanDeliver(userID, notificationType string, now time.Time) (bool, error) {
+	route, err := s.dispatcher.Route(userID, notificationType, now)
+	if err != nil {
+		return false, err
+	}
+	return route.Enabled && len(route.Channels) > 0, nil
+}
+
+// StartOutboxWorker menjalankan pengiriman outbox dengan maksimal `workers` pengiriman paralel
+func (s *NotificationService) StartOutboxWorker(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan models.NotificationOutbox)
+
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for entry := range jobs {
+				s.deliver(entry)
+			}
+		}()
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(jobs)
+
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.dispatchDue(jobs, workers)
+
+			select {
+			case <-ticker.C:
+			case <-s.outboxWake:
+			case <-s.stopChan:
+				log.Println("📨 Notification outbox worker stopped")
+				return
+			}
+		}
+	}()
+
+	log.Printf("✅ Notification outbox worker started (%d workers)", workers)
+}
+
+// StopOutboxWorker menunggu pengiriman yang sedang berjalan selesai.
+// Notifikasi yang belum terkirim tetap di outbox dan dilanjutkan saat start berikutnya.
+func (s *NotificationService) StopOutboxWorker() {
+	close(s.stopChan)
+	s.wg.Wait()
+}
+
+// dispatchDue mengambil notifikasi jatuh tempo dan membagikannya ke worker
+func (s *NotificationService) dispatchDue(jobs chan<- models.NotificationOutbox, workers int) {
+	for {
+		entries, err := s.outboxRepo.ClaimDue(time.Now(), outboxLease, workers*2)
+		if err != nil {
+			log.Printf("❌ Failed to claim notification outbox: %v", err)
+			return
+		}
+
+		for _, entry := range entries {
+			select {
+			case jobs <- entry:
+			case <-s.stopChan:
+				// Sisa entry yang sudah di-claim diambil lagi setelah lease habis
+				return
+			}
+		}
+
+		if len(entries) < workers*2 {
+			return
+		}
+	}
+}
+
+// deliver mengirim satu notifikasi ke semua channel-nya dan mencatat hasilnya.
+// Channel yang sudah berhasil tidak dikirim ulang saat retry.
+func (s *NotificationService) deliver(entry models.NotificationOutbox) {
+	attempts := entry.Attempts + 1
+	claimToken := ""
+	if entry.ClaimToken != nil {
+		claimToken = *entry.ClaimToken
+	}
+
+	channels := entry.Channels
+	if len(channels) == 0 {
+		// Entry lama sebelum ada preferensi channel
+		channels = defaultNotificationChannels
+	}
+
+	notification := Notification{
+		ID:       entry.ID,
+		UserID:   entry.UserID,
+		Type:     entry.Type,
+		Title:    entry.Title,
+		Body:     entry.Body,
+		Data:     entry.Data,
+		Priority: entry.Priority,
+		Color:    entry.Color,
+	}
+
+	delivered := append([]models.NotificationChannel(nil), entry.DeliveredChannels...)
+	var failures []string
+	retryable := false
+	for _, channel := range channels {
+		if hasChannel(delivered, channel) {
+			continue
+		}
+		if err := s.dispatcher.Send(s.ctx, channel, notification); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", channel, err))
+			if !isPermanentNotificationError(err) {
+				retryable = true
+			}
+			continue
+		}
+		delivered = append(delivered, channel)
+	}
+
+	// Sukses jika semua channel terkirim, atau sisa kegagalan memang tidak bisa di-retry
+	if len(failures) == 0 || (!retryable && len(delivered) > 0) {
+		marked, err := s.outboxRepo.MarkSent(entry.ID, claimToken, attempts, delivered, time.Now())
+		if err != nil {
+			log.Printf("⚠️ Failed to mark notification %s as sent: %v", entry.ID, err)
+		} else if !marked {
+			log.Printf("⏭️ Notification %s claim lost before it was marked sent", entry.ID)
+			return
+		}
+		if len(failures) > 0 {
+			log.Printf("⚠️ Notification %s to user %s partially sent: %s", entry.Type, entry.UserID, strings.Join(failures, "; "))
+		} else {
+			log.Printf("✅ Notification %s sent to user %s", entry.Type, entry.UserID)
+		}
+		return
+	}
+
+	lastError := strings.Join(failures, "; ")
+	status := models.NotificationStatusFailed
+	nextAttempt := time.Now().Add(outboxBackoff(attempts))
+	if attempts >= outboxMaxAttempts || !retryable {
+		status = models.NotificationStatusDead
+	}
+
+	marked, markErr := s.outboxRepo.MarkFailed(entry.ID, claimToken, status, attempts, delivered, lastError, nextAttempt)
+	if markErr != nil {
+		log.Printf("⚠️ Failed to record notification %s failure: %v", entry.ID, markErr)
+	} else if !marked {
+		log.Printf("⏭️ Notification %s claim lost before its failure was recorded", entry.ID)
+		return
+	}
+
+	if status == models.NotificationStatusDead {
+		log.Printf("❌ Notification %s to user %s dead after %d attempt(s): %s", entry.Type, entry.UserID, attempts, lastError)
+	} else {
+		log.Printf("⚠️ Notification %s to user %s failed (attempt %d), retry at %s: %s", entry.Type, entry.UserID, attempts, nextAttempt.Format("15:04:05"), lastError)
+	}
+}
+
+// GetHistory riwayat notifikasi user beserta status pengirimannya
+func (s *NotificationService) GetHistory(userID string, status *models.NotificationStatus, limit, offset int) ([]models.NotificationOutbox, int64, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.outboxRepo.FindByUserID(userID, status, limit, offset)
+}
+
+// outboxBackoff exponential backoff (30s, 1m, 2m, ...) maksimal 1 jam, dengan jitter ±20%
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(backoff)/5*2+1)) - backoff/5
+	return backoff + jitter
+}
+
+// isPermanentNotificationError error yang tidak akan berhasil walau dicoba ulang
+func isPermanentNotificationError(err error) bool {
+	return errors.Is(err, ErrNoDevices) ||
+		errors.Is(err, ErrFCMNotConfigured) ||
+		errors.Is(err, ErrNoNotificationChannel) ||
+		errors.Is(err, ErrNotificationRejected)
+}
+
+func hasChannel(channels []models.NotificationChannel, channel models.NotificationChannel) bool {
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationKey idempotency key dari tipe notifikasi + identitas alaminya
+func notificationKey(notificationType string, parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return notificationType + ":" + hex.EncodeToString(sum[:16])
+}
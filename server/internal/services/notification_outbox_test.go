@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// TestOutboxBackoff backoff eksponensial dengan batas 1 jam dan jitter ±20%
+func TestOutboxBackoff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		attempts int
+		base     time.Duration
+	}{
+		{"No attempt yet", 0, 30 * time.Second},
+		{"First attempt", 1, 30 * time.Second},
+		{"Second attempt", 2, time.Minute},
+		{"Third attempt", 3, 2 * time.Minute},
+		{"Sixth attempt", 6, 16 * time.Minute},
+		{"Capped at one hour", 8, time.Hour},
+		{"Capped for many attempts", 50, time.Hour},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			min, max := tc.base-tc.base/5, tc.base+tc.base/5
+			for i := 0; i < 200; i++ {
+				got := outboxBackoff(tc.attempts)
+				if got < min || got > max {
+					t.Fatalf("Attempts: %d\nExpected backoff within [%s, %s], Got: %s", tc.attempts, min, max, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// defaultNotificationChannels channel jika user belum mengatur preferensi
+var defaultNotificationChannels = []models.NotificationChannel{models.NotificationChannelPush}
+
+type NotificationPreferenceService struct {
+	preferenceRepo *repository.NotificationPreferenceRepository
+}
+
+func NewNotificationPreferenceService(preferenceRepo *repository.NotificationPreferenceRepository) *NotificationPreferenceService {
+	return &NotificationPreferenceService{preferenceRepo: preferenceRepo}
+}
+
+// GetPreferences mendapatkan pengaturan umum + preferensi efektif untuk setiap jenis notifikasi
+func (s *NotificationPreferenceService) GetPreferences(userID string) (*NotificationPreferencesResponse, error) {
+	settings, err := s.preferenceRepo.FindSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	applyBriefingDefaults(settings)
+
+	saved, err := s.preferenceRepo.FindPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[string]models.NotificationPreference, len(saved))
+	for _, p := range saved {
+		byType[p.Type] = p
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, t := range models.NotificationTypes {
+		if p, ok := byType[t]; ok {
+			preferences = append(preferences, p)
+			continue
+		}
+		preferences = append(preferences, defaultNotificationPreference(userID, t))
+	}
+
+	return &NotificationPreferencesResponse{
+		Settings:    settings,
+		Preferences: preferences,
+	}, nil
+}
+
+// UpdatePreferences memperbarui zona waktu, jam tenang dan/atau preferensi per jenis notifikasi
+func (s *NotificationPreferenceService) UpdatePreferences(userID string, data UpdateNotificationPreferencesDTO) (*NotificationPreferencesResponse, error) {
+	settings, err := s.preferenceRepo.FindSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settingsChanged := false
+	if data.TimeZone != nil {
+		tz := strings.TrimSpace(*data.TimeZone)
+		if _, err := time.LoadLocation(tz); err != nil || tz == "" {
+			return nil, errors.New("invalid time zone")
+		}
+		settings.TimeZone = tz
+		settingsChanged = true
+	}
+	if data.QuietHoursEnabled != nil {
+		settings.QuietHoursEnabled = *data.QuietHoursEnabled
+		settingsChanged = true
+	}
+	if data.QuietHoursStart != nil {
+		if _, err := parseClock(*data.QuietHoursStart); err != nil {
+			return nil, errors.New("invalid quiet_hours_start (use HH:MM)")
+		}
+		settings.QuietHoursStart = *data.QuietHoursStart
+		settingsChanged = true
+	}
+	if data.QuietHoursEnd != nil {
+		if _, err := parseClock(*data.QuietHoursEnd); err != nil {
+			return nil, errors.New("invalid quiet_hours_end (use HH:MM)")
+		}
+		settings.QuietHoursEnd = *data.QuietHoursEnd
+		settingsChanged = true
+	}
+	if data.AgendaTime != nil {
+		minute, err := parseClock(*data.AgendaTime)
+		if err != nil {
+			return nil, errors.New("invalid agenda_time (use HH:MM)")
+		}
+		settings.AgendaTime = formatClock(minute)
+		settingsChanged = true
+	}
+	if data.DigestTime != nil {
+		minute, err := parseClock(*data.DigestTime)
+		if err != nil {
+			return nil, errors.New("invalid digest_time (use HH:MM)")
+		}
+		settings.DigestTime = formatClock(minute)
+		settingsChanged = true
+	}
+
+	// Validasi semua preferensi dulu agar update tidak tersimpan setengah
+	updates := make([]models.NotificationPreference, 0, len(data.Preferences))
+	for _, item := range data.Preferences {
+		if !models.IsNotificationType(item.Type) {
+			return nil, fmt.Errorf("unknown notification type: %s", item.Type)
+		}
+
+		preference, err := s.preferenceRepo.FindPreference(userID, item.Type)
+		if err != nil {
+			return nil, err
+		}
+		if preference == nil {
+			p := defaultNotificationPreference(userID, item.Type)
+			preference = &p
+		}
+
+		if item.Enabled != nil {
+			preference.Enabled = *item.Enabled
+		}
+		if item.BypassQuietHours != nil {
+			preference.BypassQuietHours = *item.BypassQuietHours
+		}
+		if item.Channels != nil {
+			channels, err := normalizeNotificationChannels(*item.Channels)
+			if err != nil {
+				return nil, err
+			}
+			preference.Channels = channels
+		}
+		updates = append(updates, *preference)
+	}
+
+	if settingsChanged {
+		if err := s.preferenceRepo.SaveSettings(settings); err != nil {
+			return nil, err
+		}
+	}
+	for i := range updates {
+		if err := s.preferenceRepo.SavePreference(&updates[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPreferences(userID)
+}
+
+// Resolve menentukan apakah & bagaimana notifikasi jenis tertentu dikirim ke user saat ini.
+// DeferUntil terisi jika sekarang jam tenang user (kirim setelah jam tenang berakhir).
+func (s *NotificationPreferenceService) Resolve(userID, notificationType string, now time.Time) (*NotificationDelivery, error) {
+	preference, err := s.preferenceRepo.FindPreference(userID, notificationType)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		p := defaultNotificationPreference(userID, notificationType)
+		preference = &p
+	}
+
+	delivery := &NotificationDelivery{
+		Enabled:  preference.Enabled && len(preference.Channels) > 0,
+		Channels: preference.Channels,
+	}
+	if !delivery.Enabled || preference.BypassQuietHours {
+		return delivery, nil
+	}
+
+	settings, err := s.preferenceRepo.FindSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	delivery.DeferUntil = quietHoursEnd(settings, now)
+	return delivery, nil
+}
+
+// IsEnabled cek cepat apakah user masih menerima jenis notifikasi ini
+func (s *NotificationPreferenceService) IsEnabled(userID, notificationType string) bool {
+	preference, err := s.preferenceRepo.FindPreference(userID, notificationType)
+	if err != nil || preference == nil {
+		return true
+	}
+	return preference.Enabled && len(preference.Channels) > 0
+}
+
+// DisabledUserIDs user yang mematikan jenis notifikasi ini (untuk filter job massal)
+func (s *NotificationPreferenceService) DisabledUserIDs(notificationType string) (map[string]bool, error) {
+	userIDs, err := s.preferenceRepo.FindUserIDsWithDisabled(notificationType)
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		disabled[id] = true
+	}
+	return disabled, nil
+}
+
+// TimeZones zona waktu semua user, termasuk default untuk user tanpa pengaturan
+func (s *NotificationPreferenceService) TimeZones() ([]string, error) {
+	stored, err := s.preferenceRepo.FindTimeZones()
+	if err != nil {
+		return nil, err
+	}
+	timeZones := []string{models.DefaultNotificationTimeZone}
+	seen := map[string]bool{models.DefaultNotificationTimeZone: true}
+	for _, tz := range stored {
+		if !seen[tz] {
+			seen[tz] = true
+			timeZones = append(timeZones, tz)
+		}
+	}
+	return timeZones, nil
+}
+
+// Settings pengaturan notifikasi umum user (default jika belum diatur)
+func (s *NotificationPreferenceService) Settings(userID string) (*models.NotificationSettings, error) {
+	settings, err := s.preferenceRepo.FindSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	applyBriefingDefaults(settings)
+	return settings, nil
+}
+
+// Location zona waktu notifikasi user (default Asia/Jakarta)
+func (s *NotificationPreferenceService) Location(userID string) *time.Location {
+	settings, err := s.preferenceRepo.FindSettings(userID)
+	if err != nil {
+		return notificationLocation(models.DefaultNotificationTimeZone)
+	}
+	return notificationLocation(settings.TimeZone)
+}
+
+// applyBriefingDefaults mengisi jam agenda/digest untuk baris lama yang belum punya nilai
+func applyBriefingDefaults(settings *models.NotificationSettings) {
+	if settings.AgendaTime == "" {
+		settings.AgendaTime = models.DefaultAgendaTime
+	}
+	if settings.DigestTime == "" {
+		settings.DigestTime = models.DefaultDigestTime
+	}
+}
+
+func defaultNotificationPreference(userID, notificationType string) models.NotificationPreference {
+	return models.NotificationPreference{
+		UserID:   userID,
+		Type:     notificationType,
+		Enabled:  true,
+		Channels: append([]models.NotificationChannel(nil), defaultNotificationChannels...),
+	}
+}
+
+func normalizeNotificationChannels(channels []models.NotificationChannel) ([]models.NotificationChannel, error) {
+	seen := make(map[models.NotificationChannel]bool, len(channels))
+	result := make([]models.NotificationChannel, 0, len(channels))
+	for _, c := range channels {
+		c = models.NotificationChannel(strings.ToLower(strings.TrimSpace(string(c))))
+		if !c.IsValid() {
+			return nil, fmt.Errorf("invalid channel: %s (use push, email, in_app, web_push or webhook)", c)
+		}
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// quietHoursEnd waktu berakhirnya jam tenang jika now berada di dalamnya, nil jika tidak.
+// Jam tenang boleh melewati tengah malam (mis. 22:00-07:00).
+func quietHoursEnd(settings *models.NotificationSettings, now time.Time) *time.Time {
+	if !settings.QuietHoursEnabled {
+		return nil
+	}
+	start, err := parseClock(settings.QuietHoursStart)
+	if err != nil {
+		return nil
+	}
+	end, err := parseClock(settings.QuietHoursEnd)
+	if err != nil || start == end {
+		return nil
+	}
+
+	local := now.In(notificationLocation(settings.TimeZone))
+	minute := local.Hour()*60 + local.Minute()
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+
+	var until time.Time
+	switch {
+	case start < end && minute >= start && minute < end:
+		until = midnight.Add(time.Duration(end) * time.Minute)
+	case start > end && minute >= start:
+		until = midnight.AddDate(0, 0, 1).Add(time.Duration(end) * time.Minute)
+	case start > end && minute < end:
+		until = midnight.Add(time.Duration(end) * time.Minute)
+	default:
+		return nil
+	}
+	return &until
+}
+
+// parseClock "HH:MM" -> menit sejak tengah malam
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// formatClock menit sejak tengah malam -> "HH:MM"
+func formatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+func notificationLocation(tz string) *time.Location {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc, err = time.LoadLocation(models.DefaultNotificationTimeZone)
+		if err != nil {
+			return time.UTC
+		}
+	}
+	return loc
+}
+
+// DTOs
+
+type NotificationPreferencesResponse struct {
+	Settings    *models.NotificationSettings    `json:"settings"`
+	Preferences []models.NotificationPreference `json:"preferences"`
+	// AvailableChannels channel yang aktif di server (diisi NotificationService)
+	AvailableChannels []models.NotificationChannel `json:"available_channels,omitempty"`
+}
+
+type UpdateNotificationPreferencesDTO struct {
+	TimeZone          *string                           `json:"time_zone"`
+	QuietHoursEnabled *bool                             `json:"quiet_hours_enabled"`
+	QuietHoursStart   *string                           `json:"quiet_hours_start"`
+	QuietHoursEnd     *string                           `json:"quiet_hours_end"`
+	AgendaTime        *string                           `json:"agenda_time"`
+	DigestTime        *string                           `json:"digest_time"`
+	Preferences       []UpdateNotificationPreferenceDTO `json:"preferences"`
+}
+
+type UpdateNotificationPreferenceDTO struct {
+	Type             string                        `json:"type"`
+	Enabled          *bool                         `json:"enabled"`
+	Channels         *[]models.NotificationChannel `json:"channels"`
+	BypassQuietHours *bool                         `json:"bypass_quiet_hours"`
+}
+
+// NotificationDelivery hasil resolusi preferensi untuk satu notifikasi
+type NotificationDelivery struct {
+	Enabled    bool
+	Channels   []models.NotificationChannel
+	DeferUntil *time.Time
+}
@@ -2,99 +2,241 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"strings"
+	"sync"
 	"time"
 
-	firebase "firebase.google.com/go/v4"
-	"firebase.google.com/go/v4/messaging"
+	"github.com/workradar/server/internal/models"
 	"github.com/workradar/server/internal/repository"
-	"google.golang.org/api/option"
 )
 
+var (
+	ErrFCMNotConfigured = errors.New("FCM not configured")
+	ErrNoDevices        = errors.New("user has no devices registered")
+	// ErrNoNotificationChannel tidak ada channel pilihan user yang tersedia di server
+	ErrNoNotificationChannel = errors.New("no notification channel available")
+	// ErrNotificationRejected ditolak permanen oleh tujuan (mis. 4xx webhook, key Web Push rusak)
+	ErrNotificationRejected = errors.New("notification rejected")
+)
+
+// NotificationService mengantrikan notifikasi ke outbox; pengiriman per channel lewat NotificationDispatcher
 type NotificationService struct {
-	userRepo        *repository.UserRepository
-	messagingClient *messaging.Client
-	ctx             context.Context
+	deviceRepo        *repository.DeviceRepository
+	endpointRepo      *repository.NotificationEndpointRepository
+	outboxRepo        *repository.NotificationOutboxRepository
+	preferenceService *NotificationPreferenceService
+	dispatcher        *NotificationDispatcher
+	ctx               context.Context
+
+	// Outbox worker
+	outboxWake chan struct{}
+	stopChan   chan struct{}
+	wg         sync.WaitGroup
 }
 
-func NewNotificationService(userRepo *repository.UserRepository, projectID, credentialsPath string) (*NotificationService, error) {
-	ctx := context.Background()
+func NewNotificationService(
+	deviceRepo *repository.DeviceRepository,
+	endpointRepo *repository.NotificationEndpointRepository,
+	outboxRepo *repository.NotificationOutboxRepository,
+	preferenceService *NotificationPreferenceService,
+	dispatcher *NotificationDispatcher,
+) *NotificationService {
+	return &NotificationService{
+		deviceRepo:        deviceRepo,
+		endpointRepo:      endpointRepo,
+		outboxRepo:        outboxRepo,
+		preferenceService: preferenceService,
+		dispatcher:        dispatcher,
+		ctx:               context.Background(),
+		outboxWake:        make(chan struct{}, 1),
+		stopChan:          make(chan struct{}),
+	}
+}
 
-	// Skip initialization if credentials not provided
-	if projectID == "" || credentialsPath == "" {
-		log.Println("⚠️ Firebase credentials not configured - notifications disabled")
-		return &NotificationService{
-			userRepo:        userRepo,
-			messagingClient: nil,
-			ctx:             ctx,
-		}, nil
+// RegisterDevice registers (or refreshes) a device FCM token for a user
+func (s *NotificationService) RegisterDevice(userID string, req RegisterDeviceDTO) (*models.UserDevice, error) {
+	token := strings.TrimSpace(req.FCMToken)
+	if token == "" {
+		return nil, errors.New("FCM token is required")
 	}
 
-	// Initialize Firebase App
-	opt := option.WithCredentialsFile(credentialsPath)
-	app, err := firebase.NewApp(ctx, &firebase.Config{
-		ProjectID: projectID,
-	}, opt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize Firebase app: %w", err)
+	platform := models.DevicePlatform(strings.ToLower(strings.TrimSpace(req.Platform)))
+	switch platform {
+	case models.DevicePlatformAndroid, models.DevicePlatformIOS, models.DevicePlatformWeb:
+	case "":
+		platform = models.DevicePlatformUnknown
+	default:
+		return nil, errors.New("invalid platform (use android, ios or web)")
 	}
 
-	// Get Messaging client
-	client, err := app.Messaging(ctx)
+	device := &models.UserDevice{
+		UserID:     userID,
+		Token:      token,
+		Platform:   platform,
+		AppVersion: optionalString(req.AppVersion),
+		DeviceName: optionalString(req.DeviceName),
+		LastSeenAt: time.Now(),
+	}
+	if err := s.deviceRepo.Upsert(device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// UnregisterDevice removes a single device token; an empty token removes all devices of the user
+func (s *NotificationService) UnregisterDevice(userID, fcmToken string) error {
+	if fcmToken == "" {
+		return s.deviceRepo.DeleteByUserID(userID)
+	}
+
+	removed, err := s.deviceRepo.DeleteByToken(userID, fcmToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get messaging client: %w", err)
+		return err
+	}
+	if removed == 0 {
+		return errors.New("device not found")
 	}
+	return nil
+}
 
-	log.Println("✅ Firebase Cloud Messaging initialized successfully")
+// GetDevices lists the devices registered for push notifications
+func (s *NotificationService) GetDevices(userID string) ([]models.UserDevice, error) {
+	return s.deviceRepo.FindByUserID(userID)
+}
 
-	return &NotificationService{
-		userRepo:        userRepo,
-		messagingClient: client,
-		ctx:             ctx,
-	}, nil
+// WebPushPublicKey returns the VAPID applicationServerKey for browser subscriptions
+func (s *NotificationService) WebPushPublicKey() (string, error) {
+	notifier, ok := s.dispatcher.Notifier(models.NotificationChannelWebPush).(*WebPushNotifier)
+	if !ok || !notifier.Available() {
+		return "", errors.New("web push not configured")
+	}
+	return notifier.PublicKey(), nil
 }
 
-// RegisterDevice registers a device FCM token for a user
-func (s *NotificationService) RegisterDevice(userID, fcmToken string) error {
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return err
+// RegisterWebPush saves a browser Push API subscription (PushSubscription.toJSON())
+func (s *NotificationService) RegisterWebPush(userID string, req RegisterWebPushDTO) (*models.WebPushSubscription, error) {
+	endpoint := strings.TrimSpace(req.Endpoint)
+	if err := validateOutboundURL(context.Background(), endpoint); err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %v", err)
+	}
+	if key, err := decodeBase64URL(req.Keys.P256dh); err != nil || len(key) != 65 {
+		return nil, errors.New("invalid keys.p256dh")
+	}
+	if secret, err := decodeBase64URL(req.Keys.Auth); err != nil || len(secret) != 16 {
+		return nil, errors.New("invalid keys.auth")
 	}
 
-	user.FCMToken = &fcmToken
-	return s.userRepo.Update(user)
+	subscription := &models.WebPushSubscription{
+		UserID:    userID,
+		Endpoint:  endpoint,
+		P256dh:    strings.TrimSpace(req.Keys.P256dh),
+		Auth:      strings.TrimSpace(req.Keys.Auth),
+		UserAgent: optionalString(req.UserAgent),
+	}
+	if err := s.endpointRepo.UpsertWebPush(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
 }
 
-// UnregisterDevice removes FCM token from user
-func (s *NotificationService) UnregisterDevice(userID string) error {
-	user, err := s.userRepo.FindByID(userID)
+// UnregisterWebPush removes a browser subscription
+func (s *NotificationService) UnregisterWebPush(userID, endpoint string) error {
+	if strings.TrimSpace(endpoint) == "" {
+		return errors.New("endpoint is required")
+	}
+	removed, err := s.endpointRepo.DeleteWebPush(userID, strings.TrimSpace(endpoint))
 	if err != nil {
 		return err
 	}
+	if removed == 0 {
+		return errors.New("subscription not found")
+	}
+	return nil
+}
 
-	user.FCMToken = nil
-	return s.userRepo.Update(user)
+// GetWebhook returns the user's notification webhook (nil if not set)
+func (s *NotificationService) GetWebhook(userID string) (*models.NotificationWebhook, error) {
+	return s.endpointRepo.FindWebhook(userID)
 }
 
-// SendTaskReminder sends a reminder notification for an upcoming task
-func (s *NotificationService) SendTaskReminder(userID, taskTitle string, deadline time.Time) error {
-	if s.messagingClient == nil {
-		return fmt.Errorf("FCM not configured")
+// SetWebhook sets the webhook URL; a new signing secret is generated when the webhook is
+// created or rotateSecret is true. The secret is only returned by this call.
+func (s *NotificationService) SetWebhook(userID, rawURL string, rotateSecret bool) (*models.NotificationWebhook, string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if err := validateOutboundURL(context.Background(), rawURL); err != nil {
+		return nil, "", fmt.Errorf("invalid webhook url: %v", err)
 	}
 
-	user, err := s.userRepo.FindByID(userID)
+	webhook, err := s.endpointRepo.FindWebhook(userID)
 	if err != nil {
-		return err
+		return nil, "", err
+	}
+	if webhook == nil {
+		webhook = &models.NotificationWebhook{UserID: userID}
+		rotateSecret = true
+	}
+	webhook.URL = rawURL
+
+	secret := ""
+	if rotateSecret {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, "", err
+		}
+		secret = hex.EncodeToString(buf)
+		webhook.Secret = secret
+	}
+
+	if err := s.endpointRepo.SaveWebhook(webhook); err != nil {
+		return nil, "", err
+	}
+	return webhook, secret, nil
+}
+
+// DeleteWebhook removes the user's notification webhook
+func (s *NotificationService) DeleteWebhook(userID string) error {
+	return s.endpointRepo.DeleteWebhook(userID)
+}
+
+// GetPreferences returns the notification settings and per-type preferences of the user
+func (s *NotificationService) GetPreferences(userID string) (*NotificationPreferencesResponse, error) {
+	if s.preferenceService == nil {
+		return nil, errors.New("notification preferences not available")
+	}
+	preferences, err := s.preferenceService.GetPreferences(userID)
+	if err != nil {
+		return nil, err
 	}
+	preferences.AvailableChannels = s.dispatcher.AvailableChannels()
+	return preferences, nil
+}
 
-	if user.FCMToken == nil || *user.FCMToken == "" {
-		return fmt.Errorf("user has no FCM token registered")
+// UpdatePreferences updates time zone, quiet hours and per-type preferences
+func (s *NotificationService) UpdatePreferences(userID string, data UpdateNotificationPreferencesDTO) (*NotificationPreferencesResponse, error) {
+	if s.preferenceService == nil {
+		return nil, errors.New("notification preferences not available")
+	}
+	preferences, err := s.preferenceService.UpdatePreferences(userID, data)
+	if err != nil {
+		return nil, err
 	}
+	preferences.AvailableChannels = s.dispatcher.AvailableChannels()
+	return preferences, nil
+}
 
+// SendTaskReminder queues a reminder notification for an upcoming task.
+// offsetMinutes identifies which of the task's reminders this is (one notification per offset).
+func (s *NotificationService) SendTaskReminder(userID, taskID, taskTitle string, deadline time.Time, offsetMinutes int) error {
 	timeUntil := time.Until(deadline)
 	var timeStr string
-	if timeUntil.Hours() < 1 {
+	if timeUntil < time.Minute {
+		// Reminder saat deadline atau catch-up sesudahnya
+		timeStr = "sudah tiba"
+	} else if timeUntil.Hours() < 1 {
 		timeStr = fmt.Sprintf("%d menit lagi", int(timeUntil.Minutes()))
 	} else if timeUntil.Hours() < 24 {
 		timeStr = fmt.Sprintf("%d jam lagi", int(timeUntil.Hours()))
@@ -102,102 +244,98 @@ func (s *NotificationService) SendTaskReminder(userID, taskTitle string, deadlin
 		timeStr = fmt.Sprintf("%d hari lagi", int(timeUntil.Hours()/24))
 	}
 
-	message := &messaging.Message{
-		Token: *user.FCMToken,
-		Notification: &messaging.Notification{
-			Title: "⏰ Pengingat Tugas",
-			Body:  fmt.Sprintf("'%s' deadline %s!", taskTitle, timeStr),
-		},
-		Data: map[string]string{
-			"type":     "task_reminder",
-			"task_id":  taskTitle,
-			"deadline": deadline.Format(time.RFC3339),
-		},
-		Android: &messaging.AndroidConfig{
-			Priority: "high",
-			Notification: &messaging.AndroidNotification{
-				Sound: "default",
-				Color: "#FF6B35",
-			},
-		},
-		APNS: &messaging.APNSConfig{
-			Payload: &messaging.APNSPayload{
-				Aps: &messaging.Aps{
-					Sound: "default",
-				},
-			},
-		},
-	}
+	return s.enqueue(&models.NotificationOutbox{
+		UserID: userID,
+		Type:   models.NotificationTypeTaskReminder,
+		Title:  "⏰ Pengingat Tugas",
+		Body:   fmt.Sprintf("'%s' deadline %s!", taskTitle, timeStr),
+		Data: withNotificationActions(map[string]string{
+			"task_id":        taskID,
+			"title":          taskTitle,
+			"deadline":       deadline.Format(time.RFC3339),
+			"offset_minutes": fmt.Sprintf("%d", offsetMinutes),
+		}, notificationCategoryTaskReminder, snoozeActions(taskID)),
+		Priority: "high",
+		Color:    "#FF6B35",
+	}, notificationKey(models.NotificationTypeTaskReminder, userID, taskID, deadline.Format(time.RFC3339), fmt.Sprintf("%d", offsetMinutes)))
+}
 
-	_, err = s.messagingClient.Send(s.ctx, message)
-	if err != nil {
-		return fmt.Errorf("failed to send notification: %w", err)
+// SendSnoozedTaskReminder queues the reminder again once a snooze expires (one notification per snooze)
+func (s *NotificationService) SendSnoozedTaskReminder(userID, taskID, taskTitle string, deadline *time.Time, snoozedUntil time.Time) error {
+	body := fmt.Sprintf("Pengingat yang ditunda: '%s'", taskTitle)
+	data := map[string]string{
+		"task_id":       taskID,
+		"title":         taskTitle,
+		"snoozed_until": snoozedUntil.Format(time.RFC3339),
+	}
+	if deadline != nil {
+		data["deadline"] = deadline.Format(time.RFC3339)
+		if timeUntil := time.Until(*deadline); timeUntil <= 0 {
+			body = fmt.Sprintf("'%s' sudah lewat deadline!", taskTitle)
+		} else if timeUntil.Hours() < 1 {
+			body = fmt.Sprintf("'%s' deadline %d menit lagi!", taskTitle, int(timeUntil.Minutes()))
+		} else if timeUntil.Hours() < 24 {
+			body = fmt.Sprintf("'%s' deadline %d jam lagi!", taskTitle, int(timeUntil.Hours()))
+		} else {
+			body = fmt.Sprintf("'%s' deadline %d hari lagi!", taskTitle, int(timeUntil.Hours()/24))
+		}
 	}
 
-	log.Printf("✅ Task reminder sent to user %s for task '%s'", userID, taskTitle)
-	return nil
+	return s.enqueue(&models.NotificationOutbox{
+		UserID:   userID,
+		Type:     models.NotificationTypeTaskReminder,
+		Title:    "⏰ Pengingat Tugas",
+		Body:     body,
+		Data:     withNotificationActions(data, notificationCategoryTaskReminder, snoozeActions(taskID)),
+		Priority: "high",
+		Color:    "#FF6B35",
+	}, notificationKey(models.NotificationTypeTaskReminder, userID, taskID, "snooze", snoozedUntil.Format(time.RFC3339)))
 }
 
-// SendWeatherAlert sends a weather-related notification
-func (s *NotificationService) SendWeatherAlert(userID, city, condition string, temperature float64) error {
-	if s.messagingClient == nil {
-		return fmt.Errorf("FCM not configured")
-	}
-
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return err
+// SendTaskOverdue queues a follow-up for a task past its deadline.
+// count is the n-th overdue reminder for this deadline (one notification per count).
+func (s *NotificationService) SendTaskOverdue(userID, taskID, taskTitle string, deadline time.Time, count int) error {
+	days := int(time.Since(deadline).Hours() / 24)
+	late := "hari ini"
+	if days >= 1 {
+		late = fmt.Sprintf("%d hari", days)
 	}
 
-	if user.FCMToken == nil || *user.FCMToken == "" {
-		return fmt.Errorf("user has no FCM token registered")
-	}
+	return s.enqueue(&models.NotificationOutbox{
+		UserID: userID,
+		Type:   models.NotificationTypeTaskOverdue,
+		Title:  "⚠️ Tugas Terlambat",
+		Body:   fmt.Sprintf("'%s' sudah lewat deadline (%s). Segera selesaikan atau jadwalkan ulang.", taskTitle, late),
+		Data: withNotificationActions(map[string]string{
+			"task_id":  taskID,
+			"title":    taskTitle,
+			"deadline": deadline.Format(time.RFC3339),
+			"count":    fmt.Sprintf("%d", count),
+		}, notificationCategoryTaskReminder, snoozeActions(taskID)),
+		Priority: "high",
+		Color:    "#D0021B",
+	}, notificationKey(models.NotificationTypeTaskOverdue, userID, taskID, deadline.Format(time.RFC3339), fmt.Sprintf("%d", count)))
+}
 
-	message := &messaging.Message{
-		Token: *user.FCMToken,
-		Notification: &messaging.Notification{
-			Title: fmt.Sprintf("🌤️ Cuaca di %s", city),
-			Body:  fmt.Sprintf("%s, %.1f°C. %s", condition, temperature, getWeatherAdvice(condition)),
-		},
+// SendWeatherAlert queues a weather-related notification (at most once per user per day)
+func (s *NotificationService) SendWeatherAlert(userID, city, condition string, temperature float64) error {
+	return s.enqueue(&models.NotificationOutbox{
+		UserID: userID,
+		Type:   models.NotificationTypeWeatherAlert,
+		Title:  fmt.Sprintf("🌤️ Cuaca di %s", city),
+		Body:   fmt.Sprintf("%s, %.1f°C. %s", condition, temperature, getWeatherAdvice(condition)),
 		Data: map[string]string{
-			"type":        "weather_alert",
 			"city":        city,
 			"condition":   condition,
 			"temperature": fmt.Sprintf("%.1f", temperature),
 		},
-		Android: &messaging.AndroidConfig{
-			Priority: "normal",
-			Notification: &messaging.AndroidNotification{
-				Sound: "default",
-				Color: "#4A90E2",
-			},
-		},
-	}
-
-	_, err = s.messagingClient.Send(s.ctx, message)
-	if err != nil {
-		return fmt.Errorf("failed to send notification: %w", err)
-	}
-
-	log.Printf("✅ Weather alert sent to user %s for %s", userID, city)
-	return nil
+		Priority: "normal",
+		Color:    "#4A90E2",
+	}, notificationKey(models.NotificationTypeWeatherAlert, userID, time.Now().Format("2006-01-02")))
 }
 
-// SendHealthRecommendation sends health/productivity recommendation
+// SendHealthRecommendation queues a health/productivity recommendation (at most once per user per hour)
 func (s *NotificationService) SendHealthRecommendation(userID, recommendation string, workloadHours float64) error {
-	if s.messagingClient == nil {
-		return fmt.Errorf("FCM not configured")
-	}
-
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return err
-	}
-
-	if user.FCMToken == nil || *user.FCMToken == "" {
-		return fmt.Errorf("user has no FCM token registered")
-	}
-
 	var title string
 	var emoji string
 
@@ -212,32 +350,101 @@ func (s *NotificationService) SendHealthRecommendation(userID, recommendation st
 		emoji = "😊"
 	}
 
-	message := &messaging.Message{
-		Token: *user.FCMToken,
-		Notification: &messaging.Notification{
-			Title: title,
-			Body:  fmt.Sprintf("%s Anda sudah bekerja %.1f jam hari ini. %s", emoji, workloadHours, recommendation),
-		},
+	return s.enqueue(&models.NotificationOutbox{
+		UserID: userID,
+		Type:   models.NotificationTypeHealth,
+		Title:  title,
+		Body:   fmt.Sprintf("%s Anda sudah bekerja %.1f jam hari ini. %s", emoji, workloadHours, recommendation),
 		Data: map[string]string{
-			"type":           "health_recommendation",
 			"workload_hours": fmt.Sprintf("%.1f", workloadHours),
 		},
-		Android: &messaging.AndroidConfig{
-			Priority: "normal",
-			Notification: &messaging.AndroidNotification{
-				Sound: "default",
-				Color: "#50C878",
-			},
-		},
+		Priority: "normal",
+		Color:    "#50C878",
+	}, notificationKey(models.NotificationTypeHealth, userID, time.Now().Format("2006-01-02T15")))
+}
+
+// SendHolidayReminder queues a reminder N days before a personal holiday / anniversary
+func (s *NotificationService) SendHolidayReminder(userID, holidayName string, date time.Time, daysBefore, yearsSince int) error {
+	when := "hari ini"
+	if daysBefore == 1 {
+		when = "besok"
+	} else if daysBefore > 1 {
+		when = fmt.Sprintf("%d hari lagi", daysBefore)
 	}
 
-	_, err = s.messagingClient.Send(s.ctx, message)
-	if err != nil {
-		return fmt.Errorf("failed to send notification: %w", err)
+	name := holidayName
+	if yearsSince > 0 {
+		name = fmt.Sprintf("%s (ke-%d)", holidayName, yearsSince)
 	}
 
-	log.Printf("✅ Health recommendation sent to user %s (%.1fh workload)", userID, workloadHours)
-	return nil
+	return s.enqueue(&models.NotificationOutbox{
+		UserID: userID,
+		Type:   models.NotificationTypeHolidayReminder,
+		Title:  "🎉 Pengingat Hari Spesial",
+		Body:   fmt.Sprintf("%s jatuh %s, %s", name, when, date.Format("02 Jan 2006")),
+		Data: map[string]string{
+			"date":        date.Format("2006-01-02"),
+			"days_before": fmt.Sprintf("%d", daysBefore),
+		},
+		Priority: "normal",
+		Color:    "#FFB347",
+	}, notificationKey(models.NotificationTypeHolidayReminder, userID, holidayName, date.Format("2006-01-02"), fmt.Sprintf("%d", daysBefore)))
+}
+
+// SendDailyBriefing queues the morning agenda or end-of-day digest (once per user per local date)
+func (s *NotificationService) SendDailyBriefing(userID string, briefing *DailyBriefing) error {
+	notificationType := models.NotificationTypeDailyAgenda
+	color := "#F5A623"
+	if briefing.Kind == models.DailyBriefingDigest {
+		notificationType = models.NotificationTypeDailyDigest
+		color = "#7B68EE"
+	}
+
+	return s.enqueue(&models.NotificationOutbox{
+		UserID:   userID,
+		Type:     notificationType,
+		Title:    briefing.Title,
+		Body:     briefing.Body,
+		Data:     briefing.Data,
+		Priority: "normal",
+		Color:    color,
+	}, notificationKey(notificationType, userID, briefing.Date))
+}
+
+// SendLeaveUpdate queues a leave workflow notification (request, approval, rejection, cancellation)
+func (s *NotificationService) SendLeaveUpdate(userID, title, body string, data map[string]string) error {
+	return s.sendDataNotification(userID, models.NotificationTypeLeaveUpdate, title, body, data)
+}
+
+// SendTaskAssignment queues a notification when a shared task is assigned or unassigned
+func (s *NotificationService) SendTaskAssignment(userID, title, body string, data map[string]string) error {
+	return s.sendDataNotification(userID, models.NotificationTypeTaskAssignment, title, body, data)
+}
+
+// SendCommentMention queues a notification when a user is @mentioned in a task comment
+func (s *NotificationService) SendCommentMention(userID, title, body string, data map[string]string) error {
+	return s.sendDataNotification(userID, models.NotificationTypeCommentMention, title, body, data)
+}
+
+// sendDataNotification queues a high priority notification with a typed data payload
+func (s *NotificationService) sendDataNotification(userID, notificationType, title, body string, data map[string]string) error {
+	return s.enqueue(&models.NotificationOutbox{
+		UserID:   userID,
+		Type:     notificationType,
+		Title:    title,
+		Body:     body,
+		Data:     data,
+		Priority: "high",
+		Color:    "#6C63FF",
+	}, "")
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
 }
 
 // Helper function for weather advice
@@ -274,3 +481,21 @@ func findInString(s, substr string) bool {
 	}
 	return false
 }
+
+// DTOs
+
+type RegisterDeviceDTO struct {
+	FCMToken   string `json:"fcm_token"`
+	Platform   string `json:"platform"` // android, ios, web
+	AppVersion string `json:"app_version"`
+	DeviceName string `json:"device_name"`
+}
+
+type RegisterWebPushDTO struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+	UserAgent string `json:"user_agent"`
+}
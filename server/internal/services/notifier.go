@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+)
+
+// Notification payload notifikasi yang sama untuk semua channel
+type Notification struct {
+	ID       string // ID outbox, dipakai channel eksternal sebagai delivery ID
+	UserID   string
+	Type     string
+	Title    string
+	Body     string
+	Data     map[string]string
+	Priority string // high, normal
+	Color    string
+}
+
+// notificationCategoryTaskReminder kategori notifikasi dengan tombol tunda
+// (APNs category / action set di client)
+const notificationCategoryTaskReminder = "TASK_REMINDER"
+
+// NotificationAction tombol aksi pada notifikasi. Client memanggil Method + Path
+// (dengan token user) dan Body sebagai JSON tanpa membuka aplikasi.
+type NotificationAction struct {
+	ID     string            `json:"id"`
+	Title  string            `json:"title"`
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Body   map[string]string `json:"body,omitempty"`
+}
+
+// withNotificationActions menambahkan kategori & aksi ke data payload ("category", "actions" berisi JSON)
+func withNotificationActions(data map[string]string, category string, actions []NotificationAction) map[string]string {
+	encoded, err := json.Marshal(actions)
+	if err != nil {
+		return data
+	}
+	data["category"] = category
+	data["actions"] = string(encoded)
+	return data
+}
+
+// notificationActions membaca kembali aksi dari data payload
+func notificationActions(data map[string]string) []NotificationAction {
+	var actions []NotificationAction
+	if raw := data["actions"]; raw != "" {
+		json.Unmarshal([]byte(raw), &actions)
+	}
+	return actions
+}
+
+// snoozeActions tombol tunda untuk pengingat task
+func snoozeActions(taskID string) []NotificationAction {
+	path := fmt.Sprintf("/api/tasks/%s/snooze", taskID)
+	return []NotificationAction{
+		{ID: "snooze_10m", Title: "Tunda 10 menit", Method: "POST", Path: path, Body: map[string]string{"duration": string(models.SnoozeTenMinutes)}},
+		{ID: "snooze_1h", Title: "Tunda 1 jam", Method: "POST", Path: path, Body: map[string]string{"duration": string(models.SnoozeOneHour)}},
+		{ID: "snooze_tomorrow", Title: "Besok saja", Method: "POST", Path: path, Body: map[string]string{"duration": string(models.SnoozeTomorrow)}},
+	}
+}
+
+// Notifier satu channel pengiriman notifikasi (FCM, email, in-app, Web Push, webhook, ...).
+// Error yang dibungkus ErrNoDevices/ErrNotificationRejected dst. dianggap permanen
+// (tidak di-retry oleh outbox), error lain di-retry dengan backoff.
+type Notifier interface {
+	Channel() models.NotificationChannel
+	// Available false jika channel tidak dikonfigurasi di server ini (mis. kredensial kosong)
+	Available() bool
+	Send(ctx context.Context, notification Notification) error
+}
+
+// RecipientNotifier notifier yang butuh endpoint milik user (device, subscription, webhook).
+// Reaches false jika user belum punya endpoint, sehingga channel tidak dipilih untuknya.
+type RecipientNotifier interface {
+	Reaches(userID string) (bool, error)
+}
+
+// NotificationDispatcher memilih channel sesuai preferensi user dan meneruskan ke Notifier-nya
+type NotificationDispatcher struct {
+	mu                sync.RWMutex
+	notifiers         map[models.NotificationChannel]Notifier
+	preferenceService *NotificationPreferenceService
+}
+
+func NewNotificationDispatcher(preferenceService *NotificationPreferenceService, notifiers ...Notifier) *NotificationDispatcher {
+	d := &NotificationDispatcher{
+		notifiers:         make(map[models.NotificationChannel]Notifier),
+		preferenceService: preferenceService,
+	}
+	for _, n := range notifiers {
+		d.Register(n)
+	}
+	return d
+}
+
+// Register menambahkan (atau mengganti) notifier untuk channel-nya, mis. RecordingNotifier di test
+func (d *NotificationDispatcher) Register(notifier Notifier) {
+	if notifier == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.notifiers[notifier.Channel()] = notifier
+}
+
+// Notifier notifier terdaftar untuk channel (nil jika tidak ada)
+func (d *NotificationDispatcher) Notifier(channel models.NotificationChannel) Notifier {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.notifiers[channel]
+}
+
+// AvailableChannels channel yang aktif di server ini
+func (d *NotificationDispatcher) AvailableChannels() []models.NotificationChannel {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	channels := []models.NotificationChannel{}
+	for _, channel := range allNotificationChannels {
+		if n, ok := d.notifiers[channel]; ok && n.Available() {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// Route menentukan channel (yang tersedia dan menjangkau user) dan penundaan jam tenang untuk satu notifikasi
+func (d *NotificationDispatcher) Route(userID, notificationType string, now time.Time) (*NotificationDelivery, error) {
+	delivery := &NotificationDelivery{Enabled: true, Channels: defaultNotificationChannels}
+	if d.preferenceService != nil {
+		resolved, err := d.preferenceService.Resolve(userID, notificationType, now)
+		if err != nil {
+			return nil, err
+		}
+		delivery = resolved
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var channels []models.NotificationChannel
+	for _, channel := range delivery.Channels {
+		n, ok := d.notifiers[channel]
+		if !ok || !n.Available() {
+			continue
+		}
+		if recipient, ok := n.(RecipientNotifier); ok {
+			// Gagal cek endpoint: channel tetap dipilih, pengiriman di-retry oleh outbox
+			if reaches, err := recipient.Reaches(userID); err == nil && !reaches {
+				continue
+			}
+		}
+		channels = append(channels, channel)
+	}
+	return &NotificationDelivery{
+		Enabled:    delivery.Enabled,
+		Channels:   channels,
+		DeferUntil: delivery.DeferUntil,
+	}, nil
+}
+
+// Send mengirim lewat satu channel
+func (d *NotificationDispatcher) Send(ctx context.Context, channel models.NotificationChannel, notification Notification) error {
+	d.mu.RLock()
+	notifier, ok := d.notifiers[channel]
+	d.mu.RUnlock()
+
+	if !ok || !notifier.Available() {
+		return ErrNoNotificationChannel
+	}
+	return notifier.Send(ctx, notification)
+}
+
+// LogAvailableChannels mencatat channel aktif saat startup
+func (d *NotificationDispatcher) LogAvailableChannels() {
+	channels := d.AvailableChannels()
+	names := make([]string, 0, len(channels))
+	for _, c := range channels {
+		names = append(names, string(c))
+	}
+	if len(names) == 0 {
+		log.Println("⚠️ No notification channel available - notifications will not be delivered")
+		return
+	}
+	log.Printf("✅ Notification channels available: %s", strings.Join(names, ", "))
+}
+
+// allNotificationChannels urutan tampil channel
+var allNotificationChannels = []models.NotificationChannel{
+	models.NotificationChannelPush,
+	models.NotificationChannelEmail,
+	models.NotificationChannelInApp,
+	models.NotificationChannelWebPush,
+	models.NotificationChannelWebhook,
+}
+
+// RecordingNotifier sink in-memory untuk test/development: mencatat notifikasi tanpa mengirim.
+// SetError membuat Send gagal untuk menguji retry.
+type RecordingNotifier struct {
+	channel models.NotificationChannel
+	mu      sync.Mutex
+	sent    []Notification
+	err     error
+}
+
+func NewRecordingNotifier(channel models.NotificationChannel) *RecordingNotifier {
+	return &RecordingNotifier{channel: channel}
+}
+
+func (r *RecordingNotifier) Channel() models.NotificationChannel {
+	return r.channel
+}
+
+func (r *RecordingNotifier) Available() bool {
+	return true
+}
+
+func (r *RecordingNotifier) Send(ctx context.Context, notification Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, notification)
+	return nil
+}
+
+// Sent salinan notifikasi yang sudah tercatat
+func (r *RecordingNotifier) Sent() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification(nil), r.sent...)
+}
+
+// SetError membuat Send berikutnya mengembalikan err (nil = normal lagi)
+func (r *RecordingNotifier) SetError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+// Reset menghapus catatan notifikasi
+func (r *RecordingNotifier) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = nil
+	r.err = nil
+}
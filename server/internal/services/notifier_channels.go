@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// EmailNotifier channel email lewat EmailService (Resend)
+type EmailNotifier struct {
+	userRepo     *repository.UserRepository
+	emailService *EmailService
+}
+
+func NewEmailNotifier(userRepo *repository.UserRepository, emailService *EmailService) *EmailNotifier {
+	return &EmailNotifier{userRepo: userRepo, emailService: emailService}
+}
+
+func (n *EmailNotifier) Channel() models.NotificationChannel {
+	return models.NotificationChannelEmail
+}
+
+func (n *EmailNotifier) Available() bool {
+	return n.emailService != nil && n.emailService.IsConfigured()
+}
+
+func (n *EmailNotifier) Send(ctx context.Context, notification Notification) error {
+	user, err := n.userRepo.FindByID(notification.UserID)
+	if err != nil {
+		return err
+	}
+	return n.emailService.SendNotificationEmail(user.Email, notification.Title, notification.Body)
+}
+
+// InAppNotifier channel in-app: notifikasi masuk sebagai bot message (dan event real-time)
+type InAppNotifier struct {
+	botMessageService *BotMessageService
+}
+
+func NewInAppNotifier(botMessageService *BotMessageService) *InAppNotifier {
+	return &InAppNotifier{botMessageService: botMessageService}
+}
+
+func (n *InAppNotifier) Channel() models.NotificationChannel {
+	return models.NotificationChannelInApp
+}
+
+func (n *InAppNotifier) Available() bool {
+	return n.botMessageService != nil
+}
+
+func (n *InAppNotifier) Send(ctx context.Context, notification Notification) error {
+	metadata := map[string]interface{}{"notification_type": notification.Type}
+	for k, v := range notification.Data {
+		metadata[k] = v
+	}
+	_, err := n.botMessageService.SendMessage(notification.UserID, models.MessageTypeAlert, notification.Title, notification.Body, metadata)
+	return err
+}
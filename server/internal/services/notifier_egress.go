@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// errEgressBlocked URL milik user mengarah ke alamat internal (loopback, private, link-local, ...)
+var errEgressBlocked = errors.New("destination address is not allowed")
+
+// isPublicAddr false untuk alamat yang tidak boleh dihubungi atas nama user
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace CGNAT (RFC 6598), sering dipakai jaringan internal cloud
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// validateOutboundURL URL https yang semua alamat host-nya publik. Dicek saat URL disimpan;
+// koneksi tetap dicek ulang oleh newOutboundHTTPClient (DNS bisa berubah).
+func validateOutboundURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return errors.New("must be an https URL")
+	}
+
+	host := parsed.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !isPublicAddr(addr) {
+			return errEgressBlocked
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("cannot resolve host %s", host)
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return errEgressBlocked
+		}
+	}
+	return nil
+}
+
+// newOutboundHTTPClient HTTP client untuk URL milik user: alamat yang benar-benar di-dial
+// (setelah resolve DNS) harus publik, sehingga DNS rebinding tidak bisa mengarah ke jaringan internal
+func newOutboundHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddr(addrPort.Addr()) {
+				return errEgressBlocked
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// Tanpa proxy: pengecekan alamat harus terhadap host tujuan, bukan proxy
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          20,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// TestIsPublicAddr alamat internal tidak boleh dihubungi webhook / web push
+func TestIsPublicAddr(t *testing.T) {
+	testCases := []struct {
+		name     string
+		addr     string
+		expected bool
+	}{
+		{"Public IPv4", "93.184.216.34", true},
+		{"Public IPv6", "2606:2800:220:1:248:1893:25c8:1946", true},
+		{"Loopback", "127.0.0.1", false},
+		{"Loopback IPv6", "::1", false},
+		{"Private 10/8", "10.1.2.3", false},
+		{"Private 172.16/12", "172.20.0.1", false},
+		{"Private 192.168/16", "192.168.1.1", false},
+		{"Unique local IPv6", "fd00::1", false},
+		{"Link-local metadata", "169.254.169.254", false},
+		{"Link-local IPv6", "fe80::1", false},
+		{"Unspecified", "0.0.0.0", false},
+		{"Unspecified IPv6", "::", false},
+		{"Multicast", "224.0.0.1", false},
+		{"Shared address space", "100.64.0.1", false},
+		{"IPv4-mapped loopback", "::ffff:127.0.0.1", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := isPublicAddr(netip.MustParseAddr(tc.addr))
+			if got != tc.expected {
+				t.Errorf("Address: %s\nExpected public: %v, Got: %v", tc.addr, tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestValidateOutboundURL URL disimpan hanya jika https dan host-nya publik
+func TestValidateOutboundURL(t *testing.T) {
+	testCases := []struct {
+		name    string
+		url     string
+		allowed bool
+	}{
+		{"Public IP literal", "https://93.184.216.34/hook", true},
+		{"Plain http", "http://93.184.216.34/hook", false},
+		{"No host", "https:///hook", false},
+		{"Loopback literal", "https://127.0.0.1:8080/hook", false},
+		{"Metadata endpoint", "https://169.254.169.254/latest/meta-data", false},
+		{"IPv6 loopback", "https://[::1]/hook", false},
+		{"Localhost name", "https://localhost/hook", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateOutboundURL(context.Background(), tc.url)
+			if (err == nil) != tc.allowed {
+				t.Errorf("URL: %s\nExpected allowed: %v, Got error: %v", tc.url, tc.allowed, err)
+			}
+		})
+	}
+}
+
+// TestOutboundClientBlocksInternalDial koneksi ke alamat internal diblok saat dial,
+// walaupun URL lolos validasi saat disimpan (DNS rebinding)
+func TestOutboundClientBlocksInternalDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newOutboundHTTPClient(2 * time.Second)
+	resp, err := client.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("Expected dial to %s to be blocked", server.URL)
+	}
+	if !errors.Is(err, errEgressBlocked) {
+		t.Errorf("Expected errEgressBlocked, Got: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+	"google.golang.org/api/option"
+)
+
+// FCMNotifier channel push ke aplikasi mobile lewat Firebase Cloud Messaging
+type FCMNotifier struct {
+	deviceRepo      *repository.DeviceRepository
+	messagingClient *messaging.Client
+}
+
+// NewFCMNotifier menginisialisasi Firebase; tanpa kredensial notifier tetap dibuat tapi tidak Available
+func NewFCMNotifier(deviceRepo *repository.DeviceRepository, projectID, credentialsPath string) (*FCMNotifier, error) {
+	notifier := &FCMNotifier{deviceRepo: deviceRepo}
+
+	// Skip initialization if credentials not provided
+	if projectID == "" || credentialsPath == "" {
+		log.Println("⚠️ Firebase credentials not configured - push channel unavailable")
+		return notifier, nil
+	}
+
+	ctx := context.Background()
+
+	// Initialize Firebase App
+	opt := option.WithCredentialsFile(credentialsPath)
+	app, err := firebase.NewApp(ctx, &firebase.Config{
+		ProjectID: projectID,
+	}, opt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Firebase app: %w", err)
+	}
+
+	// Get Messaging client
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messaging client: %w", err)
+	}
+
+	log.Println("✅ Firebase Cloud Messaging initialized successfully")
+
+	notifier.messagingClient = client
+	return notifier, nil
+}
+
+func (n *FCMNotifier) Channel() models.NotificationChannel {
+	return models.NotificationChannelPush
+}
+
+func (n *FCMNotifier) Available() bool {
+	return n.messagingClient != nil
+}
+
+// Reaches true jika user punya minimal satu device terdaftar
+func (n *FCMNotifier) Reaches(userID string) (bool, error) {
+	tokens, err := n.deviceRepo.FindTokensByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+	return len(tokens) > 0, nil
+}
+
+// Send sends the message to every registered device of the user (multicast)
+// and prunes tokens that FCM reports as invalid
+func (n *FCMNotifier) Send(ctx context.Context, notification Notification) error {
+	if n.messagingClient == nil {
+		return ErrFCMNotConfigured
+	}
+
+	tokens, err := n.deviceRepo.FindTokensByUserID(notification.UserID)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return ErrNoDevices
+	}
+
+	message := buildMulticastMessage(notification)
+	message.Tokens = tokens
+	resp, err := n.messagingClient.SendEachForMulticast(ctx, message)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	var invalid []string
+	var firstErr error
+	for i, r := range resp.Responses {
+		if r.Success {
+			continue
+		}
+		if firstErr == nil {
+			firstErr = r.Error
+		}
+		// INVALID_ARGUMENT hanya berarti token rusak jika payload terbukti valid di perangkat lain
+		if messaging.IsRegistrationTokenNotRegistered(r.Error) ||
+			messaging.IsSenderIDMismatch(r.Error) ||
+			(messaging.IsInvalidArgument(r.Error) && resp.SuccessCount > 0) {
+			invalid = append(invalid, tokens[i])
+		}
+	}
+
+	if len(invalid) > 0 {
+		if err := n.deviceRepo.DeleteTokens(invalid); err != nil {
+			log.Printf("⚠️ Failed to prune invalid FCM tokens for user %s: %v", notification.UserID, err)
+		} else {
+			log.Printf("🧹 Pruned %d invalid FCM token(s) for user %s", len(invalid), notification.UserID)
+		}
+	}
+
+	if resp.SuccessCount == 0 {
+		if len(invalid) == len(tokens) {
+			return ErrNoDevices
+		}
+		return fmt.Errorf("failed to send notification: %w", firstErr)
+	}
+	return nil
+}
+
+// buildMulticastMessage menyusun payload FCM dari notifikasi
+func buildMulticastMessage(notification Notification) *messaging.MulticastMessage {
+	data := map[string]string{"type": notification.Type}
+	for k, v := range notification.Data {
+		data[k] = v
+	}
+
+	priority := notification.Priority
+	if priority == "" {
+		priority = "normal"
+	}
+
+	return &messaging.MulticastMessage{
+		Notification: &messaging.Notification{
+			Title: notification.Title,
+			Body:  notification.Body,
+		},
+		Data: data,
+		Android: &messaging.AndroidConfig{
+			Priority: priority,
+			Notification: &messaging.AndroidNotification{
+				Sound: "default",
+				Color: notification.Color,
+			},
+		},
+		APNS: &messaging.APNSConfig{
+			Payload: &messaging.APNSPayload{
+				Aps: &messaging.Aps{
+					Sound:    "default",
+					Category: data["category"], // tombol aksi (mis. tunda) didaftarkan client per kategori
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+)
+
+// recipientRecordingNotifier RecordingNotifier yang hanya menjangkau user tertentu (seperti FCM/Web Push)
+type recipientRecordingNotifier struct {
+	*RecordingNotifier
+	reaches map[string]bool
+	err     error
+}
+
+func (n *recipientRecordingNotifier) Reaches(userID string) (bool, error) {
+	return n.reaches[userID], n.err
+}
+
+// unavailableNotifier channel yang tidak dikonfigurasi di server
+type unavailableNotifier struct {
+	*RecordingNotifier
+}
+
+func (n *unavailableNotifier) Available() bool {
+	return false
+}
+
+// TestNotificationDispatcherRoute channel dipilih hanya jika terdaftar, tersedia dan menjangkau user
+func TestNotificationDispatcherRoute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		notifier Notifier
+		userID   string
+		expected []models.NotificationChannel
+	}{
+		{
+			name:     "No notifier registered",
+			userID:   "user-1",
+			expected: nil,
+		},
+		{
+			name:     "Registered channel",
+			notifier: NewRecordingNotifier(models.NotificationChannelPush),
+			userID:   "user-1",
+			expected: []models.NotificationChannel{models.NotificationChannelPush},
+		},
+		{
+			name:     "Channel not configured",
+			notifier: &unavailableNotifier{NewRecordingNotifier(models.NotificationChannelPush)},
+			userID:   "user-1",
+			expected: nil,
+		},
+		{
+			name: "User with device",
+			notifier: &recipientRecordingNotifier{
+				RecordingNotifier: NewRecordingNotifier(models.NotificationChannelPush),
+				reaches:           map[string]bool{"user-1": true},
+			},
+			userID:   "user-1",
+			expected: []models.NotificationChannel{models.NotificationChannelPush},
+		},
+		{
+			name: "User without device",
+			notifier: &recipientRecordingNotifier{
+				RecordingNotifier: NewRecordingNotifier(models.NotificationChannelPush),
+				reaches:           map[string]bool{"user-1": true},
+			},
+			userID:   "user-2",
+			expected: nil,
+		},
+		{
+			name: "Endpoint lookup failed keeps channel",
+			notifier: &recipientRecordingNotifier{
+				RecordingNotifier: NewRecordingNotifier(models.NotificationChannelPush),
+				err:               errors.New("database unavailable"),
+			},
+			userID:   "user-2",
+			expected: []models.NotificationChannel{models.NotificationChannelPush},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dispatcher := NewNotificationDispatcher(nil)
+			if tc.notifier != nil {
+				dispatcher.Register(tc.notifier)
+			}
+
+			route, err := dispatcher.Route(tc.userID, models.NotificationTypeHealth, time.Now())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !route.Enabled || !reflect.DeepEqual(route.Channels, tc.expected) {
+				t.Errorf("User: %s\nExpected channels: %v, Got: %v (enabled: %v)", tc.userID, tc.expected, route.Channels, route.Enabled)
+			}
+		})
+	}
+}
+
+// TestNotificationDispatcherSend notifikasi diteruskan ke notifier channel-nya saja
+func TestNotificationDispatcherSend(t *testing.T) {
+	push := NewRecordingNotifier(models.NotificationChannelPush)
+	email := NewRecordingNotifier(models.NotificationChannelEmail)
+	dispatcher := NewNotificationDispatcher(nil, push, email)
+	notification := Notification{ID: "outbox-1", UserID: "user-1", Type: models.NotificationTypeHealth, Title: "Halo"}
+
+	if err := dispatcher.Send(context.Background(), models.NotificationChannelEmail, notification); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(email.Sent()) != 1 || len(push.Sent()) != 0 {
+		t.Errorf("Expected only email to receive the notification, Got email: %d, push: %d", len(email.Sent()), len(push.Sent()))
+	}
+	if got := email.Sent()[0]; got.ID != notification.ID || got.UserID != notification.UserID {
+		t.Errorf("Expected notification %+v, Got: %+v", notification, got)
+	}
+
+	sendErr := errors.New("temporary failure")
+	push.SetError(sendErr)
+	if err := dispatcher.Send(context.Background(), models.NotificationChannelPush, notification); !errors.Is(err, sendErr) {
+		t.Errorf("Expected notifier error to be returned, Got: %v", err)
+	}
+
+	if err := dispatcher.Send(context.Background(), models.NotificationChannelWebhook, notification); !errors.Is(err, ErrNoNotificationChannel) {
+		t.Errorf("Expected ErrNoNotificationChannel for unregistered channel, Got: %v", err)
+	}
+
+	expected := []models.NotificationChannel{models.NotificationChannelPush, models.NotificationChannelEmail}
+	if got := dispatcher.AvailableChannels(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected available channels: %v, Got: %v", expected, got)
+	}
+}
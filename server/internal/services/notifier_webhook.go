@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// WebhookNotifier channel webhook: POST JSON ke URL milik user.
+// Header X-Workradar-Signature = "sha256=" + HMAC-SHA256(secret, timestamp + "." + body).
+type WebhookNotifier struct {
+	endpointRepo *repository.NotificationEndpointRepository
+	client       *http.Client
+}
+
+func NewWebhookNotifier(endpointRepo *repository.NotificationEndpointRepository) *WebhookNotifier {
+	client := newOutboundHTTPClient(10 * time.Second)
+	// Jangan ikuti redirect ke host lain
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &WebhookNotifier{
+		endpointRepo: endpointRepo,
+		client:       client,
+	}
+}
+
+func (n *WebhookNotifier) Channel() models.NotificationChannel {
+	return models.NotificationChannelWebhook
+}
+
+func (n *WebhookNotifier) Available() bool {
+	return true
+}
+
+// Reaches true jika user sudah mengatur webhook
+func (n *WebhookNotifier) Reaches(userID string) (bool, error) {
+	webhook, err := n.endpointRepo.FindWebhook(userID)
+	if err != nil {
+		return false, err
+	}
+	return webhook != nil, nil
+}
+
+func (n *WebhookNotifier) Send(ctx context.Context, notification Notification) error {
+	webhook, err := n.endpointRepo.FindWebhook(notification.UserID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil {
+		return fmt.Errorf("%w: webhook not configured", ErrNotificationRejected)
+	}
+
+	now := time.Now()
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         notification.ID,
+		"type":       notification.Type,
+		"title":      notification.Title,
+		"body":       notification.Body,
+		"data":       notification.Data,
+		"priority":   notification.Priority,
+		"created_at": now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotificationRejected, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Workradar-Webhook/1.0")
+	req.Header.Set("X-Workradar-Event", notification.Type)
+	req.Header.Set("X-Workradar-Delivery", notification.ID)
+	req.Header.Set("X-Workradar-Timestamp", timestamp)
+	req.Header.Set("X-Workradar-Signature", "sha256="+signWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errEgressBlocked) {
+			return fmt.Errorf("%w: %v", ErrNotificationRejected, err)
+		}
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	// 4xx (selain timeout/rate limit) tidak akan berhasil jika diulang
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: webhook returned status %d", ErrNotificationRejected, resp.StatusCode)
+	}
+	return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// signWebhookPayload HMAC-SHA256 hex atas "timestamp.body"
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
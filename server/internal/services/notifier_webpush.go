@@ -0,0 +1,320 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+const (
+	webPushTTL        = 24 * time.Hour // push service menyimpan pesan selama browser offline
+	webPushRecordSize = 4096
+)
+
+// WebPushNotifier channel Web Push (Push API browser) dengan autentikasi VAPID (RFC 8292)
+// dan enkripsi payload aes128gcm (RFC 8291)
+type WebPushNotifier struct {
+	endpointRepo *repository.NotificationEndpointRepository
+	privateKey   *ecdsa.PrivateKey
+	publicKey    []byte // uncompressed P-256 point, applicationServerKey untuk browser
+	subject      string // mailto: atau https: kontak pemilik server
+	client       *http.Client
+}
+
+// NewWebPushNotifier membaca private key VAPID (base64url, 32 byte).
+// Tanpa key notifier tetap dibuat tapi tidak Available.
+func NewWebPushNotifier(endpointRepo *repository.NotificationEndpointRepository, vapidPrivateKey, subject string) (*WebPushNotifier, error) {
+	notifier := &WebPushNotifier{
+		endpointRepo: endpointRepo,
+		subject:      subject,
+		client:       newOutboundHTTPClient(10 * time.Second),
+	}
+
+	if vapidPrivateKey == "" {
+		log.Println("⚠️ VAPID key not configured - web push channel unavailable")
+		return notifier, nil
+	}
+
+	raw, err := decodeBase64URL(vapidPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+
+	notifier.publicKey = key.PublicKey().Bytes()
+	notifier.privateKey = &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(notifier.publicKey[1:33]),
+			Y:     new(big.Int).SetBytes(notifier.publicKey[33:65]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+
+	log.Println("✅ Web Push (VAPID) initialized successfully")
+	return notifier, nil
+}
+
+func (n *WebPushNotifier) Channel() models.NotificationChannel {
+	return models.NotificationChannelWebPush
+}
+
+func (n *WebPushNotifier) Available() bool {
+	return n.privateKey != nil
+}
+
+// PublicKey applicationServerKey (base64url) untuk pushManager.subscribe di browser
+func (n *WebPushNotifier) PublicKey() string {
+	if n.privateKey == nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(n.publicKey)
+}
+
+// Reaches true jika user punya minimal satu subscription browser
+func (n *WebPushNotifier) Reaches(userID string) (bool, error) {
+	subscriptions, err := n.endpointRepo.FindWebPushByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+	return len(subscriptions) > 0, nil
+}
+
+// Send mengirim ke semua subscription browser user; subscription kadaluarsa (404/410) dihapus
+func (n *WebPushNotifier) Send(ctx context.Context, notification Notification) error {
+	subscriptions, err := n.endpointRepo.FindWebPushByUserID(notification.UserID)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return ErrNoDevices
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":    notification.ID,
+		"type":  notification.Type,
+		"title": notification.Title,
+		"body":  notification.Body,
+		"data":  notification.Data,
+		// Aksi dipakai langsung oleh service worker (showNotification actions)
+		"actions": notificationActions(notification.Data),
+	})
+	if err != nil {
+		return err
+	}
+
+	sent, expired := 0, 0
+	var firstErr error
+	for _, subscription := range subscriptions {
+		status, err := n.sendOne(ctx, subscription, payload, notification.Priority)
+		switch {
+		case err == nil:
+			sent++
+		case status == http.StatusNotFound || status == http.StatusGone:
+			expired++
+			if err := n.endpointRepo.DeleteWebPushByID(subscription.ID); err != nil {
+				log.Printf("⚠️ Failed to prune web push subscription for user %s: %v", notification.UserID, err)
+			}
+		default:
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if expired > 0 {
+		log.Printf("🧹 Pruned %d expired web push subscription(s) for user %s", expired, notification.UserID)
+	}
+	if sent == 0 {
+		if expired == len(subscriptions) {
+			return ErrNoDevices
+		}
+		return firstErr
+	}
+	return nil
+}
+
+// sendOne mengirim satu pesan terenkripsi ke push service browser
+func (n *WebPushNotifier) sendOne(ctx context.Context, subscription models.WebPushSubscription, payload []byte, priority string) (int, error) {
+	body, err := encryptWebPushPayload(subscription.P256dh, subscription.Auth, payload)
+	if err != nil {
+		// Key subscription rusak tidak akan pernah berhasil
+		return 0, fmt.Errorf("%w: %v", ErrNotificationRejected, err)
+	}
+
+	authorization, err := n.vapidAuthorization(subscription.Endpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrNotificationRejected, err)
+	}
+	urgency := "normal"
+	if priority == "high" {
+		urgency = "high"
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", fmt.Sprintf("%d", int(webPushTTL.Seconds())))
+	req.Header.Set("Urgency", urgency)
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errEgressBlocked) {
+			return 0, fmt.Errorf("%w: %v", ErrNotificationRejected, err)
+		}
+		return 0, fmt.Errorf("failed to send web push: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	err = fmt.Errorf("web push service returned status %d", resp.StatusCode)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		err = fmt.Errorf("%w: web push service returned status %d", ErrNotificationRejected, resp.StatusCode)
+	}
+	return resp.StatusCode, err
+}
+
+// vapidAuthorization header Authorization VAPID untuk origin push service
+func (n *WebPushNotifier) vapidAuthorization(endpoint string) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("%w: invalid web push endpoint", ErrNotificationRejected)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": parsed.Scheme + "://" + parsed.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": n.subject,
+	})
+	signed, err := token.SignedString(n.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+	return fmt.Sprintf("vapid t=%s, k=%s", signed, n.PublicKey()), nil
+}
+
+// encryptWebPushPayload mengenkripsi payload untuk satu subscription (RFC 8291, satu record aes128gcm)
+func encryptWebPushPayload(p256dh, auth string, payload []byte) ([]byte, error) {
+	receiverKey, err := decodeBase64URL(p256dh)
+	if err != nil {
+		return nil, errors.New("invalid p256dh key")
+	}
+	authSecret, err := decodeBase64URL(auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, errors.New("invalid auth secret")
+	}
+	receiverPublic, err := ecdh.P256().NewPublicKey(receiverKey)
+	if err != nil {
+		return nil, errors.New("invalid p256dh key")
+	}
+
+	// Key pair & salt sementara per pesan
+	senderPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return encryptWebPushRecord(receiverPublic, authSecret, senderPrivate, salt, payload)
+}
+
+// encryptWebPushRecord enkripsi aes128gcm dengan key pair pengirim & salt tertentu
+// (dipisah agar bisa diuji dengan test vector RFC 8291 Appendix A)
+func encryptWebPushRecord(receiverPublic *ecdh.PublicKey, authSecret []byte, senderPrivate *ecdh.PrivateKey, salt, payload []byte) ([]byte, error) {
+	receiverKey := receiverPublic.Bytes()
+	senderPublic := senderPrivate.PublicKey().Bytes()
+	sharedSecret, err := senderPrivate.ECDH(receiverPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	// IKM = HKDF(auth, ecdh_secret, "WebPush: info" || 0x00 || ua_public || as_public)
+	prkKey, err := hkdf.Extract(sha256.New, sharedSecret, authSecret)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(receiverKey) + string(senderPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0x02 = delimiter record terakhir (tanpa padding)
+	plaintext := append(append([]byte(nil), payload...), 0x02)
+	if len(plaintext)+gcm.Overhead() > webPushRecordSize {
+		return nil, errors.New("web push payload too large")
+	}
+
+	// Header: salt (16) || record size (4) || key id length (1) || key id (sender public key)
+	header := make([]byte, 0, 21+len(senderPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, webPushRecordSize)
+	header = append(header, byte(len(senderPublic)))
+	header = append(header, senderPublic...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// decodeBase64URL menerima base64url dengan atau tanpa padding (format PushSubscription.toJSON)
+func decodeBase64URL(value string) ([]byte, error) {
+	value = strings.TrimRight(strings.TrimSpace(value), "=")
+	return base64.RawURLEncoding.DecodeString(value)
+}
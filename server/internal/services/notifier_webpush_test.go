@@ -0,0 +1,90 @@
+package services
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"encoding/base64"
+	"testing"
+)
+
+// Test vector RFC 8291 Appendix A
+const (
+	rfc8291Plaintext     = "V2hlbiBJIGdyb3cgdXAsIEkgd2FudCB0byBiZSBhIHdhdGVybWVsb24"
+	rfc8291SenderPrivate = "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"
+	rfc8291ReceiverKey   = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	rfc8291AuthSecret    = "BTBZMqHH6r4Tts7J_aSIgg"
+	rfc8291Salt          = "DGv6ra1nlYgDCS1FRnbzlw"
+	rfc8291Body          = "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+)
+
+func mustDecodeBase64URL(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := decodeBase64URL(value)
+	if err != nil {
+		t.Fatalf("invalid base64url %q: %v", value, err)
+	}
+	return decoded
+}
+
+// TestEncryptWebPushRecordRFC8291 hasil enkripsi identik dengan contoh RFC 8291
+func TestEncryptWebPushRecordRFC8291(t *testing.T) {
+	senderPrivate, err := ecdh.P256().NewPrivateKey(mustDecodeBase64URL(t, rfc8291SenderPrivate))
+	if err != nil {
+		t.Fatalf("invalid sender private key: %v", err)
+	}
+	receiverPublic, err := ecdh.P256().NewPublicKey(mustDecodeBase64URL(t, rfc8291ReceiverKey))
+	if err != nil {
+		t.Fatalf("invalid receiver public key: %v", err)
+	}
+
+	body, err := encryptWebPushRecord(
+		receiverPublic,
+		mustDecodeBase64URL(t, rfc8291AuthSecret),
+		senderPrivate,
+		mustDecodeBase64URL(t, rfc8291Salt),
+		mustDecodeBase64URL(t, rfc8291Plaintext),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := base64.RawURLEncoding.EncodeToString(body); got != rfc8291Body {
+		t.Errorf("Expected body: %s\nGot: %s", rfc8291Body, got)
+	}
+}
+
+// TestEncryptWebPushPayload validasi key subscription dan format header
+func TestEncryptWebPushPayload(t *testing.T) {
+	testCases := []struct {
+		name    string
+		p256dh  string
+		auth    string
+		payload []byte
+		valid   bool
+	}{
+		{"Valid subscription", rfc8291ReceiverKey, rfc8291AuthSecret, []byte(`{"title":"hi"}`), true},
+		{"Padded base64", rfc8291ReceiverKey + "=", rfc8291AuthSecret + "==", []byte(`{}`), true},
+		{"Invalid p256dh", "not-a-key", rfc8291AuthSecret, []byte(`{}`), false},
+		{"Short auth secret", rfc8291ReceiverKey, "AAAA", []byte(`{}`), false},
+		{"Payload too large", rfc8291ReceiverKey, rfc8291AuthSecret, bytes.Repeat([]byte("a"), webPushRecordSize), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := encryptWebPushPayload(tc.p256dh, tc.auth, tc.payload)
+			if (err == nil) != tc.valid {
+				t.Fatalf("Expected valid: %v, Got error: %v", tc.valid, err)
+			}
+			if !tc.valid {
+				return
+			}
+			// salt (16) || rs (4) || idlen (1) || key id (65) || ciphertext + tag (16) + delimiter (1)
+			if expected := 21 + 65 + len(tc.payload) + 1 + 16; len(body) != expected {
+				t.Errorf("Expected body length: %d, Got: %d", expected, len(body))
+			}
+			if body[20] != 65 {
+				t.Errorf("Expected key id length 65, Got: %d", body[20])
+			}
+		})
+	}
+}
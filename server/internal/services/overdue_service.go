@@ -0,0 +1,458 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// Policy bawaan jika user belum mengatur: tidak ada tindakan (task lama tidak tiba-tiba
+// diingatkan / dipindah). Interval & batas dipakai saat user memilih renotify.
+const (
+	defaultOverdueRenotifyIntervalDays = 1
+	defaultOverdueMaxRenotify          = 3
+	maxOverdueRenotifyIntervalDays     = 30
+
+	// overduePolicyPageSize jumlah task overdue per halaman job malam
+	overduePolicyPageSize = 200
+)
+
+// OverdueService menerapkan overdue policy (ingatkan ulang, rollover, tandai terlewat)
+// untuk task yang lewat deadline dan belum selesai
+type OverdueService struct {
+	overdueRepo         *repository.OverdueRepository
+	taskRepo            *repository.TaskRepository
+	categoryRepo        *repository.CategoryRepository
+	notificationService *NotificationService
+	preferenceService   *NotificationPreferenceService
+	activityService     *TaskActivityService
+	broker              EventBroker
+}
+
+func NewOverdueService(
+	overdueRepo *repository.OverdueRepository,
+	taskRepo *repository.TaskRepository,
+	categoryRepo *repository.CategoryRepository,
+	notificationService *NotificationService,
+	preferenceService *NotificationPreferenceService,
+	activityService *TaskActivityService,
+	broker EventBroker,
+) *OverdueService {
+	return &OverdueService{
+		overdueRepo:         overdueRepo,
+		taskRepo:            taskRepo,
+		categoryRepo:        categoryRepo,
+		notificationService: notificationService,
+		preferenceService:   preferenceService,
+		activityService:     activityService,
+		broker:              broker,
+	}
+}
+
+// GetPolicies mendapatkan policy default user dan policy per kategori
+func (s *OverdueService) GetPolicies(userID string) (*OverduePoliciesResponse, error) {
+	policies, err := s.overdueRepo.FindPoliciesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &OverduePoliciesResponse{
+		Default:    defaultOverduePolicy(userID),
+		Categories: []models.OverduePolicy{},
+	}
+	for _, policy := range policies {
+		if policy.CategoryID == nil {
+			response.Default = policy
+			continue
+		}
+		response.Categories = append(response.Categories, policy)
+	}
+	return response, nil
+}
+
+// UpdatePolicy membuat/memperbarui policy default (category_id kosong) atau policy kategori
+func (s *OverdueService) UpdatePolicy(userID string, data UpdateOverduePolicyDTO) (*models.OverduePolicy, error) {
+	categoryID := data.CategoryID
+	if categoryID != nil && *categoryID == "" {
+		categoryID = nil
+	}
+	if categoryID != nil {
+		category, err := s.categoryRepo.FindByID(*categoryID)
+		if err != nil || category.UserID != userID {
+			return nil, errors.New("category not found")
+		}
+	}
+
+	policy, err := s.overdueRepo.FindPolicy(userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		p := defaultOverduePolicy(userID)
+		p.CategoryID = categoryID
+		policy = &p
+	}
+
+	if data.Action != nil {
+		if !data.Action.IsValid() {
+			return nil, errors.New("invalid action (use none, renotify, rollover or mark_missed)")
+		}
+		policy.Action = *data.Action
+	}
+	if data.RenotifyIntervalDays != nil {
+		if *data.RenotifyIntervalDays < 1 || *data.RenotifyIntervalDays > maxOverdueRenotifyIntervalDays {
+			return nil, fmt.Errorf("renotify_interval_days must be between 1 and %d", maxOverdueRenotifyIntervalDays)
+		}
+		policy.RenotifyIntervalDays = *data.RenotifyIntervalDays
+	}
+	if data.MaxRenotify != nil {
+		if *data.MaxRenotify < 0 {
+			return nil, errors.New("max_renotify must not be negative")
+		}
+		policy.MaxRenotify = *data.MaxRenotify
+	}
+
+	if err := s.overdueRepo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeletePolicy menghapus policy kategori (kembali ikut default) atau default user (kembali ke bawaan)
+func (s *OverdueService) DeletePolicy(userID string, categoryID *string) error {
+	if categoryID != nil && *categoryID == "" {
+		categoryID = nil
+	}
+	removed, err := s.overdueRepo.DeletePolicy(userID, categoryID)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return errors.New("policy not found")
+	}
+	return nil
+}
+
+// GetOverdueTasks task beban kerja user yang lewat deadline dan belum selesai,
+// beserta policy yang berlaku dan tindakan terakhir job overdue
+func (s *OverdueService) GetOverdueTasks(userID string, now time.Time) ([]OverdueTaskItem, error) {
+	tasks, err := s.taskRepo.FindOverdueWorkload(userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	latest, err := s.overdueRepo.FindLatestLogs(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Policy mengikuti pemilik task (kategori milik pemilik)
+	policiesByOwner := map[string]map[string]models.OverduePolicy{}
+	items := make([]OverdueTaskItem, 0, len(tasks))
+	for _, task := range tasks {
+		policies, ok := policiesByOwner[task.UserID]
+		if !ok {
+			list, err := s.overdueRepo.FindPoliciesByUserID(task.UserID)
+			if err != nil {
+				return nil, err
+			}
+			policies = groupOverduePolicies(list)[task.UserID]
+			policiesByOwner[task.UserID] = policies
+		}
+
+		policy := resolveOverduePolicy(task, policies)
+		item := OverdueTaskItem{
+			Task:           task,
+			OverdueMinutes: int(now.Sub(*task.Deadline).Minutes()),
+			Policy:         policy.Action,
+		}
+		if entry, ok := latest[task.ID]; ok {
+			item.LastAction = &entry
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetActionLog riwayat tindakan job overdue pada task milik user
+func (s *OverdueService) GetActionLog(userID string, limit int) ([]models.OverdueActionLog, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.overdueRepo.FindLogsByUserID(userID, limit)
+}
+
+// RunNightly menerapkan policy ke semua task overdue dan mencatat setiap tindakan
+func (s *OverdueService) RunNightly(ctx context.Context, now time.Time) (*OverdueRunResult, error) {
+	all, err := s.overdueRepo.FindAllPolicies()
+	if err != nil {
+		return nil, err
+	}
+	policies := groupOverduePolicies(all)
+
+	result := &OverdueRunResult{}
+	var afterDeadline time.Time
+	afterID := ""
+	for {
+		tasks, err := s.taskRepo.FindOverdueForPolicy(now, afterDeadline, afterID, overduePolicyPageSize)
+		if err != nil {
+			return result, err
+		}
+
+		for i := range tasks {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			task := &tasks[i]
+			policy := resolveOverduePolicy(*task, policies[task.UserID])
+
+			var err error
+			switch policy.Action {
+			case models.OverdueActionRenotify:
+				err = s.renotify(task, policy, now, result)
+			case models.OverdueActionRollover:
+				err = s.rollover(task, now, result)
+			case models.OverdueActionMarkMissed:
+				err = s.markMissed(task, now, result)
+			default:
+				continue
+			}
+			if err != nil {
+				result.Failed++
+				log.Printf("❌ Failed to apply overdue policy %s to task %s: %v", policy.Action, task.ID, err)
+			}
+		}
+
+		if len(tasks) < overduePolicyPageSize {
+			return result, nil
+		}
+		last := tasks[len(tasks)-1]
+		afterDeadline, afterID = *last.Deadline, last.ID
+	}
+}
+
+// renotify mengingatkan ulang penerima task jika interval sudah lewat dan batas belum tercapai
+func (s *OverdueService) renotify(task *models.Task, policy models.OverduePolicy, now time.Time, result *OverdueRunResult) error {
+	if policy.MaxRenotify > 0 && task.OverdueNotifyCount >= policy.MaxRenotify {
+		return nil
+	}
+	interval := time.Duration(policy.RenotifyIntervalDays) * 24 * time.Hour
+	// Toleransi 1 jam agar jadwal harian tidak bergeser karena durasi job
+	if task.OverdueNotifiedAt != nil && now.Sub(*task.OverdueNotifiedAt) < interval-time.Hour {
+		return nil
+	}
+	if s.notificationService == nil {
+		return nil
+	}
+
+	count := task.OverdueNotifyCount + 1
+	recipients := task.AssigneeIDs()
+	if len(recipients) == 0 {
+		recipients = []string{task.UserID}
+	}
+
+	// Klaim dulu: task yang baru selesai / deadline-nya diubah tidak diingatkan
+	updated, err := s.taskRepo.UpdateOverdueState(task.ID, *task.Deadline, map[string]interface{}{
+		"overdue_notified_at":  now,
+		"overdue_notify_count": count,
+	})
+	if err != nil {
+		return err
+	}
+	if !updated {
+		log.Printf("⏭️ Task %s changed since it was loaded, overdue reminder skipped", task.ID)
+		return nil
+	}
+	task.OverdueNotifiedAt = &now
+	task.OverdueNotifyCount = count
+
+	queued := 0
+	var lastErr error
+	for _, userID := range recipients {
+		if err := s.notificationService.SendTaskOverdue(userID, task.ID, task.Title, *task.Deadline, count); err != nil {
+			lastErr = err
+			continue
+		}
+		queued++
+	}
+	if queued == 0 && lastErr != nil {
+		return lastErr
+	}
+
+	note := fmt.Sprintf("pengingat ke-%d", count)
+	s.recordLog(task, models.OverdueActionRenotify, nil, &note)
+	result.Renotified++
+	return nil
+}
+
+// rollover memindahkan deadline ke hari ini (zona waktu pemilik) dengan jam yang sama
+func (s *OverdueService) rollover(task *models.Task, now time.Time, result *OverdueRunResult) error {
+	var before TaskSnapshot
+	if s.activityService != nil {
+		before = s.activityService.Snapshot(task)
+	}
+
+	previous := *task.Deadline
+	local := now.In(s.location(task.UserID))
+	deadline := previous.In(local.Location())
+	newDeadline := time.Date(local.Year(), local.Month(), local.Day(), deadline.Hour(), deadline.Minute(), 0, 0, local.Location())
+	if !newDeadline.After(now) {
+		// Jam asli sudah lewat hari ini: akhir hari
+		newDeadline = time.Date(local.Year(), local.Month(), local.Day(), 23, 59, 0, 0, local.Location())
+	}
+
+	updated, err := s.taskRepo.UpdateOverdueState(task.ID, previous, map[string]interface{}{
+		"deadline":             newDeadline,
+		"original_deadline":    nil,
+		"overdue_notified_at":  nil,
+		"overdue_notify_count": 0,
+	})
+	if err != nil {
+		return err
+	}
+	if !updated {
+		log.Printf("⏭️ Task %s changed since it was loaded, overdue rollover skipped", task.ID)
+		return nil
+	}
+	task.Deadline = &newDeadline
+	task.OriginalDeadline = nil
+	task.OverdueNotifiedAt = nil
+	task.OverdueNotifyCount = 0
+
+	if s.activityService != nil {
+		s.activityService.Record(task.UserID, models.TaskActivityRescheduled, task.ID, before, s.activityService.Snapshot(task), RequestMeta{})
+	}
+	publishTaskEvent(s.broker, RealtimeTaskUpdated, task)
+
+	task.Deadline = &previous // log mencatat deadline yang terlewat
+	s.recordLog(task, models.OverdueActionRollover, &newDeadline, nil)
+	task.Deadline = &newDeadline
+	result.RolledOver++
+	return nil
+}
+
+// markMissed menandai task terlewat; task tidak diproses job lagi sampai deadline diubah
+func (s *OverdueService) markMissed(task *models.Task, now time.Time, result *OverdueRunResult) error {
+	var before TaskSnapshot
+	if s.activityService != nil {
+		before = s.activityService.Snapshot(task)
+	}
+
+	updated, err := s.taskRepo.UpdateOverdueState(task.ID, *task.Deadline, map[string]interface{}{
+		"missed_at": now,
+	})
+	if err != nil {
+		return err
+	}
+	if !updated {
+		log.Printf("⏭️ Task %s changed since it was loaded, mark missed skipped", task.ID)
+		return nil
+	}
+	task.MissedAt = &now
+
+	if s.activityService != nil {
+		s.activityService.Record(task.UserID, models.TaskActivityMissed, task.ID, before, s.activityService.Snapshot(task), RequestMeta{})
+	}
+	publishTaskEvent(s.broker, RealtimeTaskUpdated, task)
+
+	s.recordLog(task, models.OverdueActionMarkMissed, nil, nil)
+	result.Missed++
+	return nil
+}
+
+func (s *OverdueService) recordLog(task *models.Task, action models.OverdueAction, newDeadline *time.Time, note *string) {
+	entry := &models.OverdueActionLog{
+		TaskID:      task.ID,
+		UserID:      task.UserID,
+		TaskTitle:   task.Title,
+		Action:      action,
+		Deadline:    *task.Deadline,
+		NewDeadline: newDeadline,
+		Note:        note,
+	}
+	if err := s.overdueRepo.CreateLog(entry); err != nil {
+		log.Printf("⚠️ Failed to record overdue action for task %s: %v", task.ID, err)
+	}
+}
+
+func (s *OverdueService) location(userID string) *time.Location {
+	if s.preferenceService == nil {
+		return notificationLocation(models.DefaultNotificationTimeZone)
+	}
+	return s.preferenceService.Location(userID)
+}
+
+func defaultOverduePolicy(userID string) models.OverduePolicy {
+	return models.OverduePolicy{
+		UserID:               userID,
+		Action:               models.OverdueActionNone,
+		RenotifyIntervalDays: defaultOverdueRenotifyIntervalDays,
+		MaxRenotify:          defaultOverdueMaxRenotify,
+	}
+}
+
+// groupOverduePolicies user -> kategori ("" = default user) -> policy
+func groupOverduePolicies(policies []models.OverduePolicy) map[string]map[string]models.OverduePolicy {
+	grouped := make(map[string]map[string]models.OverduePolicy)
+	for _, policy := range policies {
+		if grouped[policy.UserID] == nil {
+			grouped[policy.UserID] = make(map[string]models.OverduePolicy)
+		}
+		key := ""
+		if policy.CategoryID != nil {
+			key = *policy.CategoryID
+		}
+		grouped[policy.UserID][key] = policy
+	}
+	return grouped
+}
+
+// resolveOverduePolicy policy kategori task > default pemilik > bawaan
+func resolveOverduePolicy(task models.Task, policies map[string]models.OverduePolicy) models.OverduePolicy {
+	if task.CategoryID != nil {
+		if policy, ok := policies[*task.CategoryID]; ok {
+			return policy
+		}
+	}
+	if policy, ok := policies[""]; ok {
+		return policy
+	}
+	return defaultOverduePolicy(task.UserID)
+}
+
+// DTOs
+
+type OverduePoliciesResponse struct {
+	Default    models.OverduePolicy   `json:"default"`
+	Categories []models.OverduePolicy `json:"categories"`
+}
+
+type UpdateOverduePolicyDTO struct {
+	CategoryID           *string               `json:"category_id"` // kosong = policy default user
+	Action               *models.OverdueAction `json:"action"`
+	RenotifyIntervalDays *int                  `json:"renotify_interval_days"`
+	MaxRenotify          *int                  `json:"max_renotify"`
+}
+
+type OverdueTaskItem struct {
+	models.Task
+	OverdueMinutes int                      `json:"overdue_minutes"`
+	Policy         models.OverdueAction     `json:"policy"`
+	LastAction     *models.OverdueActionLog `json:"last_action,omitempty"`
+}
+
+type OverdueRunResult struct {
+	Renotified int `json:"renotified"`
+	RolledOver int `json:"rolled_over"`
+	Missed     int `json:"missed"`
+	Failed     int `json:"failed"`
+}
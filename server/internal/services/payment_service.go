@@ -21,6 +21,7 @@ type PaymentService struct {
 	userRepo          *repository.UserRepository
 	subService        *SubscriptionService
 	botMessageService *BotMessageService
+	broker            EventBroker
 	snapClient        snap.Client
 	apiClient         coreapi.Client
 }
@@ -30,6 +31,7 @@ func NewPaymentService(
 	userRepo *repository.UserRepository,
 	subService *SubscriptionService,
 	botMessageService *BotMessageService,
+	broker EventBroker,
 ) *PaymentService {
 	// Initialize Midtrans Snap Client
 	var s snap.Client
@@ -65,6 +67,7 @@ func NewPaymentService(
 		userRepo:          userRepo,
 		subService:        subService,
 		botMessageService: botMessageService,
+		broker:            broker,
 		snapClient:        s,
 		apiClient:         c,
 	}
@@ -217,6 +220,14 @@ func (s *PaymentService) HandleNotification(notificationPayload map[string]inter
 		return err
 	}
 
+	if s.broker != nil && status != trx.Status {
+		s.broker.Publish(trx.UserID, RealtimePaymentUpdated, map[string]interface{}{
+			"order_id":  orderID,
+			"plan_type": trx.PlanType,
+			"status":    status,
+		})
+	}
+
 	// 6. If Success (Settlement), Activate Subscription & Send Success Message
 	if status == models.TransactionStatusSettlement {
 		log.Printf("Payment success for order: %s. Upgrading user...", orderID)
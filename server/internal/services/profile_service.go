@@ -160,3 +160,28 @@ func (s *ProfileService) UpdateWorkHours(userID string, workDays map[string]inte
 	// Update user
 	return s.userRepo.UpdateWorkDays(userID, &workDaysStr)
 }
+
+// GetDeadlineShiftPolicy mendapatkan policy default pergeseran deadline user
+func (s *ProfileService) GetDeadlineShiftPolicy(userID string) (models.DeadlineShiftPolicy, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.New("user not found")
+		}
+		return "", err
+	}
+
+	if !user.DeadlineShiftPolicy.IsValid() {
+		return models.DeadlineShiftKeep, nil
+	}
+	return user.DeadlineShiftPolicy, nil
+}
+
+// UpdateDeadlineShiftPolicy mengupdate policy default pergeseran deadline user
+func (s *ProfileService) UpdateDeadlineShiftPolicy(userID string, policy models.DeadlineShiftPolicy) error {
+	if !policy.IsValid() {
+		return errors.New("invalid policy. Use 'keep', 'previous_work_day', or 'next_work_day'")
+	}
+
+	return s.userRepo.UpdateDeadlineShiftPolicy(userID, policy)
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RealtimeEventType jenis event yang dikirim lewat channel real-time
+type RealtimeEventType string
+
+const (
+	RealtimeTaskCreated    RealtimeEventType = "task.created"
+	RealtimeTaskUpdated    RealtimeEventType = "task.updated"
+	RealtimeTaskDeleted    RealtimeEventType = "task.deleted"
+	RealtimeMessageCreated RealtimeEventType = "message.created"
+	RealtimePaymentUpdated RealtimeEventType = "payment.updated"
+	RealtimeLeaveUpdated   RealtimeEventType = "leave.updated"
+)
+
+// RealtimeEvent event untuk satu user.
+// ID berformat "<epoch>-<seq>": epoch berbeda per proses server sehingga ID dari proses
+// sebelum restart (atau instance lain) tidak tertukar dengan seq proses ini.
+type RealtimeEvent struct {
+	ID        string            `json:"id"`
+	Type      RealtimeEventType `json:"type"`
+	Data      interface{}       `json:"data"`
+	CreatedAt time.Time         `json:"created_at"`
+
+	seq uint64
+}
+
+// RealtimeSubscription koneksi aktif satu client.
+// Events ditutup oleh broker jika client terlalu lambat; client cukup reconnect
+// dengan ID event terakhir untuk melanjutkan.
+type RealtimeSubscription struct {
+	Events <-chan RealtimeEvent
+	// Missed event setelah lastEventID yang harus dikirim ulang terlebih dahulu
+	Missed []RealtimeEvent
+	// Gap true jika lastEventID sudah tidak ada di buffer atau berasal dari proses lain
+	// (client sebaiknya refetch penuh)
+	Gap   bool
+	Close func()
+}
+
+// EventBroker pub/sub event per user. Implementasi in-memory hanya berlaku untuk satu
+// instance server; implementasi lain (mis. Redis) cukup memenuhi interface ini.
+type EventBroker interface {
+	Publish(userID string, eventType RealtimeEventType, data interface{})
+	Subscribe(userID, lastEventID string) *RealtimeSubscription
+}
+
+const (
+	realtimeReplaySize     = 100              // event terakhir per user yang disimpan untuk resume
+	realtimeReplayTTL      = 15 * time.Minute // event lebih lama tidak di-replay
+	realtimeSubscriberSize = 32               // buffer channel per koneksi
+)
+
+type realtimeSubscriber struct {
+	ch     chan RealtimeEvent
+	closed bool
+}
+
+type realtimeStream struct {
+	userID      string
+	history     []RealtimeEvent
+	subscribers map[*realtimeSubscriber]struct{}
+	// evictedUpTo ID terbesar yang sudah tidak ada di history
+	evictedUpTo uint64
+}
+
+// InMemoryEventBroker broker in-process dengan buffer replay per user
+type InMemoryEventBroker struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	streams map[string]*realtimeStream
+}
+
+func NewInMemoryEventBroker() *InMemoryEventBroker {
+	broker := &InMemoryEventBroker{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		streams: make(map[string]*realtimeStream),
+	}
+
+	// Start cleanup goroutine
+	go broker.cleanupIdleStreams()
+
+	return broker
+}
+
+// Publish mengirim event ke semua koneksi user dan menyimpannya untuk resume
+func (b *InMemoryEventBroker) Publish(userID string, eventType RealtimeEventType, data interface{}) {
+	if userID == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event := RealtimeEvent{
+		ID:        b.epoch + "-" + strconv.FormatUint(b.seq, 10),
+		Type:      eventType,
+		Data:      data,
+		CreatedAt: time.Now(),
+		seq:       b.seq,
+	}
+
+	stream := b.stream(userID)
+	stream.prune(event.CreatedAt)
+	stream.history = append(stream.history, event)
+	if len(stream.history) > realtimeReplaySize {
+		stream.evict(len(stream.history) - realtimeReplaySize)
+	}
+
+	for sub := range stream.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			// Client lambat: putuskan agar reconnect dan replay dari ID terakhir
+			b.closeSubscriber(stream, sub)
+		}
+	}
+}
+
+// Subscribe membuka koneksi untuk user; lastEventID kosong = mulai dari sekarang
+func (b *InMemoryEventBroker) Subscribe(userID, lastEventID string) *RealtimeSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stream := b.stream(userID)
+	stream.prune(time.Now())
+
+	sub := &realtimeSubscriber{ch: make(chan RealtimeEvent, realtimeSubscriberSize)}
+	stream.subscribers[sub] = struct{}{}
+
+	subscription := &RealtimeSubscription{
+		Events: sub.ch,
+		Close: func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.closeSubscriber(stream, sub)
+		},
+	}
+
+	if lastEventID != "" {
+		subscription.Missed, subscription.Gap = stream.eventsAfter(lastEventID, b.epoch, b.seq)
+	}
+
+	return subscription
+}
+
+func (b *InMemoryEventBroker) stream(userID string) *realtimeStream {
+	stream, ok := b.streams[userID]
+	if !ok {
+		// Event sebelum stream dibuat (atau sebelum restart) tidak bisa di-replay
+		stream = &realtimeStream{
+			userID:      userID,
+			subscribers: make(map[*realtimeSubscriber]struct{}),
+			evictedUpTo: b.seq,
+		}
+		b.streams[userID] = stream
+	}
+	return stream
+}
+
+// closeSubscriber dipanggil dengan b.mu terkunci
+func (b *InMemoryEventBroker) closeSubscriber(stream *realtimeStream, sub *realtimeSubscriber) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.ch)
+	delete(stream.subscribers, sub)
+
+	// Bersihkan user yang sudah tidak punya koneksi maupun event untuk di-replay
+	if len(stream.subscribers) == 0 && len(stream.history) == 0 {
+		delete(b.streams, stream.userID)
+	}
+}
+
+// cleanupIdleStreams menghapus user tanpa koneksi yang event-nya sudah kadaluarsa
+func (b *InMemoryEventBroker) cleanupIdleStreams() {
+	ticker := time.NewTicker(realtimeReplayTTL)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		b.mu.Lock()
+		now := time.Now()
+		for userID, stream := range b.streams {
+			stream.prune(now)
+			if len(stream.subscribers) == 0 && len(stream.history) == 0 {
+				delete(b.streams, userID)
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+// prune membuang event yang lebih tua dari realtimeReplayTTL
+func (s *realtimeStream) prune(now time.Time) {
+	cutoff := now.Add(-realtimeReplayTTL)
+	n := 0
+	for n < len(s.history) && s.history[n].CreatedAt.Before(cutoff) {
+		n++
+	}
+	s.evict(n)
+}
+
+// evict membuang n event tertua dari history
+func (s *realtimeStream) evict(n int) {
+	if n <= 0 {
+		return
+	}
+	s.evictedUpTo = s.history[n-1].seq
+	s.history = append([]RealtimeEvent(nil), s.history[n:]...)
+}
+
+// eventsAfter event setelah lastEventID; gap = true jika sebagian event sudah terbuang
+// atau ID tidak dikenal. ID dari epoch lain (proses server sebelum restart) tidak bisa
+// dibandingkan dengan seq proses ini: tidak ada replay, client cukup resync.
+func (s *realtimeStream) eventsAfter(lastEventID, epoch string, currentSeq uint64) ([]RealtimeEvent, bool) {
+	lastEpoch, rawSeq, found := strings.Cut(lastEventID, "-")
+	if !found || lastEpoch != epoch {
+		return nil, true
+	}
+	last, err := strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil || last > currentSeq {
+		return nil, true
+	}
+
+	var missed []RealtimeEvent
+	for _, event := range s.history {
+		if event.seq > last {
+			missed = append(missed, event)
+		}
+	}
+	return missed, last < s.evictedUpTo
+}
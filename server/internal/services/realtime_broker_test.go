@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+)
+
+// TestEventBrokerResume resume dari Last-Event-ID hanya untuk ID milik proses yang sama
+func TestEventBrokerResume(t *testing.T) {
+	broker := NewInMemoryEventBroker()
+	live := broker.Subscribe("user-1", "")
+	defer live.Close()
+
+	broker.Publish("user-1", RealtimeTaskCreated, nil)
+	broker.Publish("user-1", RealtimeTaskUpdated, nil)
+	first := <-live.Events
+	second := <-live.Events
+
+	testCases := []struct {
+		name        string
+		lastEventID string
+		missed      int
+		gap         bool
+	}{
+		{"Resume after first event", first.ID, 1, false},
+		{"Resume after latest event", second.ID, 0, false},
+		{"ID from previous server process", "previous-1", 0, true},
+		{"Legacy numeric ID", "1", 0, true},
+		{"Sequence ahead of this process", broker.epoch + "-999", 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			subscription := broker.Subscribe("user-1", tc.lastEventID)
+			defer subscription.Close()
+
+			if len(subscription.Missed) != tc.missed || subscription.Gap != tc.gap {
+				t.Errorf("Last-Event-ID: %q\nExpected missed: %d gap: %v, Got missed: %d gap: %v",
+					tc.lastEventID, tc.missed, tc.gap, len(subscription.Missed), subscription.Gap)
+			}
+		})
+	}
+}
@@ -0,0 +1,475 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+	"github.com/workradar/server/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// ReportService membuat laporan produktivitas mingguan/bulanan
+type ReportService struct {
+	reportRepo        *repository.ReportRepository
+	taskRepo          *repository.TaskRepository
+	userRepo          *repository.UserRepository
+	holidayService    *HolidayService
+	workloadService   *WorkloadService
+	botMessageService *BotMessageService
+	emailService      *EmailService
+}
+
+func NewReportService(
+	reportRepo *repository.ReportRepository,
+	taskRepo *repository.TaskRepository,
+	userRepo *repository.UserRepository,
+	holidayService *HolidayService,
+	workloadService *WorkloadService,
+	botMessageService *BotMessageService,
+	emailService *EmailService,
+) *ReportService {
+	return &ReportService{
+		reportRepo:        reportRepo,
+		taskRepo:          taskRepo,
+		userRepo:          userRepo,
+		holidayService:    holidayService,
+		workloadService:   workloadService,
+		botMessageService: botMessageService,
+		emailService:      emailService,
+	}
+}
+
+// ReportPeriodRange menghitung awal & akhir periode yang memuat tanggal ref
+func ReportPeriodRange(period models.ReportPeriod, ref time.Time) (time.Time, time.Time) {
+	day := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, ref.Location())
+
+	if period == models.ReportPeriodMonthly {
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 1, 0).Add(-time.Second)
+	}
+
+	// Minggu dimulai hari Senin
+	weekday := int(day.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	start := day.AddDate(0, 0, -(weekday - 1))
+	return start, start.AddDate(0, 0, 7).Add(-time.Second)
+}
+
+// PreviousReportPeriodRange periode lengkap terakhir sebelum tanggal ref
+func PreviousReportPeriodRange(period models.ReportPeriod, ref time.Time) (time.Time, time.Time) {
+	start, _ := ReportPeriodRange(period, ref)
+	return ReportPeriodRange(period, start.Add(-time.Second))
+}
+
+// GenerateReport menghitung statistik, me-render HTML & PDF, lalu menyimpan report
+func (s *ReportService) GenerateReport(userID string, period models.ReportPeriod, start, end time.Time) (*models.ProductivityReport, error) {
+	if period != models.ReportPeriodWeekly && period != models.ReportPeriodMonthly {
+		return nil, errors.New("invalid period. Use 'weekly' or 'monthly'")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	stats, err := s.calculateStats(user, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ProductivityReport{
+		UserID:      userID,
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Stats:       *stats,
+	}
+
+	html, err := s.renderHTML(user, report)
+	if err != nil {
+		return nil, err
+	}
+	report.HTML = html
+	report.PDF = s.renderPDF(user, report)
+
+	if err := s.reportRepo.Save(report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GenerateAndDeliver membuat report lalu mengirimkannya via bot message dan email
+func (s *ReportService) GenerateAndDeliver(userID string, period models.ReportPeriod, start, end time.Time) (*models.ProductivityReport, error) {
+	report, err := s.GenerateReport(userID, period, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	setting, err := s.reportRepo.FindSetting(userID)
+	if err != nil {
+		return report, err
+	}
+
+	s.deliver(report, setting.EmailEnabled)
+	return report, nil
+}
+
+// deliver mengirim bot message berisi link download dan (opsional) email
+func (s *ReportService) deliver(report *models.ProductivityReport, sendEmail bool) {
+	title := fmt.Sprintf("Laporan Produktivitas %s 📊", periodLabel(report.Period))
+	content := fmt.Sprintf("Laporanmu untuk %s sudah siap!\n\n"+
+		"✅ Selesai: %d dari %d tugas baru\n"+
+		"⏱️ Tepat waktu: %.0f%%\n"+
+		"🌙 Lembur: %.1f jam, akhir pekan: %.1f jam\n\n"+
+		"Unduh laporan lengkap dalam format PDF.",
+		formatPeriodRange(report.PeriodStart, report.PeriodEnd),
+		report.Stats.TasksCompleted, report.Stats.TasksCreated,
+		report.Stats.OnTimeRate,
+		report.Stats.OvertimeHours, report.Stats.WeekendHours,
+	)
+	metadata := map[string]interface{}{
+		"report_id":    report.ID,
+		"period":       report.Period,
+		"download_url": fmt.Sprintf("/api/reports/%s/download?format=pdf", report.ID),
+		"html_url":     fmt.Sprintf("/api/reports/%s/download?format=html", report.ID),
+	}
+
+	if s.botMessageService != nil {
+		if _, err := s.botMessageService.SendMessage(report.UserID, models.MessageTypeReport, title, content, metadata); err != nil {
+			log.Printf("⚠️ Failed to store report bot message for user %s: %v", report.UserID, err)
+		}
+	}
+
+	if !sendEmail || s.emailService == nil {
+		return
+	}
+
+	user, err := s.userRepo.FindByID(report.UserID)
+	if err != nil {
+		log.Printf("⚠️ Failed to load user %s for report email: %v", report.UserID, err)
+		return
+	}
+
+	subject := fmt.Sprintf("Workradar - %s (%s)", title, formatPeriodRange(report.PeriodStart, report.PeriodEnd))
+	if err := s.emailService.SendProductivityReport(user.Email, subject, report.HTML, ReportFilename(report, "pdf"), report.PDF); err != nil {
+		log.Printf("⚠️ Failed to email report to user %s: %v", report.UserID, err)
+	}
+}
+
+// GetReports mendapatkan daftar report user
+func (s *ReportService) GetReports(userID string, period *models.ReportPeriod) ([]models.ProductivityReport, error) {
+	return s.reportRepo.FindByUserID(userID, period)
+}
+
+// GetReport mendapatkan report milik user
+func (s *ReportService) GetReport(userID, reportID string) (*models.ProductivityReport, error) {
+	report, err := s.reportRepo.FindByID(reportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report not found")
+		}
+		return nil, err
+	}
+
+	if report.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return report, nil
+}
+
+// GetSetting mendapatkan setting opt-in laporan user
+func (s *ReportService) GetSetting(userID string) (*models.ReportSetting, error) {
+	return s.reportRepo.FindSetting(userID)
+}
+
+// UpdateSetting memperbarui setting opt-in laporan user
+func (s *ReportService) UpdateSetting(userID string, data UpdateReportSettingDTO) (*models.ReportSetting, error) {
+	setting, err := s.reportRepo.FindSetting(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.WeeklyEnabled != nil {
+		setting.WeeklyEnabled = *data.WeeklyEnabled
+	}
+	if data.MonthlyEnabled != nil {
+		setting.MonthlyEnabled = *data.MonthlyEnabled
+	}
+	if data.EmailEnabled != nil {
+		setting.EmailEnabled = *data.EmailEnabled
+	}
+
+	if err := s.reportRepo.SaveSetting(setting); err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// RunScheduledReports membuat report periode sebelumnya untuk semua user yang opt-in.
+// Report yang sudah pernah dibuat untuk periode tersebut dilewati.
+func (s *ReportService) RunScheduledReports(ctx context.Context, period models.ReportPeriod, now time.Time) int {
+	settings, err := s.reportRepo.FindSubscribedSettings(period)
+	if err != nil {
+		log.Printf("❌ Failed to fetch report subscriptions: %v", err)
+		return 0
+	}
+
+	start, end := PreviousReportPeriodRange(period, now)
+	generated := 0
+
+	for _, setting := range settings {
+		if ctx.Err() != nil {
+			break
+		}
+		exists, err := s.reportRepo.ExistsForPeriod(setting.UserID, period, start)
+		if err != nil || exists {
+			continue
+		}
+
+		if _, err := s.GenerateAndDeliver(setting.UserID, period, start, end); err != nil {
+			log.Printf("❌ Failed to generate %s report for user %s: %v", period, setting.UserID, err)
+			continue
+		}
+		generated++
+	}
+
+	return generated
+}
+
+// calculateStats menghitung angka produktivitas dalam rentang tanggal
+func (s *ReportService) calculateStats(user *models.User, start, end time.Time) (*models.ProductivityStats, error) {
+	created, err := s.taskRepo.FindCreatedByUserIDAndDateRange(user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := s.taskRepo.FindCompletedByUserIDAndDateRange(user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.ProductivityStats{
+		TasksCreated:   len(created),
+		TasksCompleted: len(completed),
+	}
+
+	categories := map[string]*models.CategoryStat{}
+	categoryFor := func(task models.Task) *models.CategoryStat {
+		name := "Tanpa Kategori"
+		if task.Category != nil && task.Category.Name != "" {
+			name = task.Category.Name
+		}
+		if _, ok := categories[name]; !ok {
+			categories[name] = &models.CategoryStat{CategoryName: name}
+		}
+		return categories[name]
+	}
+
+	for _, task := range created {
+		categoryFor(task).Created++
+	}
+
+	totalLateHours := 0.0
+	lateCount := 0
+	for _, task := range completed {
+		categoryFor(task).Completed++
+
+		if task.Deadline == nil || task.CompletedAt == nil {
+			continue
+		}
+		stats.CompletedWithDue++
+		if !task.CompletedAt.After(*task.Deadline) {
+			stats.CompletedOnTime++
+		} else {
+			totalLateHours += task.CompletedAt.Sub(*task.Deadline).Hours()
+			lateCount++
+		}
+	}
+
+	if stats.CompletedWithDue > 0 {
+		stats.OnTimeRate = float64(stats.CompletedOnTime) / float64(stats.CompletedWithDue) * 100
+	}
+	if lateCount > 0 {
+		stats.AverageLatenessHour = totalLateHours / float64(lateCount)
+	}
+
+	// Jam lembur & akhir pekan memakai perhitungan multiplier workload
+	var holidays []time.Time
+	if s.holidayService != nil {
+		if list, err := s.holidayService.GetHolidaysByDateRange(user.ID, start, end); err == nil {
+			for _, h := range list {
+				holidays = append(holidays, h.Date)
+			}
+		}
+	}
+	workload, err := s.workloadService.CalculateWorkloadWithMultipliers(user.ID, start, end, ParseWorkDaysConfig(user.WorkDays), holidays)
+	if err == nil {
+		stats.OvertimeHours = workload.OvertimeHours
+		stats.WeekendHours = workload.WeekendHours
+	}
+
+	for _, c := range categories {
+		stats.CategoryBreakdown = append(stats.CategoryBreakdown, *c)
+	}
+	sort.Slice(stats.CategoryBreakdown, func(i, j int) bool {
+		return stats.CategoryBreakdown[i].Created+stats.CategoryBreakdown[i].Completed >
+			stats.CategoryBreakdown[j].Created+stats.CategoryBreakdown[j].Completed
+	})
+
+	return stats, nil
+}
+
+const productivityReportTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="margin: 0; padding: 0; font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; background-color: #f5f5f5;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="background-color: #f5f5f5; padding: 40px 0;">
+        <tr>
+            <td align="center">
+                <table width="600" cellpadding="0" cellspacing="0" style="background-color: #ffffff; border-radius: 16px; box-shadow: 0 4px 20px rgba(0,0,0,0.1);">
+                    <tr>
+                        <td style="background: linear-gradient(135deg, #6366F1 0%, #8B5CF6 100%); padding: 40px; border-radius: 16px 16px 0 0; text-align: center;">
+                            <h1 style="color: #ffffff; margin: 0; font-size: 28px;">📊 Laporan Produktivitas {{.PeriodLabel}}</h1>
+                            <p style="color: rgba(255,255,255,0.9); margin: 10px 0 0 0; font-size: 14px;">{{.Range}}</p>
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="padding: 40px;">
+                            <h2 style="color: #1f2937; margin: 0 0 20px 0;">Halo, {{.UserName}}!</h2>
+                            <table width="100%" cellpadding="8" cellspacing="0" style="color: #374151; font-size: 14px; border-collapse: collapse;">
+                                <tr><td>Tugas dibuat</td><td align="right"><strong>{{.Stats.TasksCreated}}</strong></td></tr>
+                                <tr><td>Tugas selesai</td><td align="right"><strong>{{.Stats.TasksCompleted}}</strong></td></tr>
+                                <tr><td>Tepat waktu</td><td align="right"><strong>{{printf "%.0f" .Stats.OnTimeRate}}%</strong> ({{.Stats.CompletedOnTime}}/{{.Stats.CompletedWithDue}})</td></tr>
+                                <tr><td>Rata-rata keterlambatan</td><td align="right"><strong>{{printf "%.1f" .Stats.AverageLatenessHour}} jam</strong></td></tr>
+                                <tr><td>Jam lembur</td><td align="right"><strong>{{printf "%.1f" .Stats.OvertimeHours}} jam</strong></td></tr>
+                                <tr><td>Jam akhir pekan/libur</td><td align="right"><strong>{{printf "%.1f" .Stats.WeekendHours}} jam</strong></td></tr>
+                            </table>
+                            {{if .Stats.CategoryBreakdown}}
+                            <h3 style="color: #1f2937; margin: 30px 0 10px 0;">Per Kategori</h3>
+                            <table width="100%" cellpadding="8" cellspacing="0" style="color: #374151; font-size: 14px; border-collapse: collapse;">
+                                <tr style="background-color: #f9fafb;"><th align="left">Kategori</th><th align="right">Dibuat</th><th align="right">Selesai</th></tr>
+                                {{range .Stats.CategoryBreakdown}}
+                                <tr><td>{{.CategoryName}}</td><td align="right">{{.Created}}</td><td align="right">{{.Completed}}</td></tr>
+                                {{end}}
+                            </table>
+                            {{end}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="background-color: #f9fafb; padding: 30px; border-radius: 0 0 16px 16px; text-align: center;">
+                            <p style="color: #9ca3af; font-size: 12px; margin: 0 0 10px 0;">
+                                Laporan ini dibuat otomatis oleh Workradar. Atur langganan laporan di menu profil.
+                            </p>
+                            <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                                © 2026 Workradar. All rights reserved.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`
+
+// renderHTML me-render report ke HTML (dipakai untuk email & download)
+func (s *ReportService) renderHTML(user *models.User, report *models.ProductivityReport) (string, error) {
+	tmpl, err := template.New("productivity_report").Parse(productivityReportTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse report template: %w", err)
+	}
+
+	var body bytes.Buffer
+	data := struct {
+		UserName    string
+		PeriodLabel string
+		Range       string
+		Stats       models.ProductivityStats
+	}{
+		UserName:    user.Username,
+		PeriodLabel: periodLabel(report.Period),
+		Range:       formatPeriodRange(report.PeriodStart, report.PeriodEnd),
+		Stats:       report.Stats,
+	}
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("failed to execute report template: %w", err)
+	}
+
+	return body.String(), nil
+}
+
+// renderPDF me-render report ke PDF sederhana
+func (s *ReportService) renderPDF(user *models.User, report *models.ProductivityReport) []byte {
+	stats := report.Stats
+	pdf := utils.NewSimplePDF()
+
+	pdf.AddLine("Laporan Produktivitas "+periodLabel(report.Period), 20, true)
+	pdf.AddLine(formatPeriodRange(report.PeriodStart, report.PeriodEnd)+" - "+user.Username, 11, false)
+	pdf.AddSpace(12)
+
+	pdf.AddLine("Ringkasan", 14, true)
+	pdf.AddLine(fmt.Sprintf("Tugas dibuat: %d", stats.TasksCreated), 11, false)
+	pdf.AddLine(fmt.Sprintf("Tugas selesai: %d", stats.TasksCompleted), 11, false)
+	pdf.AddLine(fmt.Sprintf("Tepat waktu: %.0f%% (%d/%d)", stats.OnTimeRate, stats.CompletedOnTime, stats.CompletedWithDue), 11, false)
+	pdf.AddLine(fmt.Sprintf("Rata-rata keterlambatan: %.1f jam", stats.AverageLatenessHour), 11, false)
+	pdf.AddLine(fmt.Sprintf("Jam lembur: %.1f jam", stats.OvertimeHours), 11, false)
+	pdf.AddLine(fmt.Sprintf("Jam akhir pekan/libur: %.1f jam", stats.WeekendHours), 11, false)
+
+	if len(stats.CategoryBreakdown) > 0 {
+		pdf.AddSpace(12)
+		pdf.AddLine("Per Kategori (dibuat / selesai)", 14, true)
+		for _, c := range stats.CategoryBreakdown {
+			pdf.AddLine(fmt.Sprintf("%s: %d / %d", c.CategoryName, c.Created, c.Completed), 11, false)
+		}
+	}
+
+	pdf.AddSpace(20)
+	pdf.AddLine("Dibuat otomatis oleh Workradar pada "+time.Now().Format("02 Jan 2006 15:04"), 9, false)
+
+	return pdf.Bytes()
+}
+
+func periodLabel(period models.ReportPeriod) string {
+	if period == models.ReportPeriodMonthly {
+		return "Bulanan"
+	}
+	return "Mingguan"
+}
+
+func formatPeriodRange(start, end time.Time) string {
+	return start.Format("02 Jan 2006") + " - " + end.Format("02 Jan 2006")
+}
+
+// ReportFilename nama file download untuk format "pdf" atau "html"
+func ReportFilename(report *models.ProductivityReport, format string) string {
+	return fmt.Sprintf("workradar-%s-%s.%s", report.Period, report.PeriodStart.Format("2006-01-02"), format)
+}
+
+// DTOs
+
+type UpdateReportSettingDTO struct {
+	WeeklyEnabled  *bool `json:"weekly_enabled"`
+	MonthlyEnabled *bool `json:"monthly_enabled"`
+	EmailEnabled   *bool `json:"email_enabled"`
+}
@@ -1,8 +1,9 @@
 package services
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"sync"
 	"time"
 
 	"github.com/workradar/server/internal/models"
@@ -15,10 +16,18 @@ type SchedulerService struct {
 	db                  *gorm.DB
 	userRepo            *repository.UserRepository
 	taskRepo            *repository.TaskRepository
+	reminderRepo        *repository.TaskReminderRepository
+	briefingRepo        *repository.DailyBriefingRepository
 	notificationService *NotificationService
+	preferenceService   *NotificationPreferenceService
+	briefingService     *DailyBriefingService
+	overdueService      *OverdueService
+	snoozeService       *TaskSnoozeService
 	weatherService      *WeatherService
-	stopChan            chan struct{}
-	wg                  sync.WaitGroup
+	reportService       *ReportService
+	holidayService      *HolidayService
+	leaveService        *LeaveService
+	registry            *JobRegistry
 }
 
 // NewSchedulerService creates a new scheduler service
@@ -26,85 +35,181 @@ func NewSchedulerService(
 	db *gorm.DB,
 	userRepo *repository.UserRepository,
 	taskRepo *repository.TaskRepository,
+	reminderRepo *repository.TaskReminderRepository,
+	briefingRepo *repository.DailyBriefingRepository,
 	notificationService *NotificationService,
+	preferenceService *NotificationPreferenceService,
+	briefingService *DailyBriefingService,
+	overdueService *OverdueService,
+	snoozeService *TaskSnoozeService,
 	weatherService *WeatherService,
+	reportService *ReportService,
+	holidayService *HolidayService,
+	leaveService *LeaveService,
+	registry *JobRegistry,
 ) *SchedulerService {
 	return &SchedulerService{
 		db:                  db,
 		userRepo:            userRepo,
 		taskRepo:            taskRepo,
+		reminderRepo:        reminderRepo,
+		briefingRepo:        briefingRepo,
 		notificationService: notificationService,
+		preferenceService:   preferenceService,
+		briefingService:     briefingService,
+		overdueService:      overdueService,
+		snoozeService:       snoozeService,
 		weatherService:      weatherService,
-		stopChan:            make(chan struct{}),
+		reportService:       reportService,
+		holidayService:      holidayService,
+		leaveService:        leaveService,
+		registry:            registry,
 	}
 }
 
-// Start begins all scheduler routines
+// notificationSchedulerName nama scheduler ini di riwayat job run dan registry
+const notificationSchedulerName = "notifications"
+
+// Start registers all notification jobs in the job registry
 func (s *SchedulerService) Start() {
 	log.Println("🚀 Starting Scheduler Service...")
 
-	// Start health recommendation scheduler (runs every hour)
-	s.wg.Add(1)
-	go s.healthRecommendationScheduler()
+	jobs := []JobDefinition{
+		{
+			// Runs every hour (and on startup) to check workload
+			Name:        "health_recommendation",
+			Description: "Check every user's workload and send health recommendations",
+			Schedule:    "0 * * * *",
+			Jitter:      2 * time.Minute,
+			Timeout:     15 * time.Minute,
+			RunOnStart:  true,
+			Run:         s.checkAllUsersWorkload,
+		},
+		{
+			Name:        "weather_alert",
+			Description: "Send weather alerts to VIP users at 6 AM",
+			Schedule:    "0 6 * * *",
+			Timeout:     15 * time.Minute,
+			CatchUp:     true,
+			Run:         s.sendWeatherNotificationsToVIPUsers,
+		},
+		{
+			// Reminders missed during downtime are caught up on startup
+			Name:        "task_reminder",
+			Description: "Send due task reminders and re-fire expired snoozes",
+			Schedule:    "* * * * *",
+			Timeout:     5 * time.Minute,
+			RunOnStart:  true,
+			Run:         s.sendTaskReminders,
+		},
+		{
+			Name:        "daily_briefing",
+			Description: "Send the morning agenda / evening digest at each user's local time",
+			Schedule:    "* * * * *",
+			Timeout:     10 * time.Minute,
+			RunOnStart:  true,
+			Run:         s.sendDailyBriefings,
+		},
+		{
+			Name:        "productivity_report",
+			Description: "Generate weekly reports on Monday and monthly reports on the 1st at 7 AM",
+			Schedule:    "0 7 * * *",
+			Timeout:     30 * time.Minute,
+			CatchUp:     true,
+			Run:         s.generateProductivityReports,
+		},
+	}
+
+	if s.overdueService != nil {
+		jobs = append(jobs, JobDefinition{
+			Name:        "overdue_policy",
+			Description: "Apply each user's overdue policy (renotify, roll over, mark missed) at 00:05",
+			Schedule:    "5 0 * * *",
+			Timeout:     30 * time.Minute,
+			CatchUp:     true,
+			Run:         s.applyOverduePolicies,
+		})
+	}
 
-	// Start weather notification scheduler (runs at 6 AM daily)
-	s.wg.Add(1)
-	go s.weatherNotificationScheduler()
+	if s.holidayService != nil {
+		jobs = append(jobs,
+			JobDefinition{
+				// Runs on startup too so a fresh database has holiday data
+				Name:        "holiday_refresh",
+				Description: "Sync holiday providers for this year and next year at 3 AM",
+				Schedule:    "0 3 * * *",
+				Jitter:      5 * time.Minute,
+				Timeout:     10 * time.Minute,
+				RunOnStart:  true,
+				Run:         s.refreshHolidays,
+			},
+			JobDefinition{
+				Name:        "holiday_reminder",
+				Description: "Send personal holiday / anniversary reminders at 8 AM",
+				Schedule:    "0 8 * * *",
+				Timeout:     10 * time.Minute,
+				CatchUp:     true,
+				Run:         s.sendHolidayReminders,
+			},
+		)
+	}
 
-	// Start task reminder scheduler (runs every 5 minutes)
-	s.wg.Add(1)
-	go s.taskReminderScheduler()
+	for _, job := range jobs {
+		job.Scheduler = notificationSchedulerName
+		if err := s.registry.Register(job); err != nil {
+			log.Printf("❌ Failed to register scheduler job %s: %v", job.Name, err)
+		}
+	}
 
 	log.Println("✅ Scheduler Service started successfully")
 }
 
-// Stop gracefully stops all scheduler routines
+// Stop removes all notification jobs from the job registry
 func (s *SchedulerService) Stop() {
 	log.Println("🛑 Stopping Scheduler Service...")
-	close(s.stopChan)
-	s.wg.Wait()
+	s.registry.Remove(notificationSchedulerName)
 	log.Println("✅ Scheduler Service stopped")
 }
 
 // ==================== HEALTH RECOMMENDATION SCHEDULER ====================
 
-// healthRecommendationScheduler runs every hour to check workload and send health notifications
-func (s *SchedulerService) healthRecommendationScheduler() {
-	defer s.wg.Done()
-
-	// Run immediately on startup
-	s.checkAllUsersWorkload()
-
-	ticker := time.NewTicker(1 * time.Hour)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			s.checkAllUsersWorkload()
-		case <-s.stopChan:
-			log.Println("📋 Health recommendation scheduler stopped")
-			return
-		}
-	}
-}
-
 // checkAllUsersWorkload checks workload for all active users
-func (s *SchedulerService) checkAllUsersWorkload() {
+func (s *SchedulerService) checkAllUsersWorkload(ctx context.Context) (string, error) {
 	log.Println("📋 Running health recommendation check...")
 
-	// Get all users with FCM tokens
+	// Semua user: channel email & in-app tidak butuh perangkat terdaftar
 	var users []models.User
-	if err := s.db.Where("fcm_token IS NOT NULL AND fcm_token != ''").Find(&users).Error; err != nil {
-		log.Printf("❌ Failed to fetch users for health check: %v", err)
-		return
+	if err := s.db.Find(&users).Error; err != nil {
+		return "", fmt.Errorf("failed to fetch users for health check: %w", err)
 	}
+	disabled := s.disabledUsers(models.NotificationTypeHealth)
 
+	// Notifikasi hanya masuk outbox, pengiriman & retry oleh worker NotificationService
+	now := time.Now()
+	checked, unreachable := 0, 0
 	for _, user := range users {
-		go s.checkUserWorkload(user)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if disabled[user.ID] {
+			continue
+		}
+		// Lewati user yang tidak terjangkau channel mana pun (mis. hanya push tanpa device)
+		deliverable, err := s.notificationService.CanDeliver(user.ID, models.NotificationTypeHealth, now)
+		if err != nil {
+			log.Printf("⚠️ Failed to resolve notification channels for user %s: %v", user.ID, err)
+			continue
+		}
+		if !deliverable {
+			unreachable++
+			continue
+		}
+		s.checkUserWorkload(user)
+		checked++
 	}
 
-	log.Printf("✅ Health check initiated for %d users", len(users))
+	log.Printf("✅ Health check completed for %d users (%d without deliverable channel skipped)", checked, unreachable)
+	return fmt.Sprintf("Checked workload of %d users, skipped %d without deliverable channel", checked, unreachable), nil
 }
 
 // checkUserWorkload analyzes a single user's workload and sends notification if needed
@@ -114,7 +219,7 @@ func (s *SchedulerService) checkUserWorkload(user models.User) {
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfDay := startOfDay.Add(24*time.Hour - time.Second)
 
-	tasks, err := s.taskRepo.FindByUserIDAndDateRange(user.ID, startOfDay, endOfDay)
+	tasks, err := s.taskRepo.FindWorkloadByUserIDAndDateRange(user.ID, startOfDay, endOfDay)
 	if err != nil {
 		log.Printf("❌ Failed to fetch tasks for user %s: %v", user.ID, err)
 		return
@@ -130,9 +235,9 @@ func (s *SchedulerService) checkUserWorkload(user models.User) {
 		recommendation := s.getHealthRecommendation(taskCount, estimatedHours)
 
 		if err := s.notificationService.SendHealthRecommendation(user.ID, recommendation, estimatedHours); err != nil {
-			log.Printf("❌ Failed to send health recommendation to user %s: %v", user.ID, err)
+			log.Printf("❌ Failed to queue health recommendation for user %s: %v", user.ID, err)
 		} else {
-			log.Printf("✅ Health recommendation sent to user %s (tasks: %d, hours: %.1f)", user.ID, taskCount, estimatedHours)
+			log.Printf("✅ Health recommendation queued for user %s (tasks: %d, hours: %.1f)", user.ID, taskCount, estimatedHours)
 		}
 	}
 }
@@ -190,64 +295,41 @@ func (s *SchedulerService) getHealthRecommendation(taskCount int, hours float64)
 
 // ==================== WEATHER NOTIFICATION SCHEDULER ====================
 
-// weatherNotificationScheduler runs daily at 6 AM to send weather alerts to VIP users
-func (s *SchedulerService) weatherNotificationScheduler() {
-	defer s.wg.Done()
-
-	for {
-		// Calculate time until next 6 AM
-		now := time.Now()
-		next6AM := time.Date(now.Year(), now.Month(), now.Day(), 6, 0, 0, 0, now.Location())
-
-		// If it's already past 6 AM, schedule for tomorrow
-		if now.After(next6AM) {
-			next6AM = next6AM.Add(24 * time.Hour)
-		}
-
-		waitDuration := next6AM.Sub(now)
-		log.Printf("⏰ Weather notification scheduled for %v (in %v)", next6AM.Format("2006-01-02 15:04:05"), waitDuration.Round(time.Minute))
-
-		timer := time.NewTimer(waitDuration)
-
-		select {
-		case <-timer.C:
-			s.sendWeatherNotificationsToVIPUsers()
-		case <-s.stopChan:
-			timer.Stop()
-			log.Println("🌤️ Weather notification scheduler stopped")
-			return
-		}
-	}
-}
-
 // sendWeatherNotificationsToVIPUsers sends weather alerts to all VIP users
-func (s *SchedulerService) sendWeatherNotificationsToVIPUsers() {
+func (s *SchedulerService) sendWeatherNotificationsToVIPUsers(ctx context.Context) (string, error) {
 	log.Println("🌤️ Running weather notification for VIP users...")
 
-	// Get all VIP users with FCM tokens
+	// Get all active VIP users
 	var vipUsers []models.User
 	if err := s.db.Where(
-		"user_type = ? AND fcm_token IS NOT NULL AND fcm_token != '' AND (vip_expires_at IS NULL OR vip_expires_at > ?)",
+		"user_type = ? AND (vip_expires_at IS NULL OR vip_expires_at > ?)",
 		models.UserTypeVIP,
 		time.Now(),
 	).Find(&vipUsers).Error; err != nil {
-		log.Printf("❌ Failed to fetch VIP users for weather notification: %v", err)
-		return
+		return "", fmt.Errorf("failed to fetch VIP users for weather notification: %w", err)
 	}
 
 	if len(vipUsers) == 0 {
-		log.Println("ℹ️ No VIP users with FCM tokens found for weather notification")
-		return
+		log.Println("ℹ️ No VIP users found for weather notification")
+		return "No VIP users", nil
 	}
 
 	// Default city for Indonesian users
 	defaultCity := "Jakarta"
 
+	disabled := s.disabledUsers(models.NotificationTypeWeatherAlert)
 	for _, user := range vipUsers {
-		go s.sendWeatherToUser(user, defaultCity)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if disabled[user.ID] {
+			continue
+		}
+		s.sendWeatherToUser(user, defaultCity)
 	}
 
-	log.Printf("✅ Weather notifications initiated for %d VIP users", len(vipUsers))
+	log.Printf("✅ Weather notifications processed for %d VIP users", len(vipUsers))
+	return fmt.Sprintf("Processed %d VIP users", len(vipUsers)), nil
 }
 
 // sendWeatherToUser sends weather notification to a single user
@@ -262,9 +344,9 @@ func (s *SchedulerService) sendWeatherToUser(user models.User, city string) {
 	// Check if weather condition warrants notification
 	if s.shouldSendWeatherAlert(weather) {
 		if err := s.notificationService.SendWeatherAlert(user.ID, city, weather.Description, weather.Temperature); err != nil {
-			log.Printf("❌ Failed to send weather alert to user %s: %v", user.ID, err)
+			log.Printf("❌ Failed to queue weather alert for user %s: %v", user.ID, err)
 		} else {
-			log.Printf("✅ Weather alert sent to user %s: %s, %.1f°C", user.ID, weather.Description, weather.Temperature)
+			log.Printf("✅ Weather alert queued for user %s: %s, %.1f°C", user.ID, weather.Description, weather.Temperature)
 		}
 	}
 }
@@ -302,72 +384,356 @@ func (s *SchedulerService) shouldSendWeatherAlert(weather *CurrentWeather) bool
 		return true
 	}
 
-	// For nice weather, still send morning notification (VIP feature)
-	return true
+	// Cuaca normal tidak perlu alert
+	return false
 }
 
 // ==================== TASK REMINDER SCHEDULER ====================
 
-// taskReminderScheduler runs every 5 minutes to check for upcoming task deadlines
-func (s *SchedulerService) taskReminderScheduler() {
-	defer s.wg.Done()
+// sendTaskReminders processes due reminders and expired snoozes in one run
+func (s *SchedulerService) sendTaskReminders(ctx context.Context) (string, error) {
+	now := time.Now()
+	checked, err := s.checkUpcomingDeadlines(ctx, now)
+	snoozed := s.sendSnoozedReminders(ctx, now)
+	return fmt.Sprintf("Checked %d tasks, %d snoozed reminders", checked, snoozed), err
+}
 
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
+const (
+	// reminderGracePeriod task yang deadline-nya sudah lewat tetap diperiksa selama ini, agar
+	// reminder yang terlewat (mis. server mati saat deadline) tetap dicatat atau dikirim
+	reminderGracePeriod = 24 * time.Hour
+	// reminderCatchUpWindow setelah deadline, reminder terdekat masih dikirim selama ini;
+	// lebih lama dari itu semua reminder dicatat skipped (task ditangani overdue policy)
+	reminderCatchUpWindow = 15 * time.Minute
+)
 
-	for {
-		select {
-		case <-ticker.C:
-			s.checkUpcomingDeadlines()
-		case <-s.stopChan:
-			log.Println("⏰ Task reminder scheduler stopped")
-			return
-		}
+// checkUpcomingDeadlines sends every reminder whose time has come and that has not been
+// processed yet for the current occurrence (task + deadline). Reminders are recorded in
+// task_reminder_logs, so consecutive ticks or restarts never send the same reminder twice.
+func (s *SchedulerService) checkUpcomingDeadlines(ctx context.Context, now time.Time) (int, error) {
+	tasks, err := s.taskRepo.FindWithPendingReminders(now.Add(-reminderGracePeriod), now, now.Add(models.MaxReminderOffsetMinutes*time.Minute))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch upcoming tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		return 0, nil
 	}
-}
 
-// checkUpcomingDeadlines finds tasks with upcoming deadlines and sends reminders
-func (s *SchedulerService) checkUpcomingDeadlines() {
-	now := time.Now()
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	logs, err := s.reminderRepo.FindByTaskIDs(taskIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch task reminder logs: %w", err)
+	}
+	processed := make(map[string]bool, len(logs))
+	for _, entry := range logs {
+		processed[reminderLogKey(entry.TaskID, entry.UserID, entry.Deadline, entry.OffsetMinutes)] = true
+	}
 
-	// Get all tasks with deadlines in the next hour that haven't been completed
-	// We'll check for tasks where deadline - reminder_minutes = now (approximately)
-	var tasks []models.Task
-	if err := s.db.Preload("User").
-		Where("is_completed = ? AND deadline IS NOT NULL AND reminder_minutes IS NOT NULL", false).
-		Where("deadline BETWEEN ? AND ?", now, now.Add(1*time.Hour)).
-		Find(&tasks).Error; err != nil {
-		log.Printf("❌ Failed to fetch upcoming tasks: %v", err)
-		return
+	// Reminder yang ditunda user dikirim ulang oleh sendSnoozedReminders
+	snoozed := map[string]models.TaskSnooze{}
+	if s.snoozeService != nil {
+		if snoozed, err = s.snoozeService.ActiveSnoozes(taskIDs, now); err != nil {
+			log.Printf("⚠️ Failed to fetch task snoozes: %v", err)
+		}
 	}
 
+	// User yang sedang cuti (approved) tidak diganggu reminder
+	onLeave := map[string]bool{}
 	for _, task := range tasks {
-		if task.Deadline == nil || task.ReminderMinutes == nil {
+		if err := ctx.Err(); err != nil {
+			return len(tasks), err
+		}
+		if task.Deadline == nil {
+			continue
+		}
+		deadline := *task.Deadline
+
+		// Reminder yang waktunya sudah tiba; offset yang lewat sebelum task dibuat diabaikan
+		var due []int
+		for _, offset := range task.ReminderSchedule() {
+			reminderAt := deadline.Add(-time.Duration(offset) * time.Minute)
+			if !reminderAt.After(now) && !reminderAt.Before(task.CreatedAt.Add(-time.Minute)) {
+				due = append(due, offset)
+			}
+		}
+		if len(due) == 0 {
 			continue
 		}
 
-		// Calculate when reminder should be sent
-		reminderTime := task.Deadline.Add(-time.Duration(*task.ReminderMinutes) * time.Minute)
+		// Deadline yang sudah lama lewat: reminder tidak lagi relevan, hanya dicatat skipped
+		stale := now.Sub(deadline) > reminderCatchUpWindow
+
+		// Task yang ditugaskan diingatkan ke assignee, bukan pembuatnya
+		recipients := task.AssigneeIDs()
+		if len(recipients) == 0 {
+			recipients = []string{task.UserID}
+		}
 
-		// Check if we're within 5 minutes of the reminder time
-		timeDiff := reminderTime.Sub(now)
-		if timeDiff >= -2*time.Minute && timeDiff <= 5*time.Minute {
-			go s.sendTaskReminder(task)
+		for _, userID := range recipients {
+			userOnLeave, checked := onLeave[userID]
+			if !checked && s.leaveService != nil {
+				userOnLeave, _ = s.leaveService.IsOnLeave(userID, now)
+				onLeave[userID] = userOnLeave
+			}
+			if userOnLeave {
+				continue
+			}
+
+			// Selama snooze aktif semua reminder yang jatuh tempo ditandai skipped:
+			// penggantinya adalah pengingat ulang saat snooze berakhir
+			skipUntil := len(due) - 1
+			if snooze, ok := snoozed[snoozeKey(task.ID, userID)]; ok && sameDeadline(snooze.Deadline, task.Deadline) {
+				skipUntil = len(due)
+			}
+			if stale {
+				skipUntil = len(due)
+			}
+
+			// due terurut offset terbesar dulu: hanya reminder terdekat yang dikirim,
+			// reminder lebih awal yang terlewat (mis. server mati) ditandai skipped
+			for _, offset := range due[:skipUntil] {
+				if processed[reminderLogKey(task.ID, userID, deadline, offset)] {
+					continue
+				}
+				if _, err := s.reminderRepo.Claim(&models.TaskReminderLog{
+					TaskID: task.ID, UserID: userID, Deadline: deadline, OffsetMinutes: offset, Skipped: true,
+				}); err != nil {
+					log.Printf("⚠️ Failed to record skipped reminder for task %s: %v", task.ID, err)
+				}
+			}
+
+			if skipUntil == len(due) {
+				continue
+			}
+			nearest := due[len(due)-1]
+			if processed[reminderLogKey(task.ID, userID, deadline, nearest)] {
+				continue
+			}
+			s.sendTaskReminder(task, userID, nearest)
 		}
 	}
+	return len(tasks), nil
 }
 
-// sendTaskReminder sends a reminder for a specific task
-func (s *SchedulerService) sendTaskReminder(task models.Task) {
+// sendTaskReminder claims and queues one reminder of a task for one recipient
+func (s *SchedulerService) sendTaskReminder(task models.Task, userID string, offsetMinutes int) {
 	if task.Deadline == nil {
 		return
 	}
 
-	if err := s.notificationService.SendTaskReminder(task.UserID, task.Title, *task.Deadline); err != nil {
-		log.Printf("❌ Failed to send task reminder for task %s: %v", task.ID, err)
+	// Reminder yang dimatikan user dicatat skipped agar tidak terkirim terlambat saat diaktifkan lagi
+	skipped := s.preferenceService != nil && !s.preferenceService.IsEnabled(userID, models.NotificationTypeTaskReminder)
+
+	claimed, err := s.reminderRepo.Claim(&models.TaskReminderLog{
+		TaskID:        task.ID,
+		UserID:        userID,
+		Deadline:      *task.Deadline,
+		OffsetMinutes: offsetMinutes,
+		Skipped:       skipped,
+	})
+	if err != nil {
+		log.Printf("❌ Failed to record task reminder for task %s: %v", task.ID, err)
+		return
+	}
+	if !claimed || skipped {
+		return // sudah diproses (mis. oleh instance lain) atau dimatikan user
+	}
+
+	if err := s.notificationService.SendTaskReminder(userID, task.ID, task.Title, *task.Deadline, offsetMinutes); err != nil {
+		log.Printf("❌ Failed to queue task reminder for task %s to user %s: %v", task.ID, userID, err)
+		// Lepas claim agar dicoba lagi pada tick berikutnya
+		if err := s.reminderRepo.Release(task.ID, userID, *task.Deadline, offsetMinutes); err != nil {
+			log.Printf("⚠️ Failed to release task reminder for task %s: %v", task.ID, err)
+		}
+		return
+	}
+
+	log.Printf("✅ Task reminder queued for '%s' to user %s (deadline: %v, %d min before)", task.Title, userID, task.Deadline.Format("15:04"), offsetMinutes)
+}
+
+// sendSnoozedReminders re-fires reminders whose snooze has expired
+func (s *SchedulerService) sendSnoozedReminders(ctx context.Context, now time.Time) int {
+	if s.snoozeService == nil {
+		return 0
+	}
+	sent := s.snoozeService.FireDue(ctx, now)
+	if sent > 0 {
+		log.Printf("✅ Snoozed task reminders queued: %d", sent)
+	}
+	return sent
+}
+
+// disabledUsers user yang mematikan jenis notifikasi ini
+func (s *SchedulerService) disabledUsers(notificationType string) map[string]bool {
+	if s.preferenceService == nil {
+		return nil
+	}
+	disabled, err := s.preferenceService.DisabledUserIDs(notificationType)
+	if err != nil {
+		log.Printf("⚠️ Failed to fetch notification preferences for %s: %v", notificationType, err)
+		return nil
+	}
+	return disabled
+}
+
+// reminderLogKey identitas satu reminder untuk satu occurrence dan penerima
+func reminderLogKey(taskID, userID string, deadline time.Time, offsetMinutes int) string {
+	return fmt.Sprintf("%s|%s|%d|%d", taskID, userID, deadline.Unix(), offsetMinutes)
+}
+
+// ==================== DAILY BRIEFING SCHEDULER ====================
+
+// dailyBriefingCatchUp briefing yang terlewat (mis. server mati) masih dikirim dalam window ini
+const dailyBriefingCatchUp = 2 * time.Hour
+
+// sendDailyBriefings sends every agenda/digest whose local time has come and that has not
+// been processed yet for the user's local date (recorded in daily_briefing_logs).
+// Per zona waktu hanya user yang jatuh tempo yang diambil dari database.
+func (s *SchedulerService) sendDailyBriefings(ctx context.Context) (string, error) {
+	if s.briefingService == nil || s.briefingRepo == nil || s.preferenceService == nil {
+		return "", nil
+	}
+	now := time.Now()
+
+	timeZones, err := s.preferenceService.TimeZones()
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch time zones for daily briefing: %w", err)
+	}
+
+	due := 0
+	var disabled map[models.DailyBriefingKind]map[string]bool
+	for _, tz := range timeZones {
+		local := now.In(notificationLocation(tz))
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+		date := day.Format("2006-01-02")
+		clocks := dailyBriefingClocks(local)
+
+		for _, kind := range []models.DailyBriefingKind{models.DailyBriefingAgenda, models.DailyBriefingDigest} {
+			if ctx.Err() != nil {
+				return fmt.Sprintf("Processed %d due briefings", due), ctx.Err()
+			}
+			userIDs, err := s.briefingRepo.FindDueUserIDs(kind, date, tz, clocks)
+			if err != nil {
+				return fmt.Sprintf("Processed %d due briefings", due), fmt.Errorf("failed to fetch due daily %s users: %w", kind, err)
+			}
+			if len(userIDs) == 0 {
+				continue
+			}
+
+			// Preferensi hanya dimuat jika ada briefing yang jatuh tempo
+			if disabled == nil {
+				disabled = map[models.DailyBriefingKind]map[string]bool{
+					models.DailyBriefingAgenda: s.disabledUsers(models.NotificationTypeDailyAgenda),
+					models.DailyBriefingDigest: s.disabledUsers(models.NotificationTypeDailyDigest),
+				}
+			}
+			for _, userID := range userIDs {
+				if disabled[kind][userID] {
+					continue
+				}
+				s.sendDailyBriefing(userID, kind, day, now)
+				due++
+			}
+		}
+	}
+	return fmt.Sprintf("Processed %d due briefings", due), nil
+}
+
+// sendDailyBriefing builds, claims and queues one briefing; empty briefings are recorded as skipped
+func (s *SchedulerService) sendDailyBriefing(userID string, kind models.DailyBriefingKind, day, now time.Time) {
+	var briefing *DailyBriefing
+	var err error
+	if kind == models.DailyBriefingAgenda {
+		briefing, err = s.briefingService.BuildAgenda(userID, day)
 	} else {
-		log.Printf("✅ Task reminder sent for '%s' (deadline: %v)", task.Title, task.Deadline.Format("15:04"))
+		briefing, err = s.briefingService.BuildDigest(userID, day, now)
+	}
+	if err != nil {
+		log.Printf("❌ Failed to build daily %s for user %s: %v", kind, userID, err)
+		return
+	}
+
+	claimed, err := s.briefingRepo.Claim(&models.DailyBriefingLog{
+		UserID:  userID,
+		Kind:    kind,
+		Date:    briefing.Date,
+		Skipped: briefing.Empty,
+	})
+	if err != nil {
+		log.Printf("❌ Failed to record daily %s for user %s: %v", kind, userID, err)
+		return
+	}
+	if !claimed || briefing.Empty {
+		return
 	}
+
+	if err := s.notificationService.SendDailyBriefing(userID, briefing); err != nil {
+		log.Printf("❌ Failed to queue daily %s for user %s: %v", kind, userID, err)
+		// Lepas claim agar dicoba lagi pada tick berikutnya
+		if err := s.briefingRepo.Release(userID, kind, briefing.Date); err != nil {
+			log.Printf("⚠️ Failed to release daily %s for user %s: %v", kind, userID, err)
+		}
+		return
+	}
+
+	log.Printf("✅ Daily %s queued for user %s (%s)", kind, userID, briefing.Date)
+}
+
+// dailyBriefingClocks jam briefing yang jatuh tempo pada local: sudah lewat, masih dalam
+// window catch-up dan di hari yang sama. Jam sebelum 10:00 juga disertakan tanpa nol di depan
+// ("7:30") untuk baris lama yang disimpan sebelum jam dinormalkan ke HH:MM.
+func dailyBriefingClocks(local time.Time) []string {
+	minute := local.Hour()*60 + local.Minute()
+	window := int(dailyBriefingCatchUp / time.Minute)
+	clocks := make([]string, 0, 2*window)
+	for m := minute; m > minute-window && m >= 0; m-- {
+		clock := formatClock(m)
+		clocks = append(clocks, clock)
+		if m < 10*60 {
+			clocks = append(clocks, clock[1:])
+		}
+	}
+	return clocks
+}
+
+// ==================== OVERDUE POLICY SCHEDULER ====================
+
+// applyOverduePolicies runs one overdue pass and logs the outcome
+func (s *SchedulerService) applyOverduePolicies(ctx context.Context) (string, error) {
+	result, err := s.overdueService.RunNightly(ctx, JobScheduledAt(ctx))
+	if err != nil {
+		return "", fmt.Errorf("overdue policy run failed: %w", err)
+	}
+	summary := fmt.Sprintf("%d renotified, %d rolled over, %d missed, %d failed",
+		result.Renotified, result.RolledOver, result.Missed, result.Failed)
+	log.Printf("✅ Overdue policies applied: %s", summary)
+	return summary, nil
+}
+
+// ==================== PRODUCTIVITY REPORT SCHEDULER ====================
+
+// generateProductivityReports creates reports for the period that just ended
+func (s *SchedulerService) generateProductivityReports(ctx context.Context) (string, error) {
+	if s.reportService == nil {
+		return "", nil
+	}
+	// Slot terjadwal, bukan waktu sekarang: run catch-up hari Selasa tetap membuat laporan Senin
+	now := JobScheduledAt(ctx)
+
+	weekly, monthly := 0, 0
+	if now.Weekday() == time.Monday {
+		weekly = s.reportService.RunScheduledReports(ctx, models.ReportPeriodWeekly, now)
+		log.Printf("📊 Weekly productivity reports generated: %d", weekly)
+	}
+
+	if now.Day() == 1 {
+		monthly = s.reportService.RunScheduledReports(ctx, models.ReportPeriodMonthly, now)
+		log.Printf("📊 Monthly productivity reports generated: %d", monthly)
+	}
+	return fmt.Sprintf("%d weekly, %d monthly reports", weekly, monthly), nil
 }
 
 // ==================== HELPER FUNCTIONS ====================
@@ -398,3 +764,60 @@ func containsString(s, substr string) bool {
 	}
 	return false
 }
+
+// ==================== HOLIDAY REFRESH SCHEDULER ====================
+
+// refreshHolidays imports holidays from all configured providers
+func (s *SchedulerService) refreshHolidays(ctx context.Context) (string, error) {
+	results := s.holidayService.RefreshHolidays(ctx, time.Now())
+
+	created, updated, removed := 0, 0, 0
+	for _, result := range results {
+		created += result.Created
+		updated += result.Updated
+		removed += result.Removed
+	}
+
+	summary := fmt.Sprintf("%d sources, %d created, %d updated, %d removed", len(results), created, updated, removed)
+	log.Printf("✅ Holiday refresh done: %s", summary)
+	return summary, nil
+}
+
+// sendHolidayReminders sends reminders due today, each occurrence only once
+func (s *SchedulerService) sendHolidayReminders(ctx context.Context) (string, error) {
+	reminders, err := s.holidayService.DueHolidayReminders(time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch holiday reminders: %w", err)
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			break
+		}
+		if reminder.Holiday.UserID == nil {
+			continue
+		}
+
+		if err := s.notificationService.SendHolidayReminder(
+			*reminder.Holiday.UserID,
+			reminder.Holiday.Name,
+			reminder.Occurrence,
+			reminder.DaysBefore,
+			reminder.YearsSince,
+		); err != nil {
+			log.Printf("⚠️ Failed to queue holiday reminder %s: %v", reminder.Holiday.ID, err)
+			continue
+		}
+
+		if err := s.holidayService.MarkReminderSent(reminder); err != nil {
+			log.Printf("⚠️ Failed to mark holiday reminder %s: %v", reminder.Holiday.ID, err)
+		}
+		sent++
+	}
+
+	if len(reminders) > 0 {
+		log.Printf("✅ Holiday reminders queued: %d/%d", sent, len(reminders))
+	}
+	return fmt.Sprintf("Queued %d/%d holiday reminders", sent, len(reminders)), nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDailyBriefingClocks jam yang jatuh tempo: sudah lewat, dalam window catch-up dan di hari yang sama
+func TestDailyBriefingClocks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		local    string
+		clock    string
+		expected bool
+	}{
+		{"Exact time", "07:00", "07:00", true},
+		{"Within catch-up window", "08:59", "07:00", true},
+		{"Catch-up window ended", "09:00", "07:00", false},
+		{"Not yet", "06:59", "07:00", false},
+		{"Legacy clock without leading zero", "07:30", "7:30", true},
+		{"Evening digest", "18:15", "18:00", true},
+		{"Previous day not included", "00:30", "23:30", false},
+		{"Midnight", "00:00", "00:00", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			local, err := time.Parse("2006-01-02 15:04", "2026-10-19 "+tc.local)
+			if err != nil {
+				t.Fatalf("invalid time %q: %v", tc.local, err)
+			}
+			got := false
+			for _, clock := range dailyBriefingClocks(local) {
+				if clock == tc.clock {
+					got = true
+				}
+			}
+			if got != tc.expected {
+				t.Errorf("Local: %s, clock: %s\nExpected due: %v, Got: %v", tc.local, tc.clock, tc.expected, got)
+			}
+		})
+	}
+}
@@ -28,8 +28,12 @@ const (
 	SecurityTaskDatabaseOptimize  SecurityScheduledTaskType = "DATABASE_OPTIMIZE"
 	SecurityTaskSecurityReport    SecurityScheduledTaskType = "SECURITY_REPORT"
 	SecurityTaskTokenCleanup      SecurityScheduledTaskType = "TOKEN_CLEANUP"
+	SecurityTaskJobRunCleanup     SecurityScheduledTaskType = "JOB_RUN_CLEANUP"
 )
 
+// securitySchedulerName nama scheduler ini di riwayat job run
+const securitySchedulerName = "security"
+
 // SecurityTaskStatus represents task execution status
 type SecurityTaskStatus string
 
@@ -46,14 +50,11 @@ type SecurityScheduledTask struct {
 	Type        SecurityScheduledTaskType `json:"type"`
 	Name        string                    `json:"name"`
 	Description string                    `json:"description"`
-	Interval    time.Duration             `json:"interval"`
-	LastRun     *time.Time                `json:"last_run,omitempty"`
-	NextRun     time.Time                 `json:"next_run"`
-	Status      SecurityTaskStatus        `json:"status"`
-	Enabled     bool                      `json:"enabled"`
+	Schedule    string                    `json:"schedule"` // cron expression
+	Timeout     time.Duration             `json:"timeout"`
 }
 
-// SecurityTaskExecutionLog represents a task execution log entry
+// SecurityTaskExecutionLog represents a task execution log entry (read from persisted job runs)
 type SecurityTaskExecutionLog struct {
 	ID          string                    `json:"id"`
 	TaskType    SecurityScheduledTaskType `json:"task_type"`
@@ -65,18 +66,17 @@ type SecurityTaskExecutionLog struct {
 	Error       string                    `json:"error,omitempty"`
 }
 
-// SecuritySchedulerService manages scheduled security tasks
+// SecuritySchedulerService manages scheduled security tasks. Scheduling, leader-only
+// execution and run history are handled by the shared JobRegistry.
 type SecuritySchedulerService struct {
-	db            *gorm.DB
-	tasks         map[SecurityScheduledTaskType]*SecurityScheduledTask
-	executionLogs []SecurityTaskExecutionLog
-	mu            sync.RWMutex
-	ctx           context.Context
-	cancel        context.CancelFunc
-	isRunning     bool
-	auditService  *SecurityAuditService
-	vulnScanner   *VulnerabilityScannerService
-	acService     *AccessControlService
+	db           *gorm.DB
+	tasks        []*SecurityScheduledTask
+	mu           sync.RWMutex
+	isRunning    bool
+	auditService *SecurityAuditService
+	vulnScanner  *VulnerabilityScannerService
+	acService    *AccessControlService
+	registry     *JobRegistry
 }
 
 var (
@@ -88,12 +88,10 @@ var (
 func GetSecuritySchedulerService() *SecuritySchedulerService {
 	securitySchedulerServiceOnce.Do(func() {
 		securitySchedulerService = &SecuritySchedulerService{
-			db:            database.DB,
-			tasks:         make(map[SecurityScheduledTaskType]*SecurityScheduledTask),
-			executionLogs: make([]SecurityTaskExecutionLog, 0),
-			auditService:  GetSecurityAuditService(),
-			vulnScanner:   GetVulnerabilityScannerService(),
-			acService:     GetAccessControlService(),
+			db:           database.DB,
+			auditService: GetSecurityAuditService(),
+			vulnScanner:  GetVulnerabilityScannerService(),
+			acService:    GetAccessControlService(),
 		}
 		securitySchedulerService.initializeTasks()
 	})
@@ -103,281 +101,223 @@ func GetSecuritySchedulerService() *SecuritySchedulerService {
 // NewSecuritySchedulerService creates a new security scheduler service
 func NewSecuritySchedulerService(db *gorm.DB) *SecuritySchedulerService {
 	service := &SecuritySchedulerService{
-		db:            db,
-		tasks:         make(map[SecurityScheduledTaskType]*SecurityScheduledTask),
-		executionLogs: make([]SecurityTaskExecutionLog, 0),
-		auditService:  GetSecurityAuditService(),
-		vulnScanner:   GetVulnerabilityScannerService(),
-		acService:     GetAccessControlService(),
+		db:           db,
+		auditService: GetSecurityAuditService(),
+		vulnScanner:  GetVulnerabilityScannerService(),
+		acService:    GetAccessControlService(),
 	}
 	service.initializeTasks()
 	return service
 }
 
-// initializeTasks sets up default security scheduled tasks
+// SetJobRegistry sets the registry that schedules the security tasks. Call before Start.
+func (s *SecuritySchedulerService) SetJobRegistry(registry *JobRegistry) {
+	s.mu.Lock()
+	s.registry = registry
+	s.mu.Unlock()
+}
+
+// initializeTasks sets up default security scheduled tasks. Schedules can be overridden
+// via JOB_<TYPE>_SCHEDULE (e.g. JOB_SECURITY_AUDIT_SCHEDULE="0 1 * * *").
 func (s *SecuritySchedulerService) initializeTasks() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Security Audit - Every 24 hours
-	auditInterval := getSecurityEnvDuration("SECURITY_AUDIT_INTERVAL", 24*time.Hour)
-	s.tasks[SecurityTaskAudit] = &SecurityScheduledTask{
-		Type:        SecurityTaskAudit,
-		Name:        "Security Audit",
-		Description: "Comprehensive security audit of the system including password policy, MFA adoption, login failures, and more",
-		Interval:    auditInterval,
-		NextRun:     time.Now().Add(auditInterval),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Vulnerability Scan - Every 12 hours
-	vulnInterval := getSecurityEnvDuration("VULNERABILITY_SCAN_INTERVAL", 12*time.Hour)
-	s.tasks[SecurityTaskVulnerabilityScan] = &SecurityScheduledTask{
-		Type:        SecurityTaskVulnerabilityScan,
-		Name:        "Vulnerability Scan",
-		Description: "Quick vulnerability scan including SQL injection, XSS detection, and API endpoint analysis",
-		Interval:    vulnInterval,
-		NextRun:     time.Now().Add(vulnInterval),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Session Cleanup - Every 1 hour
-	s.tasks[SecurityTaskSessionCleanup] = &SecurityScheduledTask{
-		Type:        SecurityTaskSessionCleanup,
-		Name:        "Session Cleanup",
-		Description: "Clean up expired sessions and refresh tokens",
-		Interval:    1 * time.Hour,
-		NextRun:     time.Now().Add(1 * time.Hour),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Token Cleanup - Every 6 hours
-	s.tasks[SecurityTaskTokenCleanup] = &SecurityScheduledTask{
-		Type:        SecurityTaskTokenCleanup,
-		Name:        "Token Cleanup",
-		Description: "Clean up expired password reset tokens and MFA tokens",
-		Interval:    6 * time.Hour,
-		NextRun:     time.Now().Add(6 * time.Hour),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Audit Log Cleanup - Every 7 days
-	s.tasks[SecurityTaskAuditLogCleanup] = &SecurityScheduledTask{
-		Type:        SecurityTaskAuditLogCleanup,
-		Name:        "Audit Log Cleanup",
-		Description: "Archive and clean old audit logs (>90 days)",
-		Interval:    7 * 24 * time.Hour,
-		NextRun:     time.Now().Add(7 * 24 * time.Hour),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Blocked IP Cleanup - Every 6 hours
-	s.tasks[SecurityTaskBlockedIPCleanup] = &SecurityScheduledTask{
-		Type:        SecurityTaskBlockedIPCleanup,
-		Name:        "Blocked IP Cleanup",
-		Description: "Remove expired IP blocks and temporary bans",
-		Interval:    6 * time.Hour,
-		NextRun:     time.Now().Add(6 * time.Hour),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Password Expiry Check - Every 24 hours
-	s.tasks[SecurityTaskPasswordExpiry] = &SecurityScheduledTask{
-		Type:        SecurityTaskPasswordExpiry,
-		Name:        "Password Expiry Check",
-		Description: "Check for users with expired passwords (>90 days)",
-		Interval:    24 * time.Hour,
-		NextRun:     time.Now().Add(24 * time.Hour),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Inactive Accounts Check - Every 7 days
-	s.tasks[SecurityTaskInactiveAccounts] = &SecurityScheduledTask{
-		Type:        SecurityTaskInactiveAccounts,
-		Name:        "Inactive Accounts Check",
-		Description: "Check for inactive accounts (>6 months) for potential deactivation",
-		Interval:    7 * 24 * time.Hour,
-		NextRun:     time.Now().Add(7 * 24 * time.Hour),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Database Optimize - Every 7 days
-	s.tasks[SecurityTaskDatabaseOptimize] = &SecurityScheduledTask{
-		Type:        SecurityTaskDatabaseOptimize,
-		Name:        "Database Optimize",
-		Description: "Optimize security-related database tables and indexes",
-		Interval:    7 * 24 * time.Hour,
-		NextRun:     time.Now().Add(7 * 24 * time.Hour),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-
-	// Security Report - Every 7 days
-	s.tasks[SecurityTaskSecurityReport] = &SecurityScheduledTask{
-		Type:        SecurityTaskSecurityReport,
-		Name:        "Security Report Generation",
-		Description: "Generate weekly security report summary",
-		Interval:    7 * 24 * time.Hour,
-		NextRun:     time.Now().Add(7 * 24 * time.Hour),
-		Status:      SecurityTaskPending,
-		Enabled:     true,
-	}
-}
-
-// Start starts the security scheduler
-func (s *SecuritySchedulerService) Start() error {
-	s.mu.Lock()
-	if s.isRunning {
-		s.mu.Unlock()
-		return fmt.Errorf("security scheduler is already running")
+	// Security Audit - Daily at 02:00 (legacy SECURITY_AUDIT_INTERVAL still honoured)
+	auditSchedule := "0 2 * * *"
+	if interval := getSecurityEnvDuration("SECURITY_AUDIT_INTERVAL", 0); interval > 0 {
+		auditSchedule = "@every " + interval.String()
 	}
-	s.ctx, s.cancel = context.WithCancel(context.Background())
-	s.isRunning = true
-	s.mu.Unlock()
 
-	go s.run()
+	// Vulnerability Scan - Every 12 hours (legacy VULNERABILITY_SCAN_INTERVAL still honoured)
+	vulnSchedule := "30 */12 * * *"
+	if interval := getSecurityEnvDuration("VULNERABILITY_SCAN_INTERVAL", 0); interval > 0 {
+		vulnSchedule = "@every " + interval.String()
+	}
 
-	log.Println("🔒 Security Scheduler Service started")
-	return nil
+	s.tasks = []*SecurityScheduledTask{
+		{
+			Type:        SecurityTaskAudit,
+			Name:        "Security Audit",
+			Description: "Comprehensive security audit of the system including password policy, MFA adoption, login failures, and more",
+			Schedule:    auditSchedule,
+			Timeout:     10 * time.Minute,
+		},
+		{
+			Type:        SecurityTaskVulnerabilityScan,
+			Name:        "Vulnerability Scan",
+			Description: "Quick vulnerability scan including SQL injection, XSS detection, and API endpoint analysis",
+			Schedule:    vulnSchedule,
+			Timeout:     10 * time.Minute,
+		},
+		// Session Cleanup - Every hour
+		{
+			Type:        SecurityTaskSessionCleanup,
+			Name:        "Session Cleanup",
+			Description: "Clean up expired sessions and refresh tokens",
+			Schedule:    "15 * * * *",
+			Timeout:     5 * time.Minute,
+		},
+		// Token Cleanup - Every 6 hours
+		{
+			Type:        SecurityTaskTokenCleanup,
+			Name:        "Token Cleanup",
+			Description: "Clean up expired password reset tokens and MFA tokens",
+			Schedule:    "20 */6 * * *",
+			Timeout:     5 * time.Minute,
+		},
+		// Audit Log Cleanup - Weekly, Sunday 04:00
+		{
+			Type:        SecurityTaskAuditLogCleanup,
+			Name:        "Audit Log Cleanup",
+			Description: "Archive and clean old audit logs (>90 days)",
+			Schedule:    "0 4 * * 0",
+			Timeout:     30 * time.Minute,
+		},
+		// Blocked IP Cleanup - Every 6 hours
+		{
+			Type:        SecurityTaskBlockedIPCleanup,
+			Name:        "Blocked IP Cleanup",
+			Description: "Remove expired IP blocks and temporary bans",
+			Schedule:    "40 */6 * * *",
+			Timeout:     5 * time.Minute,
+		},
+		// Password Expiry Check - Daily at 05:00
+		{
+			Type:        SecurityTaskPasswordExpiry,
+			Name:        "Password Expiry Check",
+			Description: "Check for users with expired passwords (>90 days)",
+			Schedule:    "0 5 * * *",
+			Timeout:     10 * time.Minute,
+		},
+		// Inactive Accounts Check - Weekly, Sunday 04:30
+		{
+			Type:        SecurityTaskInactiveAccounts,
+			Name:        "Inactive Accounts Check",
+			Description: "Check for inactive accounts (>6 months) for potential deactivation",
+			Schedule:    "30 4 * * 0",
+			Timeout:     10 * time.Minute,
+		},
+		// Database Optimize - Weekly, Sunday 03:30
+		{
+			Type:        SecurityTaskDatabaseOptimize,
+			Name:        "Database Optimize",
+			Description: "Optimize security-related database tables and indexes",
+			Schedule:    "30 3 * * 0",
+			Timeout:     30 * time.Minute,
+		},
+		// Job Run Cleanup - Daily at 02:45
+		{
+			Type:        SecurityTaskJobRunCleanup,
+			Name:        "Job Run Cleanup",
+			Description: "Remove scheduler job-run history older than JOB_RUN_RETENTION_DAYS (default 14)",
+			Schedule:    "45 2 * * *",
+			Timeout:     10 * time.Minute,
+		},
+		// Security Report - Weekly, Monday 06:00
+		{
+			Type:        SecurityTaskSecurityReport,
+			Name:        "Security Report Generation",
+			Description: "Generate weekly security report summary",
+			Schedule:    "0 6 * * 1",
+			Timeout:     10 * time.Minute,
+		},
+	}
 }
 
-// Stop stops the security scheduler
-func (s *SecuritySchedulerService) Stop() {
+// Start registers the security tasks in the job registry
+func (s *SecuritySchedulerService) Start() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.cancel != nil {
-		s.cancel()
-		s.isRunning = false
-		log.Println("🔒 Security Scheduler Service stopped")
+	if s.isRunning {
+		return fmt.Errorf("security scheduler is already running")
 	}
-}
-
-// run is the main scheduler loop
-func (s *SecuritySchedulerService) run() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	// Run initial check
-	s.checkAndRunTasks()
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			s.checkAndRunTasks()
-		}
+	if s.registry == nil {
+		return fmt.Errorf("security scheduler has no job registry")
 	}
-}
 
-// checkAndRunTasks checks and runs due security tasks
-func (s *SecuritySchedulerService) checkAndRunTasks() {
-	s.mu.RLock()
-	tasksToRun := make([]SecurityScheduledTaskType, 0)
-	now := time.Now()
-
-	for taskType, task := range s.tasks {
-		if task.Enabled && task.Status != SecurityTaskRunning && now.After(task.NextRun) {
-			tasksToRun = append(tasksToRun, taskType)
+	for _, task := range s.tasks {
+		taskType := task.Type
+		err := s.registry.Register(JobDefinition{
+			Name:        string(taskType),
+			Scheduler:   securitySchedulerName,
+			Description: task.Name + ": " + task.Description,
+			Schedule:    task.Schedule,
+			Timeout:     task.Timeout,
+			// Jadwal dilanjutkan dari riwayat: restart/failover tidak mereset atau mengulang task
+			CatchUp: true,
+			Run: func(ctx context.Context) (string, error) {
+				return s.runTask(ctx, taskType)
+			},
+		})
+		if err != nil {
+			log.Printf("❌ Failed to register security task %s: %v", taskType, err)
 		}
 	}
-	s.mu.RUnlock()
+	s.isRunning = true
 
-	// Run each due task
-	for _, taskType := range tasksToRun {
-		go s.executeTask(taskType)
-	}
+	log.Println("🔒 Security Scheduler Service started")
+	return nil
 }
 
-// executeTask executes a single security task
-func (s *SecuritySchedulerService) executeTask(taskType SecurityScheduledTaskType) {
+// Stop removes the security tasks from the job registry
+func (s *SecuritySchedulerService) Stop() {
 	s.mu.Lock()
-	task, exists := s.tasks[taskType]
-	if !exists || task.Status == SecurityTaskRunning {
-		s.mu.Unlock()
-		return
-	}
-	task.Status = SecurityTaskRunning
-	s.mu.Unlock()
+	defer s.mu.Unlock()
 
-	// Create execution log
-	execLog := SecurityTaskExecutionLog{
-		ID:        fmt.Sprintf("sec_exec_%s_%d", taskType, time.Now().UnixNano()),
-		TaskType:  taskType,
-		StartedAt: time.Now(),
-		Status:    SecurityTaskRunning,
+	if s.isRunning {
+		s.registry.Remove(securitySchedulerName)
+		s.isRunning = false
+		log.Println("🔒 Security Scheduler Service stopped")
 	}
+}
 
-	// Execute the task
-	var result string
-	var err error
-
+// runTask dispatches a security task to its implementation
+func (s *SecuritySchedulerService) runTask(ctx context.Context, taskType SecurityScheduledTaskType) (string, error) {
 	switch taskType {
 	case SecurityTaskAudit:
-		result, err = s.runSecurityAudit()
+		return s.runSecurityAudit(ctx)
 	case SecurityTaskVulnerabilityScan:
-		result, err = s.runVulnerabilityScan()
+		return s.runVulnerabilityScan(ctx)
 	case SecurityTaskSessionCleanup:
-		result, err = s.runSessionCleanup()
+		return s.runSessionCleanup(ctx)
 	case SecurityTaskTokenCleanup:
-		result, err = s.runTokenCleanup()
+		return s.runTokenCleanup(ctx)
 	case SecurityTaskAuditLogCleanup:
-		result, err = s.runAuditLogCleanup()
+		return s.runAuditLogCleanup(ctx)
 	case SecurityTaskBlockedIPCleanup:
-		result, err = s.runBlockedIPCleanup()
+		return s.runBlockedIPCleanup(ctx)
 	case SecurityTaskPasswordExpiry:
-		result, err = s.runPasswordExpiryCheck()
+		return s.runPasswordExpiryCheck(ctx)
 	case SecurityTaskInactiveAccounts:
-		result, err = s.runInactiveAccountsCheck()
+		return s.runInactiveAccountsCheck(ctx)
 	case SecurityTaskDatabaseOptimize:
-		result, err = s.runDatabaseOptimize()
+		return s.runDatabaseOptimize(ctx)
 	case SecurityTaskSecurityReport:
-		result, err = s.runSecurityReport()
-	default:
-		err = fmt.Errorf("unknown security task type: %s", taskType)
-	}
-
-	// Update execution log
-	now := time.Now()
-	execLog.CompletedAt = &now
-	execLog.Duration = now.Sub(execLog.StartedAt).String()
-
-	if err != nil {
-		execLog.Status = SecurityTaskFailed
-		execLog.Error = err.Error()
-		log.Printf("❌ Security Task %s failed: %v", taskType, err)
-	} else {
-		execLog.Status = SecurityTaskCompleted
-		execLog.Result = result
-		log.Printf("✅ Security Task %s completed: %s", taskType, result)
+		return s.runSecurityReport(ctx)
+	case SecurityTaskJobRunCleanup:
+		return s.runJobRunCleanup(ctx)
 	}
+	return "", fmt.Errorf("unknown security task type: %s", taskType)
+}
 
-	// Update task status
-	s.mu.Lock()
-	task.Status = execLog.Status
-	task.LastRun = &now
-	task.NextRun = now.Add(task.Interval)
-
-	// Store execution log (keep last 100)
-	s.executionLogs = append(s.executionLogs, execLog)
-	if len(s.executionLogs) > 100 {
-		s.executionLogs = s.executionLogs[1:]
+// jobRuns persisted run history (nil when not configured)
+func (s *SecuritySchedulerService) jobRuns() *JobRunService {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.registry == nil {
+		return nil
 	}
-	s.mu.Unlock()
+	return s.registry.JobRuns()
 }
 
 // Task execution functions
 
-func (s *SecuritySchedulerService) runSecurityAudit() (string, error) {
+func (s *SecuritySchedulerService) runSecurityAudit(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	report, err := s.auditService.RunFullAudit()
 	if err != nil {
 		return "", err
@@ -385,7 +325,10 @@ func (s *SecuritySchedulerService) runSecurityAudit() (string, error) {
 	return fmt.Sprintf("Score: %d%%, Status: %s, Findings: %d", report.OverallScore, report.OverallStatus, len(report.Findings)), nil
 }
 
-func (s *SecuritySchedulerService) runVulnerabilityScan() (string, error) {
+func (s *SecuritySchedulerService) runVulnerabilityScan(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	result, err := s.vulnScanner.RunQuickScan()
 	if err != nil {
 		return "", err
@@ -393,18 +336,19 @@ func (s *SecuritySchedulerService) runVulnerabilityScan() (string, error) {
 	return fmt.Sprintf("Risk Level: %s, Vulnerabilities: %d", result.RiskLevel, len(result.Vulnerabilities)), nil
 }
 
-func (s *SecuritySchedulerService) runSessionCleanup() (string, error) {
+func (s *SecuritySchedulerService) runSessionCleanup(ctx context.Context) (string, error) {
 	// Clean up blacklisted tokens that have expired
 	// JWT tokens expire on their own, but we can clean up blacklist entries
+	db := s.db.WithContext(ctx)
 	totalCleaned := int64(0)
 
 	// Delete old login attempts (more than 7 days old for failed, 30 days for successful)
-	result := s.db.Where("created_at < ? AND success = ?", time.Now().AddDate(0, 0, -7), false).Delete(&models.LoginAttempt{})
+	result := db.Where("created_at < ? AND success = ?", time.Now().AddDate(0, 0, -7), false).Delete(&models.LoginAttempt{})
 	if result.Error == nil {
 		totalCleaned += result.RowsAffected
 	}
 
-	result = s.db.Where("created_at < ? AND success = ?", time.Now().AddDate(0, 0, -30), true).Delete(&models.LoginAttempt{})
+	result = db.Where("created_at < ? AND success = ?", time.Now().AddDate(0, 0, -30), true).Delete(&models.LoginAttempt{})
 	if result.Error == nil {
 		totalCleaned += result.RowsAffected
 	}
@@ -412,11 +356,12 @@ func (s *SecuritySchedulerService) runSessionCleanup() (string, error) {
 	return fmt.Sprintf("Cleaned %d expired session-related records", totalCleaned), nil
 }
 
-func (s *SecuritySchedulerService) runTokenCleanup() (string, error) {
+func (s *SecuritySchedulerService) runTokenCleanup(ctx context.Context) (string, error) {
+	db := s.db.WithContext(ctx)
 	totalCleaned := int64(0)
 
 	// Clean expired password reset tokens
-	result := s.db.Where("expires_at < ?", time.Now()).Delete(&models.PasswordReset{})
+	result := db.Where("expires_at < ?", time.Now()).Delete(&models.PasswordReset{})
 	if result.Error != nil {
 		return "", result.Error
 	}
@@ -425,7 +370,7 @@ func (s *SecuritySchedulerService) runTokenCleanup() (string, error) {
 	// Clean old MFA backup codes that have been used
 	// Clean security events older than 30 days
 	cutoff30Days := time.Now().AddDate(0, 0, -30)
-	result = s.db.Where("created_at < ? AND resolved = ?", cutoff30Days, true).Delete(&models.SecurityEvent{})
+	result = db.Where("created_at < ? AND resolved = ?", cutoff30Days, true).Delete(&models.SecurityEvent{})
 	if result.Error != nil {
 		log.Printf("⚠️ Failed to cleanup security events: %v", result.Error)
 	} else {
@@ -435,21 +380,22 @@ func (s *SecuritySchedulerService) runTokenCleanup() (string, error) {
 	return fmt.Sprintf("Cleaned %d expired tokens/records", totalCleaned), nil
 }
 
-func (s *SecuritySchedulerService) runAuditLogCleanup() (string, error) {
+func (s *SecuritySchedulerService) runAuditLogCleanup(ctx context.Context) (string, error) {
 	// Get retention days from environment
+	db := s.db.WithContext(ctx)
 	retentionDays := getSecurityEnvInt("AUDIT_LOG_RETENTION_DAYS", 90)
 	cutoffDate := time.Now().AddDate(0, 0, -retentionDays)
 
 	// Archive logs before deletion (optional - could write to file or send to external service)
 	var count int64
-	s.db.Model(&models.AuditLog{}).Where("created_at < ?", cutoffDate).Count(&count)
+	db.Model(&models.AuditLog{}).Where("created_at < ?", cutoffDate).Count(&count)
 
 	if count > 0 {
 		log.Printf("📁 Archiving %d audit logs older than %d days...", count, retentionDays)
 	}
 
 	// Delete old audit logs
-	result := s.db.Where("created_at < ?", cutoffDate).Delete(&models.AuditLog{})
+	result := db.Where("created_at < ?", cutoffDate).Delete(&models.AuditLog{})
 	if result.Error != nil {
 		return "", result.Error
 	}
@@ -457,16 +403,17 @@ func (s *SecuritySchedulerService) runAuditLogCleanup() (string, error) {
 	return fmt.Sprintf("Archived/deleted %d old audit logs (retention: %d days)", result.RowsAffected, retentionDays), nil
 }
 
-func (s *SecuritySchedulerService) runBlockedIPCleanup() (string, error) {
+func (s *SecuritySchedulerService) runBlockedIPCleanup(ctx context.Context) (string, error) {
 	// Remove expired IP blocks that aren't permanent
-	result := s.db.Where("blocked_until < ? AND is_permanent = ?", time.Now(), false).Delete(&models.BlockedIP{})
+	db := s.db.WithContext(ctx)
+	result := db.Where("blocked_until < ? AND is_permanent = ?", time.Now(), false).Delete(&models.BlockedIP{})
 	if result.Error != nil {
 		return "", result.Error
 	}
 
 	// Also clean old login attempts (keep last 30 days)
 	cutoff30Days := time.Now().AddDate(0, 0, -30)
-	loginResult := s.db.Where("created_at < ?", cutoff30Days).Delete(&models.LoginAttempt{})
+	loginResult := db.Where("created_at < ?", cutoff30Days).Delete(&models.LoginAttempt{})
 	if loginResult.Error != nil {
 		log.Printf("⚠️ Failed to cleanup login attempts: %v", loginResult.Error)
 	}
@@ -474,13 +421,14 @@ func (s *SecuritySchedulerService) runBlockedIPCleanup() (string, error) {
 	return fmt.Sprintf("Removed %d expired IP blocks, %d old login attempts", result.RowsAffected, loginResult.RowsAffected), nil
 }
 
-func (s *SecuritySchedulerService) runPasswordExpiryCheck() (string, error) {
+func (s *SecuritySchedulerService) runPasswordExpiryCheck(ctx context.Context) (string, error) {
 	// Find users with passwords older than configured days (default 90)
+	db := s.db.WithContext(ctx)
 	maxPasswordAgeDays := getSecurityEnvInt("PASSWORD_MAX_AGE_DAYS", 90)
 	cutoffDate := time.Now().AddDate(0, 0, -maxPasswordAgeDays)
 
 	var expiredUsers []models.User
-	result := s.db.Model(&models.User{}).
+	result := db.Model(&models.User{}).
 		Where("(password_changed_at IS NULL OR password_changed_at < ?)", cutoffDate).
 		Where("auth_provider = ?", "local").
 		Where("deleted_at IS NULL").
@@ -499,7 +447,7 @@ func (s *SecuritySchedulerService) runPasswordExpiryCheck() (string, error) {
 
 	// Create security event if there are expired passwords
 	if count > 0 {
-		s.db.Create(&models.SecurityEvent{
+		db.Create(&models.SecurityEvent{
 			EventType: models.SecurityEventType("PASSWORD_EXPIRY_WARNING"),
 			Details:   fmt.Sprintf("%d users have passwords older than %d days", count, maxPasswordAgeDays),
 			Severity:  models.SeverityWarning,
@@ -511,13 +459,14 @@ func (s *SecuritySchedulerService) runPasswordExpiryCheck() (string, error) {
 	return fmt.Sprintf("Found %d users with expired passwords (>%d days)", count, maxPasswordAgeDays), nil
 }
 
-func (s *SecuritySchedulerService) runInactiveAccountsCheck() (string, error) {
+func (s *SecuritySchedulerService) runInactiveAccountsCheck(ctx context.Context) (string, error) {
 	// Find accounts inactive for more than configured months (default 6)
+	db := s.db.WithContext(ctx)
 	inactiveMonths := getSecurityEnvInt("INACTIVE_ACCOUNT_MONTHS", 6)
 	cutoffDate := time.Now().AddDate(0, -inactiveMonths, 0)
 
 	var inactiveUsers []models.User
-	result := s.db.Model(&models.User{}).
+	result := db.Model(&models.User{}).
 		Where("(last_login_at IS NULL OR last_login_at < ?)", cutoffDate).
 		Where("deleted_at IS NULL").
 		Find(&inactiveUsers)
@@ -535,7 +484,7 @@ func (s *SecuritySchedulerService) runInactiveAccountsCheck() (string, error) {
 
 	// Create security event if there are many inactive accounts
 	if count > 10 {
-		s.db.Create(&models.SecurityEvent{
+		db.Create(&models.SecurityEvent{
 			EventType: models.SecurityEventType("INACTIVE_ACCOUNTS_WARNING"),
 			Details:   fmt.Sprintf("%d accounts inactive for >%d months", count, inactiveMonths),
 			Severity:  models.SeverityInfo,
@@ -547,8 +496,9 @@ func (s *SecuritySchedulerService) runInactiveAccountsCheck() (string, error) {
 	return fmt.Sprintf("Found %d inactive accounts (>%d months)", count, inactiveMonths), nil
 }
 
-func (s *SecuritySchedulerService) runDatabaseOptimize() (string, error) {
+func (s *SecuritySchedulerService) runDatabaseOptimize(ctx context.Context) (string, error) {
 	// Optimize security-related tables
+	db := s.db.WithContext(ctx)
 	tables := []string{
 		"audit_logs",
 		"security_events",
@@ -561,15 +511,18 @@ func (s *SecuritySchedulerService) runDatabaseOptimize() (string, error) {
 	errors := 0
 
 	for _, table := range tables {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
 		// Check if table exists first
 		var count int64
-		s.db.Raw(fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_name = '%s'", table)).Scan(&count)
+		db.Raw(fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_name = '%s'", table)).Scan(&count)
 		if count == 0 {
 			continue
 		}
 
 		// Run OPTIMIZE TABLE
-		if err := s.db.Exec(fmt.Sprintf("OPTIMIZE TABLE %s", table)).Error; err != nil {
+		if err := db.Exec(fmt.Sprintf("OPTIMIZE TABLE %s", table)).Error; err != nil {
 			log.Printf("⚠️ Failed to optimize table %s: %v", table, err)
 			errors++
 		} else {
@@ -579,20 +532,24 @@ func (s *SecuritySchedulerService) runDatabaseOptimize() (string, error) {
 
 	// Also analyze tables for query optimization
 	for _, table := range tables {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
 		var count int64
-		s.db.Raw(fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_name = '%s'", table)).Scan(&count)
+		db.Raw(fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_name = '%s'", table)).Scan(&count)
 		if count == 0 {
 			continue
 		}
 
-		s.db.Exec(fmt.Sprintf("ANALYZE TABLE %s", table))
+		db.Exec(fmt.Sprintf("ANALYZE TABLE %s", table))
 	}
 
 	return fmt.Sprintf("Optimized %d/%d security tables (%d errors)", optimized, len(tables), errors), nil
 }
 
-func (s *SecuritySchedulerService) runSecurityReport() (string, error) {
+func (s *SecuritySchedulerService) runSecurityReport(ctx context.Context) (string, error) {
 	// Generate comprehensive security report
+	db := s.db.WithContext(ctx)
 	report := SecurityWeeklyReport{
 		GeneratedAt: time.Now(),
 		Period: ReportPeriod{
@@ -609,7 +566,7 @@ func (s *SecuritySchedulerService) runSecurityReport() (string, error) {
 		Info     int64
 	}
 
-	s.db.Model(&models.AuditLog{}).
+	db.Model(&models.AuditLog{}).
 		Where("created_at >= ?", report.Period.Start).
 		Count(&auditStats.Total)
 
@@ -620,15 +577,15 @@ func (s *SecuritySchedulerService) runSecurityReport() (string, error) {
 		Critical   int64
 	}
 
-	s.db.Model(&models.SecurityEvent{}).
+	db.Model(&models.SecurityEvent{}).
 		Where("created_at >= ?", report.Period.Start).
 		Count(&eventStats.Total)
 
-	s.db.Model(&models.SecurityEvent{}).
+	db.Model(&models.SecurityEvent{}).
 		Where("created_at >= ? AND resolved = ?", report.Period.Start, false).
 		Count(&eventStats.Unresolved)
 
-	s.db.Model(&models.SecurityEvent{}).
+	db.Model(&models.SecurityEvent{}).
 		Where("created_at >= ? AND severity = ?", report.Period.Start, "CRITICAL").
 		Count(&eventStats.Critical)
 
@@ -638,17 +595,17 @@ func (s *SecuritySchedulerService) runSecurityReport() (string, error) {
 		Failed int64
 	}
 
-	s.db.Model(&models.LoginAttempt{}).
+	db.Model(&models.LoginAttempt{}).
 		Where("created_at >= ?", report.Period.Start).
 		Count(&loginStats.Total)
 
-	s.db.Model(&models.LoginAttempt{}).
+	db.Model(&models.LoginAttempt{}).
 		Where("created_at >= ? AND success = ?", report.Period.Start, false).
 		Count(&loginStats.Failed)
 
 	// Get blocked IPs count
 	var blockedIPCount int64
-	s.db.Model(&models.BlockedIP{}).
+	db.Model(&models.BlockedIP{}).
 		Where("created_at >= ?", report.Period.Start).
 		Count(&blockedIPCount)
 
@@ -705,7 +662,7 @@ Generated: %s
 	log.Println(summary)
 
 	// Store report in database (optional)
-	s.db.Create(&models.SecurityEvent{
+	db.Create(&models.SecurityEvent{
 		EventType: models.SecurityEventType("WEEKLY_SECURITY_REPORT"),
 		Details:   summary,
 		Severity:  models.SeverityInfo,
@@ -716,6 +673,23 @@ Generated: %s
 	return "Weekly security report generated successfully", nil
 }
 
+func (s *SecuritySchedulerService) runJobRunCleanup(ctx context.Context) (string, error) {
+	jobRuns := s.jobRuns()
+	if jobRuns == nil {
+		return "Job run history is not persisted", nil
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	retentionDays := getSecurityEnvInt("JOB_RUN_RETENTION_DAYS", 14)
+	removed, err := jobRuns.Prune(retentionDays, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Removed %d job runs older than %d days", removed, retentionDays), nil
+}
+
 // SecurityWeeklyReport represents a weekly security report
 type SecurityWeeklyReport struct {
 	GeneratedAt time.Time    `json:"generated_at"`
@@ -728,84 +702,92 @@ type ReportPeriod struct {
 	End   time.Time `json:"end"`
 }
 
-// GetTasks returns all scheduled security tasks
-func (s *SecuritySchedulerService) GetTasks() []*SecurityScheduledTask {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	tasks := make([]*SecurityScheduledTask, 0, len(s.tasks))
-	for _, task := range s.tasks {
-		tasks = append(tasks, task)
+// GetTasks returns the status of all scheduled security tasks
+func (s *SecuritySchedulerService) GetTasks() []JobInfo {
+	registry := s.jobRegistry()
+	if registry == nil {
+		return []JobInfo{}
 	}
-	return tasks
+	return registry.List(securitySchedulerName)
 }
 
-// GetTask returns a specific task
-func (s *SecuritySchedulerService) GetTask(taskType SecurityScheduledTaskType) *SecurityScheduledTask {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.tasks[taskType]
-}
-
-// EnableTask enables a security task
+// EnableTask resumes a paused security task
 func (s *SecuritySchedulerService) EnableTask(taskType SecurityScheduledTaskType) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	task, exists := s.tasks[taskType]
-	if !exists {
-		return fmt.Errorf("task not found: %s", taskType)
+	registry := s.jobRegistry()
+	if registry == nil {
+		return fmt.Errorf("security scheduler is not running")
 	}
-	task.Enabled = true
-	return nil
+	return registry.Resume(string(taskType))
 }
 
-// DisableTask disables a security task
+// DisableTask pauses a security task on all instances
 func (s *SecuritySchedulerService) DisableTask(taskType SecurityScheduledTaskType) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	task, exists := s.tasks[taskType]
-	if !exists {
-		return fmt.Errorf("task not found: %s", taskType)
+	registry := s.jobRegistry()
+	if registry == nil {
+		return fmt.Errorf("security scheduler is not running")
 	}
-	task.Enabled = false
-	return nil
+	return registry.Pause(string(taskType))
 }
 
 // RunTaskNow triggers immediate execution of a security task
 func (s *SecuritySchedulerService) RunTaskNow(taskType SecurityScheduledTaskType) error {
-	s.mu.RLock()
-	task, exists := s.tasks[taskType]
-	if !exists {
-		s.mu.RUnlock()
-		return fmt.Errorf("task not found: %s", taskType)
+	registry := s.jobRegistry()
+	if registry == nil {
+		return fmt.Errorf("security scheduler is not running")
 	}
-	if task.Status == SecurityTaskRunning {
-		s.mu.RUnlock()
-		return fmt.Errorf("task is already running: %s", taskType)
-	}
-	s.mu.RUnlock()
-
-	go s.executeTask(taskType)
-	return nil
+	return registry.RunNow(string(taskType))
 }
 
-// GetExecutionLogs returns task execution logs
-func (s *SecuritySchedulerService) GetExecutionLogs(limit int) []SecurityTaskExecutionLog {
+func (s *SecuritySchedulerService) jobRegistry() *JobRegistry {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	return s.registry
+}
 
-	if limit <= 0 || limit > len(s.executionLogs) {
-		limit = len(s.executionLogs)
+// GetExecutionLogs returns the most recent persisted task executions, oldest first
+func (s *SecuritySchedulerService) GetExecutionLogs(limit int) []SecurityTaskExecutionLog {
+	jobRuns := s.jobRuns()
+	if jobRuns == nil {
+		return []SecurityTaskExecutionLog{}
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 100
 	}
 
-	// Return most recent logs
-	start := len(s.executionLogs) - limit
-	if start < 0 {
-		start = 0
+	runs, _, err := jobRuns.GetRuns(securitySchedulerName, "", limit, 0)
+	if err != nil {
+		log.Printf("⚠️ Failed to fetch security task history: %v", err)
+		return []SecurityTaskExecutionLog{}
+	}
+
+	logs := make([]SecurityTaskExecutionLog, len(runs))
+	for i, run := range runs {
+		entry := SecurityTaskExecutionLog{
+			ID:          run.ID,
+			TaskType:    SecurityScheduledTaskType(run.Job),
+			StartedAt:   run.StartedAt,
+			CompletedAt: run.FinishedAt,
+			Status:      SecurityTaskRunning,
+		}
+		if run.FinishedAt != nil {
+			entry.Duration = (time.Duration(run.DurationMs) * time.Millisecond).String()
+		}
+		switch run.Status {
+		case models.JobRunCompleted:
+			entry.Status = SecurityTaskCompleted
+		case models.JobRunFailed:
+			entry.Status = SecurityTaskFailed
+		}
+		if run.Result != nil {
+			entry.Result = *run.Result
+		}
+		if run.Error != nil {
+			entry.Error = *run.Error
+		}
+		// runs terbaru dulu; dibalik agar urutan kronologis
+		logs[len(runs)-1-i] = entry
 	}
-	return s.executionLogs[start:]
+	return logs
 }
 
 // IsRunning returns whether the scheduler is running
@@ -817,41 +799,15 @@ func (s *SecuritySchedulerService) IsRunning() bool {
 
 // GetSchedulerStatus returns comprehensive scheduler status
 func (s *SecuritySchedulerService) GetSchedulerStatus() map[string]interface{} {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	tasks := make([]map[string]interface{}, 0)
-	for _, task := range s.tasks {
-		taskInfo := map[string]interface{}{
-			"type":        task.Type,
-			"name":        task.Name,
-			"description": task.Description,
-			"interval":    task.Interval.String(),
-			"next_run":    task.NextRun,
-			"status":      task.Status,
-			"enabled":     task.Enabled,
-		}
-		if task.LastRun != nil {
-			taskInfo["last_run"] = task.LastRun
-		}
-		tasks = append(tasks, taskInfo)
-	}
-
-	return map[string]interface{}{
-		"is_running":  s.isRunning,
-		"tasks":       tasks,
-		"total_tasks": len(s.tasks),
-		"enabled_tasks": func() int {
-			count := 0
-			for _, t := range s.tasks {
-				if t.Enabled {
-					count++
-				}
-			}
-			return count
-		}(),
-		"recent_executions": len(s.executionLogs),
+	registry := s.jobRegistry()
+	if registry == nil {
+		return map[string]interface{}{"is_running": false}
 	}
+
+	status := registry.Status(securitySchedulerName)
+	status["is_running"] = s.IsRunning()
+	status["recent_executions"] = len(s.GetExecutionLogs(100))
+	return status
 }
 
 // Helper functions
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// TaskSnapshot nilai field task yang dilacak, sudah diformat untuk ditampilkan
+type TaskSnapshot map[string]string
+
+// taskActivityFields field yang dicatat di riwayat, sesuai urutan tampil
+var taskActivityFields = []struct {
+	Field string
+	Label string
+}{
+	{"title", "Judul"},
+	{"description", "Deskripsi"},
+	{"category", "Kategori"},
+	{"deadline", "Deadline"},
+	{"reminder_minutes", "Pengingat"},
+	{"duration_minutes", "Durasi"},
+	{"repeat_type", "Pengulangan"},
+	{"repeat_interval", "Interval pengulangan"},
+	{"repeat_end_date", "Akhir pengulangan"},
+	{"deadline_shift_policy", "Aturan geser deadline"},
+	{"reminder_muted_until", "Pengingat dibisukan sampai"},
+	{"assignees", "Ditugaskan ke"},
+	{"is_completed", "Status"},
+	{"missed_at", "Ditandai terlewat"},
+}
+
+// TaskActivityService mencatat riwayat perubahan task per field dan
+// meneruskannya ke audit log
+type TaskActivityService struct {
+	activityRepo *repository.TaskActivityRepository
+	userRepo     *repository.UserRepository
+	auditService *AuditService
+}
+
+func NewTaskActivityService(
+	activityRepo *repository.TaskActivityRepository,
+	userRepo *repository.UserRepository,
+	auditService *AuditService,
+) *TaskActivityService {
+	return &TaskActivityService{
+		activityRepo: activityRepo,
+		userRepo:     userRepo,
+		auditService: auditService,
+	}
+}
+
+// Snapshot memotret field task yang dilacak. Panggil sebelum task diubah.
+func (s *TaskActivityService) Snapshot(task *models.Task) TaskSnapshot {
+	category := "-"
+	if task.CategoryID != nil && *task.CategoryID != "" {
+		category = *task.CategoryID
+		if task.Category != nil && task.Category.ID == *task.CategoryID {
+			category = task.Category.Name
+		}
+	}
+
+	description := "-"
+	if task.Description != nil && *task.Description != "" {
+		description = truncateActivityText(*task.Description, 80)
+	}
+
+	status := "Belum selesai"
+	if task.IsCompleted {
+		status = "Selesai"
+	}
+
+	shiftPolicy := "Ikut pengaturan user"
+	if task.DeadlineShiftPolicy != nil {
+		shiftPolicy = string(*task.DeadlineShiftPolicy)
+	}
+
+	return TaskSnapshot{
+		"title":                 task.Title,
+		"description":           description,
+		"category":              category,
+		"deadline":              formatActivityTime(task.Deadline, "02 Jan 2006 15:04"),
+		"reminder_minutes":      formatReminderSchedule(task.ReminderSchedule()),
+		"duration_minutes":      formatActivityMinutes(task.DurationMinutes, "%d menit"),
+		"repeat_type":           formatRepeatType(task.RepeatType),
+		"repeat_interval":       fmt.Sprintf("%d", task.RepeatInterval),
+		"repeat_end_date":       formatActivityTime(task.RepeatEndDate, "02 Jan 2006"),
+		"deadline_shift_policy": shiftPolicy,
+		"reminder_muted_until":  formatActivityTime(task.ReminderMutedUntil, "02 Jan 2006 15:04"),
+		"assignees":             s.assigneeNames(task),
+		"is_completed":          status,
+		"missed_at":             formatActivityTime(task.MissedAt, "02 Jan 2006 15:04"),
+	}
+}
+
+// Record menyimpan aktivitas berisi field yang berubah antara before dan after.
+// before nil berarti task baru (created / spawned). Update tanpa perubahan diabaikan.
+func (s *TaskActivityService) Record(actorID string, action models.TaskActivityAction, taskID string, before, after TaskSnapshot, meta RequestMeta) {
+	changes := diffTaskSnapshots(before, after)
+	if before != nil && len(changes) == 0 {
+		return
+	}
+
+	activity := &models.TaskActivity{
+		TaskID:  taskID,
+		UserID:  actorID,
+		Action:  action,
+		Summary: taskActivitySummary(action, changes),
+		Changes: changes,
+	}
+	if err := s.activityRepo.Create(activity); err != nil {
+		log.Printf("⚠️ Failed to record task activity %s for %s: %v", action, taskID, err)
+	}
+
+	if s.auditService == nil {
+		return
+	}
+	if before == nil {
+		s.auditService.LogCreate(&actorID, "tasks", taskID, after, meta.IP, meta.UserAgent, meta.Path, 200, 0)
+		return
+	}
+
+	oldValues := map[string]interface{}{}
+	newValues := map[string]interface{}{}
+	for _, change := range changes {
+		oldValues[change.Field] = change.From
+		newValues[change.Field] = change.To
+	}
+	s.auditService.LogUpdate(&actorID, "tasks", taskID, oldValues, newValues, meta.IP, meta.UserAgent, meta.Path, 200, 0)
+}
+
+// RecordDeletion mencatat penghapusan task ke audit log (riwayat ikut terhapus bersama task)
+func (s *TaskActivityService) RecordDeletion(actorID, taskID string, before TaskSnapshot, meta RequestMeta) {
+	if s.auditService != nil {
+		s.auditService.LogDelete(&actorID, "tasks", taskID, before, meta.IP, meta.UserAgent, meta.Path, 200, 0)
+	}
+}
+
+// GetTimeline riwayat aktivitas task, terbaru dulu
+func (s *TaskActivityService) GetTimeline(taskID string, limit, offset int) ([]models.TaskActivityResponse, int64, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	activities, err := s.activityRepo.FindByTaskID(taskID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.activityRepo.CountByTaskID(taskID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	timeline := make([]models.TaskActivityResponse, 0, len(activities))
+	for _, activity := range activities {
+		timeline = append(timeline, activity.ToResponse())
+	}
+	return timeline, total, nil
+}
+
+func (s *TaskActivityService) assigneeNames(task *models.Task) string {
+	if len(task.Assignees) == 0 {
+		return "-"
+	}
+
+	names := make([]string, 0, len(task.Assignees))
+	for _, assignee := range task.Assignees {
+		name := assignee.UserID
+		if s.userRepo != nil {
+			if user, err := s.userRepo.FindByID(assignee.UserID); err == nil {
+				name = user.Username
+			}
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func diffTaskSnapshots(before, after TaskSnapshot) []models.TaskFieldChange {
+	changes := []models.TaskFieldChange{}
+	for _, field := range taskActivityFields {
+		to := after[field.Field]
+		from := "-"
+		if before != nil {
+			from = before[field.Field]
+		}
+		if from == to || (before == nil && to == "-") {
+			continue
+		}
+		changes = append(changes, models.TaskFieldChange{
+			Field: field.Field,
+			Label: field.Label,
+			From:  from,
+			To:    to,
+		})
+	}
+	return changes
+}
+
+func taskActivitySummary(action models.TaskActivityAction, changes []models.TaskFieldChange) string {
+	labels := make([]string, 0, len(changes))
+	for _, change := range changes {
+		labels = append(labels, strings.ToLower(change.Label))
+	}
+	fields := strings.Join(labels, ", ")
+
+	switch action {
+	case models.TaskActivityCreated:
+		return "Membuat task"
+	case models.TaskActivitySpawned:
+		return "Occurrence berikutnya dibuat otomatis dari task berulang"
+	case models.TaskActivityCompleted:
+		return "Menandai task selesai"
+	case models.TaskActivityReopened:
+		return "Membuka kembali task"
+	case models.TaskActivityAssigned:
+		return "Mengubah penugasan"
+	case models.TaskActivityBulkUpdated:
+		return "Mengubah massal " + fields
+	case models.TaskActivityRescheduled:
+		return "Menyesuaikan task karena cuti: " + fields
+	}
+	return "Mengubah " + fields
+}
+
+func formatActivityTime(t *time.Time, layout string) string {
+	if t == nil {
+		return "-"
+	}
+	return t.In(time.Local).Format(layout)
+}
+
+func formatActivityMinutes(minutes *int, format string) string {
+	if minutes == nil {
+		return "-"
+	}
+	return fmt.Sprintf(format, *minutes)
+}
+
+func formatReminderSchedule(offsets []int) string {
+	if len(offsets) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(offsets))
+	for _, offset := range offsets {
+		parts = append(parts, fmt.Sprintf("%d", offset))
+	}
+	return strings.Join(parts, ", ") + " menit sebelum deadline"
+}
+
+func formatRepeatType(repeatType models.RepeatType) string {
+	switch repeatType {
+	case models.RepeatHourly:
+		return "Per jam"
+	case models.RepeatDaily:
+		return "Harian"
+	case models.RepeatWeekly:
+		return "Mingguan"
+	case models.RepeatMonthly:
+		return "Bulanan"
+	}
+	return "Tidak berulang"
+}
+
+func truncateActivityText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "…"
+}
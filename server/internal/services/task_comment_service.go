@@ -0,0 +1,321 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+	"github.com/workradar/server/pkg/utils"
+	"gorm.io/gorm"
+)
+
+const taskCommentMaxLength = 2000
+
+// mentionPattern @username; nama dengan spasi ditulis tanpa spasi (@budisantoso)
+// atau memakai bagian depan email (@budi.s)
+var mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}._-]+)`)
+
+type TaskCommentService struct {
+	commentRepo         *repository.TaskCommentRepository
+	userRepo            *repository.UserRepository
+	taskService         *TaskService
+	workspaceService    *WorkspaceService
+	notificationService *NotificationService
+}
+
+func NewTaskCommentService(
+	commentRepo *repository.TaskCommentRepository,
+	userRepo *repository.UserRepository,
+	taskService *TaskService,
+	workspaceService *WorkspaceService,
+	notificationService *NotificationService,
+) *TaskCommentService {
+	return &TaskCommentService{
+		commentRepo:         commentRepo,
+		userRepo:            userRepo,
+		taskService:         taskService,
+		workspaceService:    workspaceService,
+		notificationService: notificationService,
+	}
+}
+
+// GetComments mendapatkan thread komentar task: komentar utama beserta balasannya
+func (s *TaskCommentService) GetComments(userID, taskID string) ([]models.TaskCommentResponse, error) {
+	if _, err := s.taskService.GetTaskByID(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.FindByTaskID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := []models.TaskCommentResponse{}
+	index := map[string]int{}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			index[comment.ID] = len(threads)
+			threads = append(threads, comment.ToResponse())
+		}
+	}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			continue
+		}
+		if i, ok := index[*comment.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, comment.ToResponse())
+		}
+	}
+
+	return threads, nil
+}
+
+// CreateComment menambah komentar atau balasan, lalu memberi tahu user yang di-@mention
+func (s *TaskCommentService) CreateComment(userID, taskID string, data TaskCommentDTO) (*models.TaskCommentResponse, error) {
+	task, err := s.taskService.GetTaskByID(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := sanitizeCommentBody(data.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.TaskComment{
+		TaskID: task.ID,
+		UserID: userID,
+		Body:   body,
+	}
+
+	if data.ParentID != nil && *data.ParentID != "" {
+		parent, err := s.commentRepo.FindByID(*data.ParentID)
+		if err != nil || parent.TaskID != task.ID {
+			return nil, errors.New("parent comment not found")
+		}
+		// Balasan dari balasan tetap masuk ke thread komentar utama
+		comment.ParentID = &parent.ID
+		if parent.ParentID != nil {
+			comment.ParentID = parent.ParentID
+		}
+	}
+
+	comment.Mentions = s.resolveMentions(task, userID, data.Body)
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(userID, task, comment, comment.Mentions)
+
+	return s.reload(comment)
+}
+
+// UpdateComment mengubah isi komentar (penulis saja); mention baru ikut diberi tahu
+func (s *TaskCommentService) UpdateComment(userID, taskID, commentID string, data TaskCommentDTO) (*models.TaskCommentResponse, error) {
+	task, err := s.taskService.GetTaskByID(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.findComment(task.ID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID != userID {
+		return nil, errors.New("unauthorized: only the author can edit a comment")
+	}
+
+	body, err := sanitizeCommentBody(data.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make(map[string]bool, len(comment.Mentions))
+	for _, id := range comment.Mentions {
+		previous[id] = true
+	}
+
+	now := time.Now()
+	comment.Body = body
+	comment.Mentions = s.resolveMentions(task, userID, data.Body)
+	comment.EditedAt = &now
+
+	if err := s.commentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+
+	added := []string{}
+	for _, id := range comment.Mentions {
+		if !previous[id] {
+			added = append(added, id)
+		}
+	}
+	s.notifyMentions(userID, task, comment, added)
+
+	return s.reload(comment)
+}
+
+// DeleteComment menghapus komentar beserta balasannya. Boleh oleh penulis,
+// pembuat task, atau owner/admin workspace.
+func (s *TaskCommentService) DeleteComment(userID, taskID, commentID string) error {
+	task, err := s.taskService.GetTaskByID(userID, taskID)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.findComment(task.ID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID != userID && task.UserID != userID {
+		if task.WorkspaceID == nil {
+			return errors.New("unauthorized")
+		}
+		if _, err := s.workspaceService.RequireManager(*task.WorkspaceID, userID); err != nil {
+			return errors.New("unauthorized: only the author, task creator or a workspace admin can delete a comment")
+		}
+	}
+
+	return s.commentRepo.Delete(comment.ID)
+}
+
+func (s *TaskCommentService) findComment(taskID, commentID string) (*models.TaskComment, error) {
+	comment, err := s.commentRepo.FindByID(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	if comment.TaskID != taskID {
+		return nil, errors.New("comment not found")
+	}
+	return comment, nil
+}
+
+func (s *TaskCommentService) reload(comment *models.TaskComment) (*models.TaskCommentResponse, error) {
+	loaded, err := s.commentRepo.FindByID(comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	response := loaded.ToResponse()
+	return &response, nil
+}
+
+// resolveMentions mencocokkan @mention dengan user yang bisa melihat task:
+// member workspace untuk task bersama, pemilik untuk task pribadi
+func (s *TaskCommentService) resolveMentions(task *models.Task, authorID, raw string) []string {
+	mentions := []string{}
+
+	matches := mentionPattern.FindAllStringSubmatch(raw, -1)
+	if len(matches) == 0 {
+		return mentions
+	}
+
+	var candidates []models.UserSummary
+	if task.WorkspaceID != nil {
+		members, err := s.workspaceService.GetMembers(*task.WorkspaceID, authorID)
+		if err != nil {
+			log.Printf("⚠️ Failed to load workspace members for mentions: %v", err)
+			return mentions
+		}
+		for _, member := range members {
+			if member.User != nil {
+				candidates = append(candidates, *member.User)
+			}
+		}
+	} else if owner, err := s.userRepo.FindByID(task.UserID); err == nil {
+		candidates = append(candidates, owner.ToSummary())
+	}
+
+	seen := map[string]bool{}
+	for _, match := range matches {
+		handle := strings.ToLower(strings.TrimRight(match[1], "._-"))
+		if handle == "" {
+			continue
+		}
+		for _, candidate := range candidates {
+			if seen[candidate.ID] || !matchesMention(candidate, handle) {
+				continue
+			}
+			seen[candidate.ID] = true
+			mentions = append(mentions, candidate.ID)
+		}
+	}
+
+	return mentions
+}
+
+func matchesMention(user models.UserSummary, handle string) bool {
+	if strings.ToLower(strings.ReplaceAll(user.Username, " ", "")) == handle {
+		return true
+	}
+	if at := strings.Index(user.Email, "@"); at > 0 {
+		return strings.ToLower(user.Email[:at]) == handle
+	}
+	return false
+}
+
+// notifyMentions push notifikasi ke user yang di-@mention (kecuali penulis sendiri)
+func (s *TaskCommentService) notifyMentions(authorID string, task *models.Task, comment *models.TaskComment, userIDs []string) {
+	if s.notificationService == nil || len(userIDs) == 0 {
+		return
+	}
+
+	author := "Seseorang"
+	if user, err := s.userRepo.FindByID(authorID); err == nil {
+		author = user.Username
+	}
+
+	excerpt := []rune(html.UnescapeString(comment.Body))
+	if len(excerpt) > 100 {
+		excerpt = append(excerpt[:100], '…')
+	}
+
+	title := fmt.Sprintf("💬 %s menyebutmu di \"%s\"", author, task.Title)
+	data := map[string]string{
+		"task_id":    task.ID,
+		"comment_id": comment.ID,
+	}
+
+	for _, userID := range userIDs {
+		if userID == authorID {
+			continue
+		}
+		if err := s.notificationService.SendCommentMention(userID, title, string(excerpt), data); err != nil {
+			log.Printf("⚠️ Comment mention push to %s skipped: %v", userID, err)
+		}
+	}
+}
+
+// sanitizeCommentBody membersihkan isi komentar dengan sanitasi standar (HTML di-escape)
+func sanitizeCommentBody(raw string) (string, error) {
+	body := strings.TrimSpace(raw)
+	if body == "" {
+		return "", errors.New("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > taskCommentMaxLength {
+		return "", fmt.Errorf("comment is too long (max %d characters)", taskCommentMaxLength)
+	}
+
+	config := utils.DefaultSanitizeConfig()
+	config.MaxLength = 0 // panjang sudah divalidasi sebelum escape
+	return utils.SanitizeString(utils.SanitizeHTML(body), config), nil
+}
+
+// DTOs
+
+type TaskCommentDTO struct {
+	Body     string  `json:"body"`
+	ParentID *string `json:"parent_id"` // balas komentar lain
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
@@ -10,58 +11,125 @@ import (
 	"gorm.io/gorm"
 )
 
+// maxBulkTasks batas jumlah task per bulk update
+const maxBulkTasks = 100
+
+// maxReminderOffsets batas jumlah pengingat tambahan per task
+const maxReminderOffsets = 5
+
 type TaskService struct {
-	taskRepo     *repository.TaskRepository
-	categoryRepo *repository.CategoryRepository
+	taskRepo            *repository.TaskRepository
+	categoryRepo        *repository.CategoryRepository
+	userRepo            *repository.UserRepository
+	workspaceService    *WorkspaceService
+	workCalendar        *WorkCalendarService
+	activityService     *TaskActivityService
+	notificationService *NotificationService
+	botMessageService   *BotMessageService
+	broker              EventBroker
 }
 
 func NewTaskService(
 	taskRepo *repository.TaskRepository,
 	categoryRepo *repository.CategoryRepository,
+	userRepo *repository.UserRepository,
+	workspaceService *WorkspaceService,
+	workCalendar *WorkCalendarService,
+	activityService *TaskActivityService,
+	notificationService *NotificationService,
+	botMessageService *BotMessageService,
+	broker EventBroker,
 ) *TaskService {
 	return &TaskService{
-		taskRepo:     taskRepo,
-		categoryRepo: categoryRepo,
+		taskRepo:            taskRepo,
+		categoryRepo:        categoryRepo,
+		userRepo:            userRepo,
+		workspaceService:    workspaceService,
+		workCalendar:        workCalendar,
+		activityService:     activityService,
+		notificationService: notificationService,
+		botMessageService:   botMessageService,
+		broker:              broker,
 	}
 }
 
 // CreateTask membuat task baru
-func (s *TaskService) CreateTask(userID string, data CreateTaskDTO) (*models.Task, error) {
+func (s *TaskService) CreateTask(userID string, data CreateTaskDTO, meta RequestMeta) (*models.Task, error) {
 	// Validasi title
 	if data.Title == "" {
 		return nil, errors.New("title is required")
 	}
 
-	// Validasi category (jika ada)
+	// Task bersama: user harus member workspace
+	if data.WorkspaceID != nil && *data.WorkspaceID == "" {
+		data.WorkspaceID = nil
+	}
+	if data.WorkspaceID != nil {
+		if _, err := s.workspaceService.RequireMember(*data.WorkspaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validasi category (jika ada); kategori bersama membawa task ke workspace-nya
 	if data.CategoryID != nil {
-		category, err := s.categoryRepo.FindByID(*data.CategoryID)
-		if err != nil || category.UserID != userID {
-			return nil, errors.New("invalid category")
+		workspaceID, err := s.validateCategory(userID, *data.CategoryID, data.WorkspaceID)
+		if err != nil {
+			return nil, err
 		}
+		data.WorkspaceID = workspaceID
+	}
+
+	if data.DeadlineShiftPolicy != nil && !data.DeadlineShiftPolicy.IsValid() {
+		return nil, errors.New("invalid deadline_shift_policy")
+	}
+
+	assigneeIDs, err := s.validateAssignees(userID, userID, data.WorkspaceID, data.AssigneeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reminderOffsets, err := validateReminderOffsets(data.ReminderMinutes, data.ReminderOffsets)
+	if err != nil {
+		return nil, err
 	}
 
 	// Buat task
 	task := &models.Task{
 		UserID:          userID,
 		CategoryID:      data.CategoryID,
+		WorkspaceID:     data.WorkspaceID,
 		Title:           data.Title,
 		Description:     data.Description,
 		Deadline:        data.Deadline,
 		ReminderMinutes: data.ReminderMinutes,
+		ReminderOffsets: reminderOffsets,
 		RepeatType:      data.RepeatType,
 		RepeatInterval:  data.RepeatInterval,
 		RepeatEndDate:   data.RepeatEndDate,
 		IsCompleted:     false,
+
+		DeadlineShiftPolicy: data.DeadlineShiftPolicy,
 	}
 
+	shift := s.applyDeadlineShift(task, task.Deadline)
+
 	if err := s.taskRepo.Create(task); err != nil {
 		return nil, err
 	}
 
-	// Load category relation
-	if task.CategoryID != nil {
+	if len(assigneeIDs) > 0 {
+		if err := s.taskRepo.ReplaceAssignees(task.ID, userID, assigneeIDs); err != nil {
+			return nil, err
+		}
+		s.notifyAssignment(userID, task, assigneeIDs, nil)
+	}
+
+	// Load category & assignee relation
+	if task.CategoryID != nil || len(assigneeIDs) > 0 {
 		task, _ = s.taskRepo.FindByID(task.ID)
 	}
+	task.DeadlineShift = shift
+	s.recordActivity(userID, models.TaskActivityCreated, task, nil, meta)
 
 	return task, nil
 }
@@ -74,6 +142,160 @@ func (s *TaskService) GetTasks(userID string, categoryID *string) ([]models.Task
 	return s.taskRepo.FindByUserID(userID)
 }
 
+// GetWorkspaceTasks mendapatkan semua tasks bersama di workspace (member saja)
+func (s *TaskService) GetWorkspaceTasks(userID, workspaceID string, categoryID *string) ([]models.Task, error) {
+	if _, err := s.workspaceService.RequireMember(workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.FindByWorkspaceID(workspaceID, categoryID)
+}
+
+// GetAssignedToMe mendapatkan task bersama yang ditugaskan ke user
+func (s *TaskService) GetAssignedToMe(userID string) ([]models.Task, error) {
+	return s.taskRepo.FindAssignedToUser(userID)
+}
+
+// GetAssignedByMe mendapatkan task yang ditugaskan user ke member lain
+func (s *TaskService) GetAssignedByMe(userID string) ([]models.Task, error) {
+	return s.taskRepo.FindAssignedByUser(userID)
+}
+
+// SetAssignees mengganti assignee task bersama; list kosong = lepas semua assignee
+func (s *TaskService) SetAssignees(userID, taskID string, assigneeIDs []string, meta RequestMeta) (*models.Task, error) {
+	task, err := s.GetTaskByID(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := s.snapshot(task)
+	if err := s.reassign(userID, task, assigneeIDs); err != nil {
+		return nil, err
+	}
+
+	task, err = s.taskRepo.FindByID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordActivity(userID, models.TaskActivityAssigned, task, before, meta)
+	return task, nil
+}
+
+// reassign memvalidasi & menyimpan assignee baru lalu memberi tahu yang ditambah / dilepas
+func (s *TaskService) reassign(actorID string, task *models.Task, assigneeIDs []string) error {
+	if task.WorkspaceID == nil && len(assigneeIDs) == 0 && len(task.Assignees) == 0 {
+		return nil
+	}
+
+	ids, err := s.validateAssignees(actorID, task.UserID, task.WorkspaceID, assigneeIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := s.taskRepo.ReplaceAssignees(task.ID, actorID, ids); err != nil {
+		return err
+	}
+
+	s.notifyAssignment(actorID, task, ids, task.AssigneeIDs())
+	return nil
+}
+
+// validateAssignees assignee hanya untuk task bersama, harus member workspace, dan
+// hanya pembuat task atau owner/admin workspace yang boleh menugaskan
+func (s *TaskService) validateAssignees(actorID, creatorID string, workspaceID *string, assigneeIDs []string) ([]string, error) {
+	ids := make([]string, 0, len(assigneeIDs))
+	seen := map[string]bool{}
+	for _, id := range assigneeIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		if workspaceID == nil {
+			return ids, nil
+		}
+	} else if workspaceID == nil {
+		return nil, errors.New("only workspace tasks can be assigned")
+	}
+
+	if actorID != creatorID {
+		if _, err := s.workspaceService.RequireManager(*workspaceID, actorID); err != nil {
+			return nil, errors.New("unauthorized: only the task creator or a workspace admin can assign")
+		}
+	}
+
+	for _, id := range ids {
+		if _, err := s.workspaceService.RequireMember(*workspaceID, id); err != nil {
+			return nil, fmt.Errorf("user %s is not a member of this workspace", id)
+		}
+	}
+
+	return ids, nil
+}
+
+// notifyAssignment push FCM + bot message ke user yang baru ditugaskan atau dilepas
+func (s *TaskService) notifyAssignment(actorID string, task *models.Task, current, previous []string) {
+	before := make(map[string]bool, len(previous))
+	for _, id := range previous {
+		before[id] = true
+	}
+	after := make(map[string]bool, len(current))
+	for _, id := range current {
+		after[id] = true
+	}
+
+	actor := s.displayName(actorID)
+	for _, id := range current {
+		if !before[id] && id != actorID {
+			s.notify(id, task, "📌 Task Baru Untukmu",
+				fmt.Sprintf("%s menugaskan \"%s\" kepadamu.", actor, task.Title), "assigned")
+		}
+	}
+	for _, id := range previous {
+		if !after[id] && id != actorID {
+			s.notify(id, task, "↩️ Penugasan Dilepas",
+				fmt.Sprintf("%s melepas penugasanmu dari \"%s\".", actor, task.Title), "unassigned")
+		}
+	}
+}
+
+func (s *TaskService) notify(userID string, task *models.Task, title, body, action string) {
+	if s.notificationService != nil {
+		data := map[string]string{
+			"task_id": task.ID,
+			"action":  action,
+		}
+		if task.Deadline != nil {
+			data["deadline"] = task.Deadline.Format(time.RFC3339)
+		}
+		if err := s.notificationService.SendTaskAssignment(userID, title, body, data); err != nil {
+			log.Printf("⚠️ Task assignment push to %s skipped: %v", userID, err)
+		}
+	}
+
+	if s.botMessageService != nil {
+		metadata := map[string]interface{}{
+			"task_id":      task.ID,
+			"workspace_id": task.WorkspaceID,
+			"action":       action,
+		}
+		if _, err := s.botMessageService.SendMessage(userID, models.MessageTypeTask, title, body, metadata); err != nil {
+			log.Printf("⚠️ Failed to send task assignment message to %s: %v", userID, err)
+		}
+	}
+}
+
+func (s *TaskService) displayName(userID string) string {
+	if s.userRepo != nil {
+		if user, err := s.userRepo.FindByID(userID); err == nil {
+			return user.Username
+		}
+	}
+	return "Seseorang"
+}
+
 // GetTaskByID mendapatkan task by ID
 func (s *TaskService) GetTaskByID(userID, taskID string) (*models.Task, error) {
 	task, err := s.taskRepo.FindByID(taskID)
@@ -84,21 +306,89 @@ func (s *TaskService) GetTaskByID(userID, taskID string) (*models.Task, error) {
 		return nil, err
 	}
 
-	// Verify ownership
+	// Verify ownership; task bersama bisa diakses semua member workspace
 	if task.UserID != userID {
-		return nil, errors.New("unauthorized")
+		if task.WorkspaceID == nil {
+			return nil, errors.New("unauthorized")
+		}
+		if _, err := s.workspaceService.RequireMember(*task.WorkspaceID, userID); err != nil {
+			return nil, errors.New("unauthorized")
+		}
 	}
 
 	return task, nil
 }
 
+// validateCategory memastikan kategori bisa dipakai user. Mengembalikan workspace
+// task: workspace kategori untuk kategori bersama, atau workspaceID untuk kategori pribadi.
+func (s *TaskService) validateCategory(userID, categoryID string, workspaceID *string) (*string, error) {
+	category, err := s.categoryRepo.FindByID(categoryID)
+	if err != nil {
+		return nil, errors.New("invalid category")
+	}
+
+	if category.WorkspaceID == nil {
+		// Kategori pribadi hanya untuk task pribadi milik pemilik kategori
+		if category.UserID != userID || workspaceID != nil {
+			return nil, errors.New("invalid category")
+		}
+		return nil, nil
+	}
+
+	if workspaceID != nil && *workspaceID != *category.WorkspaceID {
+		return nil, errors.New("invalid category")
+	}
+	if _, err := s.workspaceService.RequireMember(*category.WorkspaceID, userID); err != nil {
+		return nil, errors.New("invalid category")
+	}
+	return category.WorkspaceID, nil
+}
+
 // UpdateTask memperbarui task
-func (s *TaskService) UpdateTask(userID, taskID string, data UpdateTaskDTO) (*models.Task, error) {
+func (s *TaskService) UpdateTask(userID, taskID string, data UpdateTaskDTO, meta RequestMeta) (*models.Task, error) {
+	return s.updateTask(userID, taskID, data, models.TaskActivityUpdated, meta)
+}
+
+// BulkUpdateTasks menerapkan perubahan yang sama ke banyak task. Task yang gagal
+// (tidak ditemukan, tanpa akses, validasi) dilaporkan tanpa membatalkan yang lain.
+func (s *TaskService) BulkUpdateTasks(userID string, data BulkUpdateTaskDTO, meta RequestMeta) (*BulkUpdateTaskResult, error) {
+	if len(data.TaskIDs) == 0 {
+		return nil, errors.New("task_ids is required")
+	}
+	if len(data.TaskIDs) > maxBulkTasks {
+		return nil, fmt.Errorf("too many tasks (max %d)", maxBulkTasks)
+	}
+
+	result := &BulkUpdateTaskResult{
+		Tasks:  []models.Task{},
+		Failed: []BulkTaskFailure{},
+	}
+	seen := map[string]bool{}
+	for _, taskID := range data.TaskIDs {
+		if taskID == "" || seen[taskID] {
+			continue
+		}
+		seen[taskID] = true
+
+		task, err := s.updateTask(userID, taskID, data.Changes, models.TaskActivityBulkUpdated, meta)
+		if err != nil {
+			result.Failed = append(result.Failed, BulkTaskFailure{TaskID: taskID, Error: err.Error()})
+			continue
+		}
+		result.Tasks = append(result.Tasks, *task)
+	}
+
+	return result, nil
+}
+
+func (s *TaskService) updateTask(userID, taskID string, data UpdateTaskDTO, action models.TaskActivityAction, meta RequestMeta) (*models.Task, error) {
 	task, err := s.GetTaskByID(userID, taskID)
 	if err != nil {
 		return nil, err
 	}
 
+	before := s.snapshot(task)
+
 	// Update fields if provided
 	if data.Title != nil {
 		if *data.Title == "" {
@@ -108,11 +398,10 @@ func (s *TaskService) UpdateTask(userID, taskID string, data UpdateTaskDTO) (*mo
 	}
 
 	if data.CategoryID != nil {
-		// Validate category
+		// Validate category (harus dalam scope yang sama dengan task)
 		if *data.CategoryID != "" {
-			category, err := s.categoryRepo.FindByID(*data.CategoryID)
-			if err != nil || category.UserID != userID {
-				return nil, errors.New("invalid category")
+			if _, err := s.validateCategory(userID, *data.CategoryID, task.WorkspaceID); err != nil {
+				return nil, err
 			}
 		}
 		task.CategoryID = data.CategoryID
@@ -122,12 +411,46 @@ func (s *TaskService) UpdateTask(userID, taskID string, data UpdateTaskDTO) (*mo
 		task.Description = data.Description
 	}
 
+	// Deadline baru atau policy berubah: hitung ulang dari deadline yang diminta user
+	requestedDeadline := task.OriginalDeadline
+	if requestedDeadline == nil {
+		requestedDeadline = task.Deadline
+	}
+	recalculateDeadline := false
+
 	if data.Deadline != nil {
-		task.Deadline = data.Deadline
+		requestedDeadline = data.Deadline
+		recalculateDeadline = true
 	}
 
-	if data.ReminderMinutes != nil {
-		task.ReminderMinutes = data.ReminderMinutes
+	if data.DeadlineShiftPolicy != nil {
+		if !data.DeadlineShiftPolicy.IsValid() {
+			return nil, errors.New("invalid deadline_shift_policy")
+		}
+		task.DeadlineShiftPolicy = data.DeadlineShiftPolicy
+		recalculateDeadline = true
+	}
+
+	var shift *models.DeadlineShift
+	if recalculateDeadline {
+		shift = s.applyDeadlineShift(task, requestedDeadline)
+	}
+
+	if data.ReminderMinutes != nil || data.ReminderOffsets != nil {
+		reminderMinutes := task.ReminderMinutes
+		if data.ReminderMinutes != nil {
+			reminderMinutes = data.ReminderMinutes
+		}
+		reminderOffsets := task.ReminderOffsets
+		if data.ReminderOffsets != nil {
+			reminderOffsets = *data.ReminderOffsets
+		}
+		offsets, err := validateReminderOffsets(reminderMinutes, reminderOffsets)
+		if err != nil {
+			return nil, err
+		}
+		task.ReminderMinutes = reminderMinutes
+		task.ReminderOffsets = offsets
 	}
 
 	if data.RepeatType != nil {
@@ -142,6 +465,12 @@ func (s *TaskService) UpdateTask(userID, taskID string, data UpdateTaskDTO) (*mo
 		task.RepeatEndDate = data.RepeatEndDate
 	}
 
+	if data.AssigneeIDs != nil {
+		if err := s.reassign(userID, task, *data.AssigneeIDs); err != nil {
+			return nil, err
+		}
+	}
+
 	if data.IsCompleted != nil {
 		task.IsCompleted = *data.IsCompleted
 		if *data.IsCompleted {
@@ -157,29 +486,51 @@ func (s *TaskService) UpdateTask(userID, taskID string, data UpdateTaskDTO) (*mo
 	}
 
 	// Reload with category
-	task, _ = s.taskRepo.FindByID(task.ID)
+	task, err = s.taskRepo.FindByID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	task.DeadlineShift = shift
+	s.recordActivity(userID, action, task, before, meta)
 	return task, nil
 }
 
 // DeleteTask menghapus task
-func (s *TaskService) DeleteTask(userID, taskID string) error {
+func (s *TaskService) DeleteTask(userID, taskID string, meta RequestMeta) error {
 	// Verify ownership
-	_, err := s.GetTaskByID(userID, taskID)
+	task, err := s.GetTaskByID(userID, taskID)
 	if err != nil {
 		return err
 	}
 
-	return s.taskRepo.Delete(taskID)
+	// Task bersama hanya boleh dihapus pembuatnya atau owner/admin workspace
+	if task.UserID != userID && task.WorkspaceID != nil {
+		if _, err := s.workspaceService.RequireManager(*task.WorkspaceID, userID); err != nil {
+			return errors.New("unauthorized")
+		}
+	}
+
+	if err := s.taskRepo.Delete(taskID); err != nil {
+		return err
+	}
+
+	if s.activityService != nil {
+		s.activityService.RecordDeletion(userID, task.ID, s.activityService.Snapshot(task), meta)
+	}
+	publishTaskEvent(s.broker, RealtimeTaskDeleted, task)
+	return nil
 }
 
 // ToggleTaskComplete toggle status completed task
 // For repeating tasks: marks current as complete and creates next occurrence
-func (s *TaskService) ToggleTaskComplete(userID, taskID string) (*models.Task, error) {
+func (s *TaskService) ToggleTaskComplete(userID, taskID string, meta RequestMeta) (*models.Task, error) {
 	task, err := s.GetTaskByID(userID, taskID)
 	if err != nil {
 		return nil, err
 	}
 
+	before := s.snapshot(task)
+
 	// Toggle completion status
 	task.IsCompleted = !task.IsCompleted
 	if task.IsCompleted {
@@ -188,7 +539,12 @@ func (s *TaskService) ToggleTaskComplete(userID, taskID string) (*models.Task, e
 
 		// If this is a repeating task that's being completed, create next occurrence
 		if task.RepeatType != models.RepeatNone && task.Deadline != nil {
-			nextDeadline := s.calculateNextDeadline(*task.Deadline, task.RepeatType, task.RepeatInterval)
+			// Hitung dari deadline asli supaya pergeseran libur tidak ikut terbawa ke occurrence berikutnya
+			baseDeadline := *task.Deadline
+			if task.OriginalDeadline != nil {
+				baseDeadline = *task.OriginalDeadline
+			}
+			nextDeadline := s.calculateNextDeadline(baseDeadline, task.RepeatType, task.RepeatInterval)
 
 			// Check if next deadline is before repeat end date (if set)
 			shouldCreateNext := true
@@ -201,21 +557,39 @@ func (s *TaskService) ToggleTaskComplete(userID, taskID string) (*models.Task, e
 				newTask := &models.Task{
 					UserID:          task.UserID,
 					CategoryID:      task.CategoryID,
+					WorkspaceID:     task.WorkspaceID,
 					Title:           task.Title,
 					Description:     task.Description,
 					Deadline:        &nextDeadline,
 					ReminderMinutes: task.ReminderMinutes,
+					ReminderOffsets: task.ReminderOffsets,
 					DurationMinutes: task.DurationMinutes,
 					RepeatType:      task.RepeatType,
 					RepeatInterval:  task.RepeatInterval,
 					RepeatEndDate:   task.RepeatEndDate,
 					IsCompleted:     false,
 					CompletedAt:     nil,
+
+					DeadlineShiftPolicy: task.DeadlineShiftPolicy,
+				}
+
+				if task.RepeatType != models.RepeatHourly {
+					s.applyDeadlineShift(newTask, &nextDeadline)
 				}
 
 				if err := s.taskRepo.Create(newTask); err != nil {
 					// Log error but don't fail the completion
 					log.Printf("⚠️ Failed to create next repeat task: %v", err)
+				} else {
+					if len(task.Assignees) > 0 {
+						// Occurrence berikutnya tetap ditugaskan ke orang yang sama
+						if err := s.taskRepo.CopyAssignees(task.Assignees, newTask.ID); err != nil {
+							log.Printf("⚠️ Failed to copy assignees to next repeat task: %v", err)
+						}
+					}
+					if spawned, err := s.taskRepo.FindByID(newTask.ID); err == nil {
+						s.recordActivity(userID, models.TaskActivitySpawned, spawned, nil, meta)
+					}
 				}
 			}
 		}
@@ -227,9 +601,122 @@ func (s *TaskService) ToggleTaskComplete(userID, taskID string) (*models.Task, e
 		return nil, err
 	}
 
+	action := models.TaskActivityReopened
+	if task.IsCompleted {
+		action = models.TaskActivityCompleted
+	}
+	s.recordActivity(userID, action, task, before, meta)
+
 	return task, nil
 }
 
+// GetActivity timeline perubahan task (siapa mengubah apa & kapan)
+func (s *TaskService) GetActivity(userID, taskID string, limit, offset int) ([]models.TaskActivityResponse, int64, error) {
+	if _, err := s.GetTaskByID(userID, taskID); err != nil {
+		return nil, 0, err
+	}
+	if s.activityService == nil {
+		return []models.TaskActivityResponse{}, 0, nil
+	}
+	return s.activityService.GetTimeline(taskID, limit, offset)
+}
+
+func (s *TaskService) snapshot(task *models.Task) TaskSnapshot {
+	if s.activityService == nil {
+		return nil
+	}
+	return s.activityService.Snapshot(task)
+}
+
+// recordActivity mencatat riwayat dan mengirim event real-time; before nil = task baru
+func (s *TaskService) recordActivity(actorID string, action models.TaskActivityAction, task *models.Task, before TaskSnapshot, meta RequestMeta) {
+	eventType := RealtimeTaskUpdated
+	if action == models.TaskActivityCreated || action == models.TaskActivitySpawned {
+		eventType = RealtimeTaskCreated
+	}
+	publishTaskEvent(s.broker, eventType, task)
+
+	if s.activityService == nil {
+		return
+	}
+	s.activityService.Record(actorID, action, task.ID, before, s.activityService.Snapshot(task), meta)
+}
+
+// publishTaskEvent mengirim event task ke pembuat dan semua assignee
+func publishTaskEvent(broker EventBroker, eventType RealtimeEventType, task *models.Task) {
+	if broker == nil {
+		return
+	}
+
+	recipients := append([]string{task.UserID}, task.AssigneeIDs()...)
+	seen := make(map[string]bool, len(recipients))
+	for _, userID := range recipients {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		if eventType == RealtimeTaskDeleted {
+			broker.Publish(userID, eventType, map[string]interface{}{"id": task.ID})
+		} else {
+			broker.Publish(userID, eventType, task)
+		}
+	}
+}
+
+// validateReminderOffsets memvalidasi pengingat (menit sebelum deadline) dan membuang duplikat
+func validateReminderOffsets(reminderMinutes *int, offsets []int) ([]int, error) {
+	if reminderMinutes != nil && (*reminderMinutes < 0 || *reminderMinutes > models.MaxReminderOffsetMinutes) {
+		return nil, fmt.Errorf("reminder_minutes must be between 0 and %d", models.MaxReminderOffsetMinutes)
+	}
+	if len(offsets) > maxReminderOffsets {
+		return nil, fmt.Errorf("at most %d reminders per task", maxReminderOffsets)
+	}
+
+	seen := map[int]bool{}
+	result := make([]int, 0, len(offsets))
+	for _, offset := range offsets {
+		if offset < 0 || offset > models.MaxReminderOffsetMinutes {
+			return nil, fmt.Errorf("reminder_offsets must be between 0 and %d minutes", models.MaxReminderOffsetMinutes)
+		}
+		if seen[offset] || (reminderMinutes != nil && *reminderMinutes == offset) {
+			continue
+		}
+		seen[offset] = true
+		result = append(result, offset)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// applyDeadlineShift sets task.Deadline from the requested deadline, moving it off
+// holidays / leave / non-work days according to the task or user policy
+func (s *TaskService) applyDeadlineShift(task *models.Task, requested *time.Time) *models.DeadlineShift {
+	task.Deadline = requested
+	task.OriginalDeadline = nil
+	// Deadline baru = status overdue lama tidak berlaku lagi
+	task.MissedAt = nil
+	task.OverdueNotifiedAt = nil
+	task.OverdueNotifyCount = 0
+
+	if requested == nil || s.workCalendar == nil {
+		return nil
+	}
+
+	shift := s.workCalendar.ShiftDeadline(task.UserID, *requested, task.DeadlineShiftPolicy)
+	if shift == nil {
+		return nil
+	}
+
+	original := *requested
+	shifted := shift.To
+	task.Deadline = &shifted
+	task.OriginalDeadline = &original
+	return shift
+}
+
 // calculateNextDeadline calculates the next deadline based on repeat type and interval
 func (s *TaskService) calculateNextDeadline(current time.Time, repeatType models.RepeatType, interval int) time.Time {
 	switch repeatType {
@@ -249,14 +736,19 @@ func (s *TaskService) calculateNextDeadline(current time.Time, repeatType models
 // DTOs (Data Transfer Objects)
 
 type CreateTaskDTO struct {
+	WorkspaceID     *string           `json:"workspace_id"` // kosong = task pribadi
 	CategoryID      *string           `json:"category_id"`
 	Title           string            `json:"title"`
 	Description     *string           `json:"description"`
 	Deadline        *time.Time        `json:"deadline"`
 	ReminderMinutes *int              `json:"reminder_minutes"`
+	ReminderOffsets []int             `json:"reminder_offsets"` // mis. [1440, 15] = 1 hari & 15 menit sebelum
 	RepeatType      models.RepeatType `json:"repeat_type"`
 	RepeatInterval  int               `json:"repeat_interval"`
 	RepeatEndDate   *time.Time        `json:"repeat_end_date"`
+	AssigneeIDs     []string          `json:"assignee_ids"` // hanya untuk task bersama
+
+	DeadlineShiftPolicy *models.DeadlineShiftPolicy `json:"deadline_shift_policy"` // kosong = ikut setting user
 }
 
 type UpdateTaskDTO struct {
@@ -265,8 +757,27 @@ type UpdateTaskDTO struct {
 	Description     *string            `json:"description"`
 	Deadline        *time.Time         `json:"deadline"`
 	ReminderMinutes *int               `json:"reminder_minutes"`
+	ReminderOffsets *[]int             `json:"reminder_offsets"` // [] = hapus pengingat tambahan
 	RepeatType      *models.RepeatType `json:"repeat_type"`
 	RepeatInterval  *int               `json:"repeat_interval"`
 	RepeatEndDate   *time.Time         `json:"repeat_end_date"`
 	IsCompleted     *bool              `json:"is_completed"`
+	AssigneeIDs     *[]string          `json:"assignee_ids"` // [] = lepas semua assignee
+
+	DeadlineShiftPolicy *models.DeadlineShiftPolicy `json:"deadline_shift_policy"`
+}
+
+type BulkUpdateTaskDTO struct {
+	TaskIDs []string      `json:"task_ids"`
+	Changes UpdateTaskDTO `json:"changes"`
+}
+
+type BulkUpdateTaskResult struct {
+	Tasks  []models.Task     `json:"tasks"`
+	Failed []BulkTaskFailure `json:"failed"`
+}
+
+type BulkTaskFailure struct {
+	TaskID string `json:"task_id"`
+	Error  string `json:"error"`
 }
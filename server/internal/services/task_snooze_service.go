@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// TaskSnoozeService menunda pengingat task per penerima dan mengirim ulang saat waktunya tiba
+type TaskSnoozeService struct {
+	snoozeRepo          *repository.TaskSnoozeRepository
+	taskRepo            *repository.TaskRepository
+	taskService         *TaskService
+	notificationService *NotificationService
+	preferenceService   *NotificationPreferenceService
+}
+
+func NewTaskSnoozeService(
+	snoozeRepo *repository.TaskSnoozeRepository,
+	taskRepo *repository.TaskRepository,
+	taskService *TaskService,
+	notificationService *NotificationService,
+	preferenceService *NotificationPreferenceService,
+) *TaskSnoozeService {
+	return &TaskSnoozeService{
+		snoozeRepo:          snoozeRepo,
+		taskRepo:            taskRepo,
+		taskService:         taskService,
+		notificationService: notificationService,
+		preferenceService:   preferenceService,
+	}
+}
+
+// Snooze menunda pengingat task untuk user: 10 menit, 1 jam, atau besok pada jam agenda pagi
+func (s *TaskSnoozeService) Snooze(userID, taskID string, option models.SnoozeOption, now time.Time) (*models.TaskSnooze, error) {
+	if !option.IsValid() {
+		return nil, errors.New("invalid duration (use 10m, 1h or tomorrow)")
+	}
+
+	task, err := s.taskService.GetTaskByID(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	// Member workspace lain bisa melihat task, tapi tidak menerima pengingatnya
+	if !task.IsRecipient(userID) {
+		return nil, errors.New("unauthorized: only the task owner or assignees can snooze its reminders")
+	}
+	if task.IsCompleted {
+		return nil, errors.New("task already completed")
+	}
+
+	until, err := s.snoozeUntil(userID, option, now)
+	if err != nil {
+		return nil, err
+	}
+
+	snooze := &models.TaskSnooze{
+		TaskID:       task.ID,
+		UserID:       userID,
+		Deadline:     task.Deadline,
+		Duration:     option,
+		SnoozedUntil: until,
+	}
+	if err := s.snoozeRepo.Save(snooze); err != nil {
+		return nil, err
+	}
+	return s.snoozeRepo.Find(task.ID, userID)
+}
+
+// GetSnooze snooze user yang masih menunggu untuk task (nil jika tidak ada)
+func (s *TaskSnoozeService) GetSnooze(userID, taskID string) (*models.TaskSnooze, error) {
+	if _, err := s.taskService.GetTaskByID(userID, taskID); err != nil {
+		return nil, err
+	}
+	snooze, err := s.snoozeRepo.Find(taskID, userID)
+	if err != nil || snooze == nil || snooze.FiredAt != nil {
+		return nil, err
+	}
+	return snooze, nil
+}
+
+// Unsnooze membatalkan snooze; reminder reguler berjalan lagi
+func (s *TaskSnoozeService) Unsnooze(userID, taskID string) error {
+	if _, err := s.taskService.GetTaskByID(userID, taskID); err != nil {
+		return err
+	}
+	removed, err := s.snoozeRepo.Delete(taskID, userID)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return errors.New("snooze not found")
+	}
+	return nil
+}
+
+// ActiveSnoozes snooze yang sedang berjalan untuk daftar task, key = task + user.
+// Dipakai scheduler reminder untuk melewati reminder reguler selama ditunda.
+func (s *TaskSnoozeService) ActiveSnoozes(taskIDs []string, now time.Time) (map[string]models.TaskSnooze, error) {
+	snoozes, err := s.snoozeRepo.FindPendingByTaskIDs(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[string]models.TaskSnooze, len(snoozes))
+	for _, snooze := range snoozes {
+		if snooze.SnoozedUntil.After(now) {
+			active[snoozeKey(snooze.TaskID, snooze.UserID)] = snooze
+		}
+	}
+	return active, nil
+}
+
+// FireDue mengirim ulang pengingat untuk snooze yang sudah jatuh tempo. Snooze yang
+// task-nya sudah selesai/dihapus, deadline-nya berubah, atau user-nya bukan lagi pemilik
+// maupun assignee dibuang tanpa notifikasi.
+func (s *TaskSnoozeService) FireDue(ctx context.Context, now time.Time) int {
+	snoozes, err := s.snoozeRepo.FindDue(now)
+	if err != nil {
+		log.Printf("❌ Failed to fetch due snoozes: %v", err)
+		return 0
+	}
+
+	sent := 0
+	for i := range snoozes {
+		if ctx.Err() != nil {
+			break // sisanya diproses tick berikutnya
+		}
+		snooze := &snoozes[i]
+		claimed, err := s.snoozeRepo.ClaimFired(snooze, now)
+		if err != nil {
+			log.Printf("❌ Failed to claim snooze %s: %v", snooze.ID, err)
+			continue
+		}
+		if !claimed {
+			continue // diproses instance lain atau diganti snooze baru
+		}
+
+		// User bisa saja sudah di-unassign sejak menunda pengingat
+		task, err := s.taskRepo.FindByID(snooze.TaskID)
+		if err != nil || task.IsCompleted || !sameDeadline(task.Deadline, snooze.Deadline) || !task.IsRecipient(snooze.UserID) {
+			log.Printf("⏭️  Snooze %s for task %s no longer applies, skipping", snooze.ID, snooze.TaskID)
+			continue
+		}
+
+		if err := s.notificationService.SendSnoozedTaskReminder(snooze.UserID, task.ID, task.Title, task.Deadline, snooze.SnoozedUntil); err != nil {
+			log.Printf("❌ Failed to queue snoozed reminder for task %s to user %s: %v", task.ID, snooze.UserID, err)
+			// Lepas claim agar dicoba lagi pada tick berikutnya
+			if err := s.snoozeRepo.ReleaseFired(snooze); err != nil {
+				log.Printf("⚠️ Failed to release snooze %s: %v", snooze.ID, err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// snoozeUntil waktu pengingat dikirim ulang; "besok" memakai zona waktu & jam agenda user
+func (s *TaskSnoozeService) snoozeUntil(userID string, option models.SnoozeOption, now time.Time) (time.Time, error) {
+	switch option {
+	case models.SnoozeTenMinutes:
+		return now.Add(10 * time.Minute), nil
+	case models.SnoozeOneHour:
+		return now.Add(time.Hour), nil
+	}
+
+	settings := &models.NotificationSettings{TimeZone: models.DefaultNotificationTimeZone, AgendaTime: models.DefaultAgendaTime}
+	if s.preferenceService != nil {
+		st, err := s.preferenceService.Settings(userID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		settings = st
+	}
+	minute, err := parseClock(settings.AgendaTime)
+	if err != nil {
+		minute, _ = parseClock(models.DefaultAgendaTime)
+	}
+
+	local := now.In(notificationLocation(settings.TimeZone))
+	tomorrow := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, local.Location())
+	return tomorrow.Add(time.Duration(minute) * time.Minute), nil
+}
+
+// snoozeKey identitas snooze satu penerima untuk satu task
+func snoozeKey(taskID, userID string) string {
+	return taskID + "|" + userID
+}
+
+func sameDeadline(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
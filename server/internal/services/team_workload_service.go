@@ -0,0 +1,247 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+const (
+	// teamWorkloadMaxDays rentang maksimal dashboard tim
+	teamWorkloadMaxDays = 92
+	// teamLeaveLookaheadDays cuti mendatang yang ditampilkan
+	teamLeaveLookaheadDays = 30
+
+	// Ambang sinyal burnout
+	burnoutLoadPerDay     = 8.0 // beban tertimbang per hari
+	burnoutOvertimeTasks  = 5
+	burnoutWeekendTasks   = 2
+	burnoutOverdueBacklog = 5
+
+	BurnoutSignalHighLoad       = "high_load"
+	BurnoutSignalOvertime       = "frequent_overtime"
+	BurnoutSignalWeekendWork    = "weekend_work"
+	BurnoutSignalOverdueBacklog = "overdue_backlog"
+)
+
+// TeamWorkloadService dashboard beban kerja member workspace untuk owner/admin
+type TeamWorkloadService struct {
+	workspaceRepo    *repository.WorkspaceRepository
+	workspaceService *WorkspaceService
+	workloadService  *WorkloadService
+	taskRepo         *repository.TaskRepository
+	leaveRepo        *repository.LeaveRepository
+	holidayService   *HolidayService
+	accessControl    *AccessControlService
+}
+
+func NewTeamWorkloadService(
+	workspaceRepo *repository.WorkspaceRepository,
+	workspaceService *WorkspaceService,
+	workloadService *WorkloadService,
+	taskRepo *repository.TaskRepository,
+	leaveRepo *repository.LeaveRepository,
+	holidayService *HolidayService,
+	accessControl *AccessControlService,
+) *TeamWorkloadService {
+	return &TeamWorkloadService{
+		workspaceRepo:    workspaceRepo,
+		workspaceService: workspaceService,
+		workloadService:  workloadService,
+		taskRepo:         taskRepo,
+		leaveRepo:        leaveRepo,
+		holidayService:   holidayService,
+		accessControl:    accessControl,
+	}
+}
+
+// GetTeamWorkload agregasi beban kerja tiap member dalam rentang tanggal,
+// diurutkan dari yang paling berat
+func (s *TeamWorkloadService) GetTeamWorkload(workspaceID, actorID string, start, end time.Time) (*TeamWorkload, error) {
+	member, err := s.workspaceService.RequireMember(workspaceID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.accessControl.EnforceWorkspaceAccess(member.Role, PermissionWorkspaceWorkloadRead); err != nil {
+		return nil, err
+	}
+
+	if end.Before(start) {
+		return nil, errors.New("end date must be after start date")
+	}
+	days := int(end.Sub(start).Hours()/24) + 1
+	if days > teamWorkloadMaxDays {
+		return nil, errors.New("date range is too long (max 92 days)")
+	}
+
+	members, err := s.workspaceRepo.FindMembers(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := truncateToDate(now)
+	leaveUntil := today.AddDate(0, 0, teamLeaveLookaheadDays)
+
+	team := &TeamWorkload{
+		WorkspaceID: workspaceID,
+		StartDate:   start,
+		EndDate:     end,
+		Days:        days,
+		Members:     make([]MemberWorkload, 0, len(members)),
+	}
+
+	for _, m := range members {
+		if m.User == nil {
+			continue
+		}
+		user := m.User
+
+		item := MemberWorkload{
+			User:           user.ToSummary(),
+			Role:           m.Role,
+			UpcomingLeaves: []TeamLeavePeriod{},
+			BurnoutSignals: []string{},
+		}
+
+		var holidays []time.Time
+		if s.holidayService != nil {
+			if list, err := s.holidayService.GetHolidaysByDateRange(user.ID, start, end); err == nil {
+				for _, h := range list {
+					holidays = append(holidays, h.Date)
+				}
+			}
+		}
+
+		if stats, err := s.workloadService.CalculateWorkspaceWorkload(workspaceID, user.ID, start, end, ParseWorkDaysConfig(user.WorkDays), holidays); err == nil {
+			item.WeightedLoad = roundLoad(stats.CalculatedLoad)
+			item.LoadPerDay = roundLoad(stats.CalculatedLoad / float64(days))
+			item.ScheduledTasks = stats.TotalTasks
+			item.CompletedTasks = stats.RegularTasks + stats.OvertimeTasks + stats.WeekendTasks
+			item.OvertimeTasks = stats.OvertimeTasks
+			item.WeekendTasks = stats.WeekendTasks
+			item.OvertimeHours = roundLoad(stats.OvertimeHours)
+			item.WeekendHours = roundLoad(stats.WeekendHours)
+		} else {
+			log.Printf("⚠️ Failed to calculate workload for %s: %v", user.ID, err)
+		}
+
+		if count, err := s.taskRepo.CountOpenWorkspaceWorkload(workspaceID, user.ID); err == nil {
+			item.OpenTasks = count
+		}
+		if count, err := s.taskRepo.CountOverdueWorkspaceWorkload(workspaceID, user.ID, now); err == nil {
+			item.OverdueTasks = count
+		}
+
+		if leaves, err := s.leaveRepo.FindOverlapping(user.ID, today, leaveUntil, ""); err == nil {
+			// Hanya rentang tanggal cuti yang disetujui; alasan, tipe & komentar tetap privat
+			for _, leave := range leaves {
+				if leave.Status != models.LeaveStatusApproved {
+					continue
+				}
+				item.UpcomingLeaves = append(item.UpcomingLeaves, TeamLeavePeriod{
+					StartDate: leave.Date,
+					EndDate:   leave.LastDate(),
+					HalfDay:   leave.HalfDay,
+				})
+				if !today.Before(truncateToDate(leave.Date)) && !today.After(truncateToDate(leave.LastDate())) {
+					item.OnLeaveToday = true
+				}
+			}
+		}
+
+		item.BurnoutSignals = burnoutSignals(item)
+		item.RiskLevel = burnoutRiskLevel(len(item.BurnoutSignals))
+
+		team.TotalWeightedLoad += item.WeightedLoad
+		team.TotalOpenTasks += item.OpenTasks
+		team.TotalOverdueTasks += item.OverdueTasks
+		if item.RiskLevel == "high" {
+			team.AtRiskMembers++
+		}
+		team.Members = append(team.Members, item)
+	}
+
+	sort.SliceStable(team.Members, func(i, j int) bool {
+		return team.Members[i].WeightedLoad > team.Members[j].WeightedLoad
+	})
+	team.TotalWeightedLoad = roundLoad(team.TotalWeightedLoad)
+
+	return team, nil
+}
+
+func burnoutSignals(item MemberWorkload) []string {
+	signals := []string{}
+	if item.LoadPerDay >= burnoutLoadPerDay {
+		signals = append(signals, BurnoutSignalHighLoad)
+	}
+	if item.OvertimeTasks >= burnoutOvertimeTasks {
+		signals = append(signals, BurnoutSignalOvertime)
+	}
+	if item.WeekendTasks >= burnoutWeekendTasks {
+		signals = append(signals, BurnoutSignalWeekendWork)
+	}
+	if item.OverdueTasks >= burnoutOverdueBacklog {
+		signals = append(signals, BurnoutSignalOverdueBacklog)
+	}
+	return signals
+}
+
+func burnoutRiskLevel(signals int) string {
+	switch {
+	case signals >= 2:
+		return "high"
+	case signals == 1:
+		return "medium"
+	}
+	return "low"
+}
+
+func roundLoad(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// DTOs
+
+type TeamWorkload struct {
+	WorkspaceID       string           `json:"workspace_id"`
+	StartDate         time.Time        `json:"start_date"`
+	EndDate           time.Time        `json:"end_date"`
+	Days              int              `json:"days"`
+	TotalWeightedLoad float64          `json:"total_weighted_load"`
+	TotalOpenTasks    int64            `json:"total_open_tasks"`
+	TotalOverdueTasks int64            `json:"total_overdue_tasks"`
+	AtRiskMembers     int              `json:"at_risk_members"`
+	Members           []MemberWorkload `json:"members"`
+}
+
+type MemberWorkload struct {
+	User           models.UserSummary   `json:"user"`
+	Role           models.WorkspaceRole `json:"role"`
+	WeightedLoad   float64              `json:"weighted_load"` // overtime 1.5x, akhir pekan/libur 1.3x
+	LoadPerDay     float64              `json:"load_per_day"`
+	ScheduledTasks int                  `json:"scheduled_tasks"` // deadline dalam rentang
+	CompletedTasks int                  `json:"completed_tasks"`
+	OvertimeTasks  int                  `json:"overtime_tasks"`
+	WeekendTasks   int                  `json:"weekend_tasks"`
+	OvertimeHours  float64              `json:"overtime_hours"`
+	WeekendHours   float64              `json:"weekend_hours"`
+	OpenTasks      int64                `json:"open_tasks"`
+	OverdueTasks   int64                `json:"overdue_tasks"`
+	OnLeaveToday   bool                 `json:"on_leave_today"`
+	UpcomingLeaves []TeamLeavePeriod    `json:"upcoming_leaves"`
+	BurnoutSignals []string             `json:"burnout_signals"`
+	RiskLevel      string               `json:"risk_level"` // low | medium | high
+}
+
+// TeamLeavePeriod rentang cuti member yang terlihat oleh manager workspace
+type TeamLeavePeriod struct {
+	StartDate time.Time           `json:"start_date"`
+	EndDate   time.Time           `json:"end_date"`
+	HalfDay   models.LeaveHalfDay `json:"half_day,omitempty"`
+}
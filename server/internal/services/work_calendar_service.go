@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+)
+
+// maxDeadlineShiftDays batas pencarian hari kerja terdekat (mis. cuti panjang)
+const maxDeadlineShiftDays = 60
+
+// WorkCalendarService menentukan hari kerja user berdasarkan WorkDays, holiday dan cuti
+type WorkCalendarService struct {
+	holidayService *HolidayService
+	leaveService   *LeaveService
+	userRepo       *repository.UserRepository
+}
+
+func NewWorkCalendarService(
+	holidayService *HolidayService,
+	leaveService *LeaveService,
+	userRepo *repository.UserRepository,
+) *WorkCalendarService {
+	return &WorkCalendarService{
+		holidayService: holidayService,
+		leaveService:   leaveService,
+		userRepo:       userRepo,
+	}
+}
+
+// NonWorkReason alasan sebuah tanggal bukan hari kerja ("" = hari kerja)
+func (s *WorkCalendarService) NonWorkReason(userID string, date time.Time, workDaysConfig map[string]interface{}) string {
+	if !IsConfiguredWorkDay(workDaysConfig, date) {
+		return "hari non-kerja"
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	if s.holidayService != nil {
+		if holidays, err := s.holidayService.GetHolidaysByDateRange(userID, day, day); err == nil && len(holidays) > 0 {
+			return holidays[0].Name
+		}
+	}
+
+	if s.leaveService != nil {
+		if onLeave, err := s.leaveService.IsOnLeave(userID, day); err == nil && onLeave {
+			return "cuti"
+		}
+	}
+
+	return ""
+}
+
+// ResolvePolicy policy task, fallback ke setting user
+func (s *WorkCalendarService) ResolvePolicy(user *models.User, taskPolicy *models.DeadlineShiftPolicy) models.DeadlineShiftPolicy {
+	if taskPolicy != nil && taskPolicy.IsValid() {
+		return *taskPolicy
+	}
+	if user != nil && user.DeadlineShiftPolicy.IsValid() {
+		return user.DeadlineShiftPolicy
+	}
+	return models.DeadlineShiftKeep
+}
+
+// ShiftDeadline menggeser deadline sesuai policy jika jatuh di hari libur/cuti/non-kerja.
+// Jam deadline dipertahankan. Mengembalikan nil jika tidak ada pergeseran.
+func (s *WorkCalendarService) ShiftDeadline(userID string, deadline time.Time, taskPolicy *models.DeadlineShiftPolicy) *models.DeadlineShift {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil
+	}
+
+	policy := s.ResolvePolicy(user, taskPolicy)
+	if policy == models.DeadlineShiftKeep {
+		return nil
+	}
+
+	workDaysConfig := ParseWorkDaysConfig(user.WorkDays)
+
+	reason := s.NonWorkReason(userID, deadline, workDaysConfig)
+	if reason == "" {
+		return nil
+	}
+
+	step := 1
+	direction := "hari kerja berikutnya"
+	if policy == models.DeadlineShiftPrevious {
+		step = -1
+		direction = "hari kerja sebelumnya"
+	}
+
+	candidate := deadline
+	for i := 0; i < maxDeadlineShiftDays; i++ {
+		candidate = candidate.AddDate(0, 0, step)
+		if s.NonWorkReason(userID, candidate, workDaysConfig) != "" {
+			continue
+		}
+
+		return &models.DeadlineShift{
+			From:   deadline,
+			To:     candidate,
+			Policy: policy,
+			Reason: reason,
+			Note: fmt.Sprintf("Deadline dipindah dari %s (%s) ke %s (%s)",
+				deadline.Format("02 Jan 2006"), reason, candidate.Format("02 Jan 2006"), direction),
+		}
+	}
+
+	return nil
+}
+
+// NextWorkDay hari kerja pertama setelah tanggal from (jam dipertahankan)
+func (s *WorkCalendarService) NextWorkDay(userID string, from time.Time) (time.Time, bool) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return from, false
+	}
+	workDaysConfig := ParseWorkDaysConfig(user.WorkDays)
+
+	candidate := from
+	for i := 0; i < maxDeadlineShiftDays; i++ {
+		candidate = candidate.AddDate(0, 0, 1)
+		if s.NonWorkReason(userID, candidate, workDaysConfig) == "" {
+			return candidate, true
+		}
+	}
+
+	return from, false
+}
+
+// IsConfiguredWorkDay mengecek WorkDays user (key "0"=Senin ... "6"=Minggu).
+// Tanpa konfigurasi, Senin-Jumat dianggap hari kerja.
+func IsConfiguredWorkDay(workDaysConfig map[string]interface{}, date time.Time) bool {
+	dayIndex := mondayIndex(date.Weekday())
+
+	if len(workDaysConfig) == 0 {
+		return dayIndex < 5
+	}
+
+	dayMap, ok := workDaysConfig[fmt.Sprintf("%d", dayIndex)].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	isWorkDay, _ := dayMap["is_work_day"].(bool)
+	return isWorkDay
+}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/workradar/server/internal/models"
 	"github.com/workradar/server/internal/repository"
 )
 
@@ -38,7 +40,7 @@ func (s *WorkloadService) GetDailyWorkload(userID string) (*WorkloadResponse, er
 		endOfDay := startOfDay.Add(24*time.Hour - time.Second)
 
 		// Count tasks di hari ini
-		tasks, _ := s.taskRepo.FindByUserIDAndDateRange(userID, startOfDay, endOfDay)
+		tasks, _ := s.taskRepo.FindWorkloadByUserIDAndDateRange(userID, startOfDay, endOfDay)
 
 		// Get day name
 		dayName := dayLabels[int(date.Weekday())]
@@ -75,7 +77,7 @@ func (s *WorkloadService) GetWeeklyWorkload(userID string) (*WorkloadResponse, e
 		endOfWeek = time.Date(endOfWeek.Year(), endOfWeek.Month(), endOfWeek.Day(), 23, 59, 59, 0, endOfWeek.Location())
 
 		// Count tasks minggu ini
-		tasks, _ := s.taskRepo.FindByUserIDAndDateRange(userID, startOfWeek, endOfWeek)
+		tasks, _ := s.taskRepo.FindWorkloadByUserIDAndDateRange(userID, startOfWeek, endOfWeek)
 
 		data = append(data, WorkloadData{
 			Label: "Week " + string(rune('1'+3-i)), // "Week 1", "Week 2", ...
@@ -101,7 +103,7 @@ func (s *WorkloadService) GetMonthlyWorkload(userID string) (*WorkloadResponse,
 		endOfMonth := startOfMonth.AddDate(0, 1, 0).Add(-time.Second)
 
 		// Count tasks bulan ini
-		tasks, _ := s.taskRepo.FindByUserIDAndDateRange(userID, startOfMonth, endOfMonth)
+		tasks, _ := s.taskRepo.FindWorkloadByUserIDAndDateRange(userID, startOfMonth, endOfMonth)
 
 		monthName := monthNames[date.Month()-1]
 
@@ -139,11 +141,35 @@ func (s *WorkloadService) CalculateWorkloadWithMultipliers(
 ) (*WorkloadStats, error) {
 
 	// Get all completed tasks in date range
-	tasks, err := s.taskRepo.FindByUserIDAndDateRange(userID, startDate, endDate)
+	tasks, err := s.taskRepo.FindWorkloadByUserIDAndDateRange(userID, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.calculateStats(tasks, workDaysConfig, holidays), nil
+}
+
+// CalculateWorkspaceWorkload sama seperti CalculateWorkloadWithMultipliers, hanya task di workspace tertentu
+func (s *WorkloadService) CalculateWorkspaceWorkload(
+	workspaceID, userID string,
+	startDate, endDate time.Time,
+	workDaysConfig map[string]interface{},
+	holidays []time.Time,
+) (*WorkloadStats, error) {
+	tasks, err := s.taskRepo.FindWorkspaceWorkloadByDateRange(workspaceID, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.calculateStats(tasks, workDaysConfig, holidays), nil
+}
+
+// calculateStats menghitung beban kerja tertimbang dari task dalam rentang
+func (s *WorkloadService) calculateStats(
+	tasks []models.Task,
+	workDaysConfig map[string]interface{},
+	holidays []time.Time,
+) *WorkloadStats {
 	stats := &WorkloadStats{
 		TotalTasks: len(tasks),
 	}
@@ -155,7 +181,10 @@ func (s *WorkloadService) CalculateWorkloadWithMultipliers(
 		}
 
 		completedAt := *task.CompletedAt
-		categoryName := task.Category.Name
+		categoryName := ""
+		if task.Category != nil {
+			categoryName = task.Category.Name
+		}
 
 		// Only apply multipliers for "Kerja" category
 		if categoryName != "Kerja" {
@@ -179,7 +208,7 @@ func (s *WorkloadService) CalculateWorkloadWithMultipliers(
 		}
 	}
 
-	return stats, nil
+	return stats
 }
 
 // isWeekendOrHoliday checks if date is weekend or holiday
@@ -283,6 +312,19 @@ func (s *WorkloadService) isOvertimeWork(
 	return completedTime.Before(startTime) || completedTime.After(endTime)
 }
 
+// ParseWorkDaysConfig decodes User.WorkDays into the map format used by the multiplier helpers.
+// Returns an empty map when the user hasn't configured work hours.
+func ParseWorkDaysConfig(workDays *string) map[string]interface{} {
+	config := map[string]interface{}{}
+	if workDays == nil || *workDays == "" {
+		return config
+	}
+	if err := json.Unmarshal([]byte(*workDays), &config); err != nil {
+		return map[string]interface{}{}
+	}
+	return config
+}
+
 // estimateTaskDuration returns estimated hours for a task
 func estimateTaskDuration(durationMinutes *int) float64 {
 	if durationMinutes == nil || *durationMinutes == 0 {
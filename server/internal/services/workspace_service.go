@@ -0,0 +1,429 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/workradar/server/internal/models"
+	"github.com/workradar/server/internal/repository"
+	"gorm.io/gorm"
+)
+
+// workspaceInvitationTTL masa berlaku undangan workspace
+const workspaceInvitationTTL = 7 * 24 * time.Hour
+
+type WorkspaceService struct {
+	workspaceRepo *repository.WorkspaceRepository
+	userRepo      *repository.UserRepository
+	emailService  *EmailService
+}
+
+func NewWorkspaceService(
+	workspaceRepo *repository.WorkspaceRepository,
+	userRepo *repository.UserRepository,
+	emailService *EmailService,
+) *WorkspaceService {
+	return &WorkspaceService{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		emailService:  emailService,
+	}
+}
+
+// ==================== MEMBERSHIP CHECKS ====================
+
+// RequireMember memastikan user adalah member workspace
+func (s *WorkspaceService) RequireMember(workspaceID, userID string) (*models.WorkspaceMember, error) {
+	member, err := s.workspaceRepo.FindMember(workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Non-member tidak perlu tahu workspace ada atau tidak
+			return nil, errors.New("workspace not found")
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// RequireManager memastikan user adalah owner/admin workspace
+func (s *WorkspaceService) RequireManager(workspaceID, userID string) (*models.WorkspaceMember, error) {
+	member, err := s.RequireMember(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.Role.CanManage() {
+		return nil, errors.New("unauthorized: requires workspace owner or admin")
+	}
+	return member, nil
+}
+
+// ==================== WORKSPACES ====================
+
+// CreateWorkspace membuat workspace baru, pembuat menjadi owner
+func (s *WorkspaceService) CreateWorkspace(userID string, data WorkspaceDTO) (*models.Workspace, error) {
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return nil, errors.New("workspace name is required")
+	}
+	if len(name) > 100 {
+		return nil, errors.New("workspace name is too long")
+	}
+
+	workspace := &models.Workspace{
+		Name:        name,
+		Description: data.Description,
+		OwnerID:     userID,
+	}
+	if err := s.workspaceRepo.CreateWithOwner(workspace); err != nil {
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// GetWorkspaces mendapatkan workspace user beserta role-nya
+func (s *WorkspaceService) GetWorkspaces(userID string) ([]WorkspaceSummary, error) {
+	workspaces, err := s.workspaceRepo.FindByMember(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]WorkspaceSummary, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		summary := WorkspaceSummary{Workspace: workspace}
+		if member, err := s.workspaceRepo.FindMember(workspace.ID, userID); err == nil {
+			summary.Role = member.Role
+		}
+		summary.MemberCount, _ = s.workspaceRepo.CountMembers(workspace.ID)
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// GetWorkspace detail workspace beserta member
+func (s *WorkspaceService) GetWorkspace(workspaceID, userID string) (*WorkspaceDetail, error) {
+	member, err := s.RequireMember(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		return nil, errors.New("workspace not found")
+	}
+
+	members, err := s.GetMembers(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WorkspaceDetail{
+		Workspace: *workspace,
+		Role:      member.Role,
+		Members:   members,
+	}, nil
+}
+
+// UpdateWorkspace memperbarui nama/deskripsi (owner/admin)
+func (s *WorkspaceService) UpdateWorkspace(workspaceID, userID string, data WorkspaceDTO) (*models.Workspace, error) {
+	if _, err := s.RequireManager(workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		return nil, errors.New("workspace not found")
+	}
+
+	if name := strings.TrimSpace(data.Name); name != "" {
+		if len(name) > 100 {
+			return nil, errors.New("workspace name is too long")
+		}
+		workspace.Name = name
+	}
+	if data.Description != nil {
+		workspace.Description = data.Description
+	}
+
+	if err := s.workspaceRepo.Update(workspace); err != nil {
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// DeleteWorkspace menghapus workspace (owner saja)
+func (s *WorkspaceService) DeleteWorkspace(workspaceID, userID string) error {
+	member, err := s.RequireMember(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role != models.WorkspaceRoleOwner {
+		return errors.New("unauthorized: only the owner can delete a workspace")
+	}
+
+	return s.workspaceRepo.Delete(workspaceID)
+}
+
+// ==================== MEMBERS ====================
+
+// GetMembers daftar member workspace
+func (s *WorkspaceService) GetMembers(workspaceID, userID string) ([]models.WorkspaceMemberResponse, error) {
+	if _, err := s.RequireMember(workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.workspaceRepo.FindMembers(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.WorkspaceMemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = member.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// UpdateMemberRole mengubah role member. Role owner hanya bisa diserahkan oleh owner
+// (owner lama menjadi admin).
+func (s *WorkspaceService) UpdateMemberRole(workspaceID, actorID, targetUserID string, role models.WorkspaceRole) error {
+	actor, err := s.RequireManager(workspaceID, actorID)
+	if err != nil {
+		return err
+	}
+	if !role.IsValid() {
+		return errors.New("invalid role, use owner, admin or member")
+	}
+
+	target, err := s.workspaceRepo.FindMember(workspaceID, targetUserID)
+	if err != nil {
+		return errors.New("member not found")
+	}
+	if target.Role == models.WorkspaceRoleOwner {
+		return errors.New("cannot change the owner's role, transfer ownership instead")
+	}
+
+	if role == models.WorkspaceRoleOwner {
+		if actor.Role != models.WorkspaceRoleOwner {
+			return errors.New("unauthorized: only the owner can transfer ownership")
+		}
+
+		workspace, err := s.workspaceRepo.FindByID(workspaceID)
+		if err != nil {
+			return errors.New("workspace not found")
+		}
+		workspace.OwnerID = targetUserID
+		if err := s.workspaceRepo.Update(workspace); err != nil {
+			return err
+		}
+		if err := s.workspaceRepo.UpdateMemberRole(workspaceID, actorID, models.WorkspaceRoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	return s.workspaceRepo.UpdateMemberRole(workspaceID, targetUserID, role)
+}
+
+// RemoveMember mengeluarkan member, atau keluar sendiri jika target = actor
+func (s *WorkspaceService) RemoveMember(workspaceID, actorID, targetUserID string) error {
+	actor, err := s.RequireMember(workspaceID, actorID)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.workspaceRepo.FindMember(workspaceID, targetUserID)
+	if err != nil {
+		return errors.New("member not found")
+	}
+	if target.Role == models.WorkspaceRoleOwner {
+		return errors.New("owner cannot leave or be removed, transfer ownership first")
+	}
+
+	if actorID != targetUserID {
+		if !actor.Role.CanManage() {
+			return errors.New("unauthorized: requires workspace owner or admin")
+		}
+		if target.Role == models.WorkspaceRoleAdmin && actor.Role != models.WorkspaceRoleOwner {
+			return errors.New("unauthorized: only the owner can remove an admin")
+		}
+	}
+
+	return s.workspaceRepo.DeleteMember(workspaceID, targetUserID)
+}
+
+// ==================== INVITATIONS ====================
+
+// InviteMember mengundang user lewat email (owner/admin)
+func (s *WorkspaceService) InviteMember(workspaceID, actorID string, data InviteMemberDTO) (*models.WorkspaceInvitation, error) {
+	if _, err := s.RequireManager(workspaceID, actorID); err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(data.Email))
+	if email == "" || !strings.Contains(email, "@") {
+		return nil, errors.New("valid email is required")
+	}
+
+	role := data.Role
+	if role == "" {
+		role = models.WorkspaceRoleMember
+	}
+	if role != models.WorkspaceRoleAdmin && role != models.WorkspaceRoleMember {
+		return nil, errors.New("invalid role, use admin or member")
+	}
+
+	if user, err := s.userRepo.FindByEmail(email); err == nil {
+		if _, err := s.workspaceRepo.FindMember(workspaceID, user.ID); err == nil {
+			return nil, errors.New("user is already a member")
+		}
+	}
+
+	if _, err := s.workspaceRepo.FindPendingInvitation(workspaceID, email); err == nil {
+		return nil, errors.New("invitation already sent to this email")
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		return nil, errors.New("workspace not found")
+	}
+
+	invitation := &models.WorkspaceInvitation{
+		WorkspaceID: workspaceID,
+		Email:       email,
+		Role:        role,
+		Status:      models.InvitationPending,
+		InvitedBy:   actorID,
+		ExpiresAt:   time.Now().Add(workspaceInvitationTTL),
+	}
+	if err := s.workspaceRepo.CreateInvitation(invitation); err != nil {
+		return nil, err
+	}
+
+	if s.emailService != nil {
+		inviterName := "Seseorang"
+		if inviter, err := s.userRepo.FindByID(actorID); err == nil {
+			inviterName = inviter.Username
+		}
+		if err := s.emailService.SendWorkspaceInvitation(email, inviterName, workspace.Name, string(role), invitation.ExpiresAt); err != nil {
+			log.Printf("⚠️ Failed to send workspace invitation to %s: %v", email, err)
+		}
+	}
+
+	return invitation, nil
+}
+
+// GetInvitations daftar undangan workspace (owner/admin)
+func (s *WorkspaceService) GetInvitations(workspaceID, actorID string) ([]models.WorkspaceInvitation, error) {
+	if _, err := s.RequireManager(workspaceID, actorID); err != nil {
+		return nil, err
+	}
+	return s.workspaceRepo.FindInvitationsByWorkspace(workspaceID)
+}
+
+// RevokeInvitation membatalkan undangan pending
+func (s *WorkspaceService) RevokeInvitation(workspaceID, actorID, invitationID string) error {
+	if _, err := s.RequireManager(workspaceID, actorID); err != nil {
+		return err
+	}
+
+	invitation, err := s.workspaceRepo.FindInvitationByID(invitationID)
+	if err != nil || invitation.WorkspaceID != workspaceID {
+		return errors.New("invitation not found")
+	}
+	if invitation.Status != models.InvitationPending {
+		return errors.New("invitation is no longer pending")
+	}
+
+	now := time.Now()
+	invitation.Status = models.InvitationRevoked
+	invitation.RespondedAt = &now
+	return s.workspaceRepo.UpdateInvitation(invitation)
+}
+
+// GetMyInvitations undangan pending untuk email user
+func (s *WorkspaceService) GetMyInvitations(userID string) ([]models.WorkspaceInvitation, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	return s.workspaceRepo.FindPendingInvitationsByEmail(strings.ToLower(user.Email))
+}
+
+// RespondInvitation menerima / menolak undangan; email user harus sama dengan email undangan
+func (s *WorkspaceService) RespondInvitation(invitationID, userID string, accept bool) (*models.WorkspaceInvitation, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	invitation, err := s.workspaceRepo.FindInvitationByID(invitationID)
+	if err != nil || !strings.EqualFold(invitation.Email, user.Email) {
+		return nil, errors.New("invitation not found")
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, errors.New("invitation is no longer pending")
+	}
+	if invitation.IsExpired() {
+		return nil, errors.New("invitation has expired")
+	}
+
+	now := time.Now()
+	invitation.RespondedAt = &now
+
+	if !accept {
+		invitation.Status = models.InvitationDeclined
+		if err := s.workspaceRepo.UpdateInvitation(invitation); err != nil {
+			return nil, err
+		}
+		return invitation, nil
+	}
+
+	invitation.Status = models.InvitationAccepted
+	if _, err := s.workspaceRepo.FindMember(invitation.WorkspaceID, userID); err == nil {
+		// Sudah member (mis. diundang dua kali): cukup tandai diterima
+		if err := s.workspaceRepo.UpdateInvitation(invitation); err != nil {
+			return nil, err
+		}
+		return invitation, nil
+	}
+
+	member := &models.WorkspaceMember{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      userID,
+		Role:        invitation.Role,
+	}
+	if err := s.workspaceRepo.AcceptInvitation(invitation, member); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// DTOs
+
+type WorkspaceDTO struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+type InviteMemberDTO struct {
+	Email string               `json:"email"`
+	Role  models.WorkspaceRole `json:"role"`
+}
+
+type WorkspaceSummary struct {
+	models.Workspace
+	Role        models.WorkspaceRole `json:"role"`
+	MemberCount int64                `json:"member_count"`
+}
+
+type WorkspaceDetail struct {
+	models.Workspace
+	Role    models.WorkspaceRole             `json:"role"`
+	Members []models.WorkspaceMemberResponse `json:"members"`
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// SimplePDF is a minimal text-only PDF builder (A4, Helvetica).
+// It's enough for tabular reports without pulling in a PDF dependency.
+type SimplePDF struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+	y       float64
+}
+
+const (
+	pdfPageWidth  = 595.0 // A4 in points
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+)
+
+// NewSimplePDF creates an empty document with one page
+func NewSimplePDF() *SimplePDF {
+	p := &SimplePDF{}
+	p.newPage()
+	return p
+}
+
+func (p *SimplePDF) newPage() {
+	p.current = &bytes.Buffer{}
+	p.pages = append(p.pages, p.current)
+	p.y = pdfPageHeight - pdfMargin
+}
+
+// AddLine writes a single line of text; bold selects Helvetica-Bold
+func (p *SimplePDF) AddLine(text string, size float64, bold bool) {
+	lineHeight := size * 1.4
+	if p.y-lineHeight < pdfMargin {
+		p.newPage()
+	}
+	p.y -= lineHeight
+
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.current, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, pdfMargin, p.y, pdfEscape(text))
+}
+
+// AddSpace adds vertical whitespace
+func (p *SimplePDF) AddSpace(height float64) {
+	p.y -= height
+	if p.y < pdfMargin {
+		p.newPage()
+	}
+}
+
+// Bytes renders the document
+func (p *SimplePDF) Bytes() []byte {
+	var out bytes.Buffer
+	offsets := []int{}
+
+	writeObj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// 1: catalog, 2: pages, 3-4: fonts, then page+content pairs
+	pageCount := len(p.pages)
+	kids := make([]string, pageCount)
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount))
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range p.pages {
+		contentRef := 6 + i*2
+		writeObj(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, contentRef,
+		))
+		writeObj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// pdfEscape escapes PDF string delimiters and drops characters
+// outside Latin-1 (emoji etc.) which the standard fonts can't render
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r < 32:
+			continue
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		}
+	}
+	return b.String()
+}
@@ -23,6 +23,8 @@ type Holiday struct {
 	UpdatedAt   time.Time
 }
 
+// Deprecated: server mengimpor data holiday dari provider bundled (job holiday_refresh);
+// baris dari seed ini (source kosong) diganti saat refresh berikutnya.
 func main() {
 	godotenv.Load(".env.production")
 